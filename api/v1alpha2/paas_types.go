@@ -7,15 +7,20 @@ See LICENSE.md for details.
 package v1alpha2
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/belastingdienst/opr-paas/v5/pkg/fields"
 	"github.com/belastingdienst/opr-paas/v5/pkg/groups"
 	paasquota "github.com/belastingdienst/opr-paas/v5/pkg/quota"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // Definitions to manage status conditions
@@ -27,8 +32,31 @@ const (
 	// TypeDegradedPaas represents the status used when the Paas is deleted and the finalizer operations are yet to
 	// occur.
 	TypeDegradedPaas = "Degraded"
+	// TypeQuotaPressurePaas represents the status used when the usage of one or more quotas of the Paas reaches the
+	// quota pressure threshold of the PaasConfig
+	TypeQuotaPressurePaas = "QuotaPressure"
 )
 
+// PlanAnnotation can be set to "true" on a Paas to reconcile it in plan mode. In plan mode, the changes that would be
+// applied are written to the status of the Paas, and no changes are applied to the cluster.
+const PlanAnnotation = "paas.cpet.belastingdienst.nl/plan"
+
+// DebugAnnotation can be set to "true" on a Paas to enable debug logging for all components while reconciling this
+// Paas, so that a single Paas can be debugged without enabling debug logging for all Paas'es.
+const DebugAnnotation = "paas.cpet.belastingdienst.nl/debug"
+
+// QuotaApprovalAnnotationPrefix is the prefix of an annotation which is set on a Paas, followed by the UID of a
+// QuotaRequest, in the same update which applies the QuotaRequest to the quota of the Paas. It holds the approval
+// (as json) until it is recorded in the status of the Paas, so that a QuotaRequest is applied only once.
+const QuotaApprovalAnnotationPrefix = "paas.cpet.belastingdienst.nl/quota-approval-"
+
+// PendingDeletionLabel is set to "true" on a namespace which is no longer required by a Paas, and which is kept until
+// it is deleted by the DelayedDelete namespace retention policy
+const PendingDeletionLabel = "paas.cpet.belastingdienst.nl/pending-deletion"
+
+// PendingDeletionSinceAnnotation holds the time (RFC3339) at which a namespace was marked as pending deletion
+const PendingDeletionSinceAnnotation = "paas.cpet.belastingdienst.nl/pending-deletion-since"
+
 // PaasSpec defines the desired state of Paas
 type PaasSpec struct {
 	// Deprecated, the requestor implementation will be replaced by an annotation and Go Template functionality
@@ -62,6 +90,39 @@ type PaasSpec struct {
 	// Indicated by which 3rd party Paas this Paas is managed
 	// +kubebuilder:validation:Optional
 	ManagedByPaas string `json:"managedByPaas,omitempty"`
+
+	// NamespaceRetention overrides `PaasConfig.spec.namespaceRetention` for namespaces which are no longer required
+	// by this Paas. The policy must be one of `PaasConfig.spec.allowedNamespaceRetentionPolicies`.
+	// +kubebuilder:validation:Optional
+	NamespaceRetention *NamespaceRetention `json:"namespaceRetention,omitempty"`
+
+	// DeletionPolicy overrides `PaasConfig.spec.deletionPolicy` for the resources of this Paas when it is deleted
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +kubebuilder:validation:Optional
+	DeletionPolicy PaasDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// GetNamespaceRetention returns the namespace retention of this Paas, which defaults to the namespace retention of
+// the PaasConfig. The namespace retention of the Paas is ignored when its policy is not allowed by the PaasConfig.
+func (p Paas) GetNamespaceRetention(config PaasConfig) NamespaceRetention {
+	if p.Spec.NamespaceRetention != nil &&
+		slices.Contains(config.Spec.AllowedNamespaceRetentionPolicies, p.Spec.NamespaceRetention.GetPolicy()) {
+		return *p.Spec.NamespaceRetention
+	}
+	return config.Spec.NamespaceRetention
+}
+
+// GetDeletionPolicy returns the deletion policy of this Paas, which defaults to the deletion policy of the PaasConfig,
+// and to PaasDeletionPolicyDelete when neither is set
+func (p Paas) GetDeletionPolicy(config PaasConfig) PaasDeletionPolicy {
+	switch {
+	case p.Spec.DeletionPolicy != "":
+		return p.Spec.DeletionPolicy
+	case config.Spec.DeletionPolicy != "":
+		return config.Spec.DeletionPolicy
+	default:
+		return PaasDeletionPolicyDelete
+	}
 }
 
 // PaasCapability holds all information for a capability
@@ -153,6 +214,59 @@ type PaasGroup struct {
 	// List of roles, as defined in the `PaasConfig` which the users in this group get assigned via a rolebinding.
 	// +kubebuilder:validation:Optional
 	Roles []string `json:"roles,omitempty"`
+	// Expiry timestamps for users in `group.users`, by user name. A user is removed from the group after its expiry.
+	// +kubebuilder:validation:Optional
+	UserExpiries map[string]metav1.Time `json:"userExpiries,omitempty"`
+	// Expiry timestamps for roles in `group.roles`, by role name. A role is no longer assigned to the users in this
+	// group after its expiry.
+	// +kubebuilder:validation:Optional
+	RoleExpiries map[string]metav1.Time `json:"roleExpiries,omitempty"`
+	// Service accounts which are bound directly in the RoleBindings for the roles of this group, in all namespaces
+	// this group has access to. Service accounts must be allowed by the `paas.serviceAccountSubject` validation in
+	// the `PaasConfig`.
+	// +kubebuilder:validation:Optional
+	ServiceAccounts []PaasServiceAccount `json:"serviceAccounts,omitempty"`
+	// Users which are bound directly (as User subjects, rather than as members of the group) in the RoleBindings for
+	// the roles of this group, in all namespaces this group has access to. Users must be allowed by the
+	// `paas.userSubject` validation in the `PaasConfig`.
+	// +kubebuilder:validation:Optional
+	UserSubjects []string `json:"userSubjects,omitempty"`
+}
+
+// PaasServiceAccount refers to a service account which is bound in the RoleBindings for the roles of a group
+type PaasServiceAccount struct {
+	// Name of the service account
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Namespace of the service account, which must be a namespace of the same Paas. Defaults to the namespace of the
+	// RoleBinding.
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ActiveUsers returns the users of a group which have not expired at now
+func (pg PaasGroup) ActiveUsers(now time.Time) []string {
+	return activeItems(pg.Users, pg.UserExpiries, now)
+}
+
+// ActiveRoles returns the roles of a group which have not expired at now
+func (pg PaasGroup) ActiveRoles(now time.Time) []string {
+	return activeItems(pg.Roles, pg.RoleExpiries, now)
+}
+
+// activeItems returns all items which have no expiry, or an expiry after now
+func activeItems(items []string, expiries map[string]metav1.Time, now time.Time) []string {
+	if len(expiries) == 0 {
+		return items
+	}
+	var active []string
+	for _, item := range items {
+		if expiry, exists := expiries[item]; !exists || now.Before(expiry.Time) {
+			active = append(active, item)
+		}
+	}
+	return active
 }
 
 // PaasGroups hold all groups in a paas.spec.groups
@@ -178,6 +292,188 @@ type PaasStatus struct {
 	// +kubebuilder:validation:Optional
 	//revive:disable-next-line
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+	// Inventory lists all resources managed for this Paas, as derived after the last successful reconciliation
+	// +kubebuilder:validation:Optional
+	Inventory []PaasInventoryItem `json:"inventory,omitempty"`
+	// Plan lists the changes that would be applied when reconciling this Paas, and is only set when the Paas is
+	// reconciled in plan mode (see PlanAnnotation)
+	// +kubebuilder:validation:Optional
+	Plan *PaasPlan `json:"plan,omitempty"`
+	// PendingNamespaceDeletions lists all namespaces which are no longer required by this Paas, and which are kept
+	// until they are deleted by the DelayedDelete namespace retention policy
+	// +kubebuilder:validation:Optional
+	PendingNamespaceDeletions []PaasPendingNamespaceDeletion `json:"pendingNamespaceDeletions,omitempty"`
+	// UpcomingExpirations lists all users and roles in the groups of this Paas which are due to expire, in order of
+	// expiry
+	// +kubebuilder:validation:Optional
+	UpcomingExpirations []PaasExpiration `json:"upcomingExpirations,omitempty"`
+	// QuotaUsage lists the usage of all quotas which apply to this Paas, including the cluster-wide quotas it
+	// contributes to, as read after the last successful reconciliation
+	// +kubebuilder:validation:Optional
+	QuotaUsage []PaasQuotaUsage `json:"quotaUsage,omitempty"`
+	// QuotaApprovals lists the approved QuotaRequests which were applied to the quota of this Paas, oldest first
+	// +kubebuilder:validation:Optional
+	QuotaApprovals []PaasQuotaApproval `json:"quotaApprovals,omitempty"`
+}
+
+// PaasQuotaApproval describes an approved QuotaRequest which was applied to the quota of a Paas
+type PaasQuotaApproval struct {
+	// QuotaRequest is the namespace and name of the QuotaRequest
+	// +kubebuilder:validation:Required
+	QuotaRequest NamespacedName `json:"quotaRequest"`
+	// UID of the QuotaRequest, so that every QuotaRequest is applied only once
+	// +kubebuilder:validation:Required
+	UID types.UID `json:"uid"`
+	// Capability is the capability of which the quota was changed, which is empty for the quota of the Paas itself
+	// +kubebuilder:validation:Optional
+	Capability string `json:"capability,omitempty"`
+	// Previous holds the values of the requested resources before the QuotaRequest was applied
+	// +kubebuilder:validation:Optional
+	Previous paasquota.Quota `json:"previous,omitempty"`
+	// Approved holds the approved values of the requested resources
+	// +kubebuilder:validation:Required
+	Approved paasquota.Quota `json:"approved"`
+	// ApprovedBy is the approver of the QuotaRequest
+	// +kubebuilder:validation:Required
+	ApprovedBy string `json:"approvedBy"`
+	// ApprovedAt is the time at which the QuotaRequest was applied
+	// +kubebuilder:validation:Required
+	ApprovedAt metav1.Time `json:"approvedAt"`
+}
+
+// IsQuotaRequestApplied returns true when the QuotaRequest with uid is in the quota approvals of this Paas
+func (ps PaasStatus) IsQuotaRequestApplied(uid types.UID) bool {
+	return slices.ContainsFunc(ps.QuotaApprovals, func(approval PaasQuotaApproval) bool {
+		return approval.UID == uid
+	})
+}
+
+// PaasQuotaUsage describes the usage of a quota which applies to a Paas
+type PaasQuotaUsage struct {
+	// Name of the ClusterResourceQuota or ResourceQuota
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Namespace of the ResourceQuota, which is empty for a ClusterResourceQuota
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+	// Capability is the capability which the quota belongs to, which is empty for the quota of the Paas itself
+	// +kubebuilder:validation:Optional
+	Capability string `json:"capability,omitempty"`
+	// ClusterWide is true for a cluster-wide quota, which is shared with other Paas'es
+	// +kubebuilder:validation:Optional
+	ClusterWide bool `json:"clusterWide,omitempty"`
+	// Hard is the enforced quota
+	// +kubebuilder:validation:Optional
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+	// Used is the usage of the quota, which for a cluster-wide quota includes the usage of other Paas'es
+	// +kubebuilder:validation:Optional
+	Used corev1.ResourceList `json:"used,omitempty"`
+	// Contribution is the quota which this Paas contributes to a cluster-wide quota
+	// +kubebuilder:validation:Optional
+	Contribution corev1.ResourceList `json:"contribution,omitempty"`
+	// UsedByPaas is the usage of a cluster-wide quota in the namespaces of this Paas
+	// +kubebuilder:validation:Optional
+	UsedByPaas corev1.ResourceList `json:"usedByPaas,omitempty"`
+}
+
+// Pressure returns the resources (sorted by name) of which the usage is at least threshold percent of the hard quota
+func (qu PaasQuotaUsage) Pressure(threshold int) (resources []corev1.ResourceName) {
+	for resource, hard := range qu.Hard {
+		used, exists := qu.Used[resource]
+		if !exists || hard.IsZero() {
+			continue
+		}
+		if used.AsApproximateFloat64()*100 >= hard.AsApproximateFloat64()*float64(threshold) {
+			resources = append(resources, resource)
+		}
+	}
+	slices.Sort(resources)
+	return resources
+}
+
+// PaasExpiration describes a user or role in a group of a Paas which is due to expire
+type PaasExpiration struct {
+	// Group is the key of the group in the Paas
+	// +kubebuilder:validation:Required
+	Group string `json:"group"`
+	// User is the user which is removed from the group, when a user expires
+	// +kubebuilder:validation:Optional
+	User string `json:"user,omitempty"`
+	// Role is the role which is no longer assigned to the group, when a role expires
+	// +kubebuilder:validation:Optional
+	Role string `json:"role,omitempty"`
+	// ExpiresAt is the time at which the user or role expires
+	// +kubebuilder:validation:Required
+	ExpiresAt metav1.Time `json:"expiresAt"`
+}
+
+// PaasPendingNamespaceDeletion describes an obsolete namespace which is pending deletion
+type PaasPendingNamespaceDeletion struct {
+	// Name of the namespace
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// DeleteAfter is the time after which the namespace is deleted on the next reconciliation
+	// +kubebuilder:validation:Required
+	DeleteAfter metav1.Time `json:"deleteAfter"`
+}
+
+// PaasPlan holds all changes that would be applied to the cluster when reconciling a Paas
+type PaasPlan struct {
+	// ObservedGeneration is the generation of the Paas that this plan was computed for
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// WouldCreate lists all resources that would be created
+	// +kubebuilder:validation:Optional
+	WouldCreate []PaasPlanItem `json:"wouldCreate,omitempty"`
+	// WouldUpdate lists all resources that would be updated
+	// +kubebuilder:validation:Optional
+	WouldUpdate []PaasPlanItem `json:"wouldUpdate,omitempty"`
+	// WouldDelete lists all resources that would be deleted
+	// +kubebuilder:validation:Optional
+	WouldDelete []PaasPlanItem `json:"wouldDelete,omitempty"`
+}
+
+// PaasPlanItem describes a single resource that would be changed when reconciling a Paas
+type PaasPlanItem struct {
+	// Kind of the resource, e.a. Namespace, ClusterResourceQuota, Group or RoleBinding
+	// +kubebuilder:validation:Required
+	Kind string `json:"kind"`
+	// Name of the resource
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Namespace of the resource, when the resource is namespaced
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// PaasInventoryItem describes a single resource (or ClusterRoleBinding subject) managed for a Paas
+type PaasInventoryItem struct {
+	// Kind of the managed resource, e.a. Namespace, ClusterResourceQuota, Group, RoleBinding,
+	// ClusterRoleBinding or Secret
+	// +kubebuilder:validation:Required
+	Kind string `json:"kind"`
+	// APIVersion of the managed resource, which is only set for baseline resources, since they can be of any kind
+	// +kubebuilder:validation:Optional
+	APIVersion string `json:"apiVersion,omitempty"`
+	// Name of the managed resource
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Namespace of the managed resource, when the resource is namespaced
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+	// Subject is set for ClusterRoleBindings, which are shared between Paas'es, and holds the subject
+	// (Kind/Namespace/Name) which was added for this Paas
+	// +kubebuilder:validation:Optional
+	Subject string `json:"subject,omitempty"`
+	// Capability is the name of the capability this resource originates from (if any)
+	// +kubebuilder:validation:Optional
+	Capability string `json:"capability,omitempty"`
+	// PaasNS is the namespaced name (namespace/name) of the PaasNS this resource originates from (if any)
+	// +kubebuilder:validation:Optional
+	PaasNS string `json:"paasns,omitempty"`
+	// Hash is a hash of the desired spec of the resource
+	// +kubebuilder:validation:Optional
+	Hash string `json:"hash,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -321,6 +617,46 @@ func (pgs PaasGroups) Roles() map[string][]string {
 	return roles
 }
 
+// ActiveRoles returns a map of groupKeys with the roles defined within that groupKey which have not expired at now.
+// A group without roles is returned with an empty list (which maps to the default roles), but a group of which all
+// roles have expired is left out, so that it is no longer bound at all.
+func (pgs PaasGroups) ActiveRoles(now time.Time) map[string][]string {
+	roles := make(map[string][]string)
+	for groupKey, group := range pgs {
+		active := group.ActiveRoles(now)
+		if len(group.Roles) > 0 && len(active) == 0 {
+			continue
+		}
+		roles[groupKey] = active
+	}
+	return roles
+}
+
+// UpcomingExpirations returns all expiries of users and roles in the groups which lie after now, in order of expiry
+func (pgs PaasGroups) UpcomingExpirations(now time.Time) (expirations []PaasExpiration) {
+	for groupKey, group := range pgs {
+		for user, expiry := range group.UserExpiries {
+			if slices.Contains(group.Users, user) && now.Before(expiry.Time) {
+				expirations = append(expirations, PaasExpiration{Group: groupKey, User: user, ExpiresAt: expiry})
+			}
+		}
+		for role, expiry := range group.RoleExpiries {
+			if slices.Contains(group.Roles, role) && now.Before(expiry.Time) {
+				expirations = append(expirations, PaasExpiration{Group: groupKey, Role: role, ExpiresAt: expiry})
+			}
+		}
+	}
+	slices.SortFunc(expirations, func(a, b PaasExpiration) int {
+		return cmp.Or(
+			a.ExpiresAt.Compare(b.ExpiresAt.Time),
+			cmp.Compare(a.Group, b.Group),
+			cmp.Compare(a.User, b.User),
+			cmp.Compare(a.Role, b.Role),
+		)
+	})
+	return expirations
+}
+
 // Keys can return a list of all keys in paas.spec.groups
 func (pgs PaasGroups) Keys() (keys []string) {
 	for key := range pgs {
@@ -1,8 +1,12 @@
 package v1alpha2_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
@@ -23,6 +27,121 @@ var _ = Describe("PaasTypes", func() {
 			It("should have name properly set", func() {
 				Expect(paas.Name).To(Equal(paasName))
 			})
+			It("should have the Delete deletion policy", func() {
+				Expect(paas.GetDeletionPolicy(v1alpha2.PaasConfig{})).To(Equal(v1alpha2.PaasDeletionPolicyDelete))
+			})
+		})
+	})
+	Describe("Deletion policy", func() {
+		var config v1alpha2.PaasConfig
+		BeforeEach(func() {
+			config = v1alpha2.PaasConfig{
+				Spec: v1alpha2.PaasConfigSpec{DeletionPolicy: v1alpha2.PaasDeletionPolicyRetain},
+			}
+		})
+		It("should default to the deletion policy of the PaasConfig", func() {
+			Expect(paas.GetDeletionPolicy(config)).To(Equal(v1alpha2.PaasDeletionPolicyRetain))
+		})
+		It("should be overridden by the deletion policy of the Paas", func() {
+			paas.Spec.DeletionPolicy = v1alpha2.PaasDeletionPolicyDelete
+			Expect(paas.GetDeletionPolicy(config)).To(Equal(v1alpha2.PaasDeletionPolicyDelete))
+		})
+	})
+	Describe("Namespace retention", func() {
+		var config v1alpha2.PaasConfig
+		orphan := v1alpha2.NamespaceRetention{Policy: v1alpha2.NamespaceRetentionOrphan}
+		BeforeEach(func() {
+			config = v1alpha2.PaasConfig{Spec: v1alpha2.PaasConfigSpec{
+				NamespaceRetention: v1alpha2.NamespaceRetention{
+					Policy:     v1alpha2.NamespaceRetentionDelayedDelete,
+					DelayHours: 24,
+				},
+			}}
+			paas.Spec.NamespaceRetention = &orphan
+		})
+		It("should ignore the namespace retention of the Paas when its policy is not allowed", func() {
+			Expect(paas.GetNamespaceRetention(config)).To(Equal(config.Spec.NamespaceRetention))
+		})
+		It("should be overridden by the namespace retention of the Paas when its policy is allowed", func() {
+			config.Spec.AllowedNamespaceRetentionPolicies = []v1alpha2.NamespaceRetentionPolicy{
+				v1alpha2.NamespaceRetentionOrphan,
+			}
+			Expect(paas.GetNamespaceRetention(config)).To(Equal(orphan))
+		})
+	})
+	Describe("Group expiries", func() {
+		var (
+			now     = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+			expired = metav1.NewTime(now.Add(-time.Hour))
+			soon    = metav1.NewTime(now.Add(time.Hour))
+			later   = metav1.NewTime(now.Add(2 * time.Hour))
+			groups  v1alpha2.PaasGroups
+		)
+		BeforeEach(func() {
+			groups = v1alpha2.PaasGroups{
+				"contractors": {
+					Users:        []string{"u1", "u2", "u3"},
+					UserExpiries: map[string]metav1.Time{"u1": expired, "u2": later, "removed": soon},
+					Roles:        []string{"admin", "viewer"},
+					RoleExpiries: map[string]metav1.Time{"admin": soon},
+				},
+				"permanent": {Users: []string{"u4"}, Roles: []string{"viewer"}},
+			}
+		})
+		It("should only return users and roles which have not expired", func() {
+			Expect(groups["contractors"].ActiveUsers(now)).To(Equal([]string{"u2", "u3"}))
+			Expect(groups["contractors"].ActiveRoles(now)).To(Equal([]string{"admin", "viewer"}))
+			Expect(groups.ActiveRoles(soon.Time)).To(Equal(map[string][]string{
+				"contractors": {"viewer"},
+				"permanent":   {"viewer"},
+			}))
+		})
+		It("should list upcoming expirations in order of expiry", func() {
+			Expect(groups.UpcomingExpirations(now)).To(Equal([]v1alpha2.PaasExpiration{
+				{Group: "contractors", Role: "admin", ExpiresAt: soon},
+				{Group: "contractors", User: "u2", ExpiresAt: later},
+			}))
+			Expect(groups.UpcomingExpirations(later.Time)).To(BeEmpty())
+		})
+		It("should leave out groups of which all roles have expired", func() {
+			groups["contractors"].RoleExpiries["viewer"] = soon
+			groups["defaults"] = v1alpha2.PaasGroup{Users: []string{"u5"}}
+			Expect(groups.ActiveRoles(soon.Time)).To(Equal(map[string][]string{
+				"defaults":  nil,
+				"permanent": {"viewer"},
+			}))
+		})
+	})
+	Describe("Quota usage", func() {
+		usage := v1alpha2.PaasQuotaUsage{
+			Hard: corev1.ResourceList{
+				corev1.ResourceLimitsCPU:      resource.MustParse("2"),
+				corev1.ResourceLimitsMemory:   resource.MustParse("10Gi"),
+				corev1.ResourceRequestsCPU:    resource.MustParse("0"),
+				corev1.ResourceRequestsMemory: resource.MustParse("1Gi"),
+			},
+			Used: corev1.ResourceList{
+				corev1.ResourceLimitsCPU:    resource.MustParse("1800m"),
+				corev1.ResourceLimitsMemory: resource.MustParse("8Gi"),
+				corev1.ResourceRequestsCPU:  resource.MustParse("0"),
+			},
+		}
+		It("should return the resources of which usage reaches the threshold", func() {
+			Expect(usage.Pressure(80)).To(Equal([]corev1.ResourceName{
+				corev1.ResourceLimitsCPU,
+				corev1.ResourceLimitsMemory,
+			}))
+			Expect(usage.Pressure(90)).To(Equal([]corev1.ResourceName{corev1.ResourceLimitsCPU}))
+			Expect(usage.Pressure(95)).To(BeEmpty())
+		})
+	})
+	Describe("Quota approvals", func() {
+		status := v1alpha2.PaasStatus{
+			QuotaApprovals: []v1alpha2.PaasQuotaApproval{{UID: "approved-uid"}},
+		}
+		It("should only report recorded QuotaRequests as applied", func() {
+			Expect(status.IsQuotaRequestApplied("approved-uid")).To(BeTrue())
+			Expect(status.IsQuotaRequestApplied("other-uid")).To(BeFalse())
 		})
 	})
 })
@@ -11,8 +11,11 @@ package v1alpha2
 
 import (
 	"reflect"
+	"slices"
+	"time"
 
 	paasquota "github.com/belastingdienst/opr-paas/v5/pkg/quota"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -104,6 +107,11 @@ type PaasConfigSpec struct {
 	// +kubebuilder:validation:Optional
 	RoleMappings ConfigRoleMappings `json:"rolemappings"`
 
+	// Role mappings which only apply in selected namespaces of a Paas. In a namespace which is selected by scoped role
+	// mappings for a role, these take precedence over the role mappings for that role.
+	// +kubebuilder:validation:Optional
+	ScopedRoleMappings ConfigScopedRoleMappings `json:"scopedRoleMappings,omitempty"`
+
 	// Enable, disable, and tune operator features
 	// +kubebuilder:validation:Optional
 	FeatureFlags ConfigFeatureFlags `json:"feature_flags"`
@@ -115,6 +123,417 @@ type PaasConfigSpec struct {
 	// With templating Administrators can define labels and generic custom fields to be applied on sub resources
 	// +kubebuilder:validation:Optional
 	Templating ConfigTemplatingItems `json:"templating,omitempty"`
+
+	// Limits to the namespaces which can be created for a Paas through (nested) PaasNS'es
+	// +kubebuilder:validation:Optional
+	PaasNSLimits ConfigPaasNSLimits `json:"paasNsLimits,omitempty"`
+
+	// What to do with namespaces which are no longer required by a Paas. Can be overridden per Paas with
+	// `Paas.spec.namespaceRetention`. Defaults to deleting them.
+	// +kubebuilder:validation:Optional
+	NamespaceRetention NamespaceRetention `json:"namespaceRetention,omitempty"`
+
+	// The namespace retention policies which a Paas may set in `Paas.spec.namespaceRetention`. By default, a Paas
+	// cannot override the namespace retention of the PaasConfig.
+	// +kubebuilder:validation:items:Enum=Delete;Orphan;DelayedDelete
+	// +kubebuilder:validation:Optional
+	AllowedNamespaceRetentionPolicies []NamespaceRetentionPolicy `json:"allowedNamespaceRetentionPolicies,omitempty"`
+
+	// What to do with the resources of a Paas when the Paas is deleted, which is either Delete or Retain. Can be
+	// overridden per Paas with `Paas.spec.deletionPolicy`. Defaults to Delete.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +kubebuilder:validation:Optional
+	DeletionPolicy PaasDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Baseline resources (e.g. LimitRanges or NetworkPolicies) which are applied in the namespaces of every Paas.
+	// The key is the name of the baseline resource, which is used to label the resulting resources.
+	// +kubebuilder:validation:Optional
+	BaselineResources map[string]ConfigBaselineResource `json:"baselineResources,omitempty"`
+
+	// Settings for the NetworkPolicies which isolate the namespaces of a Paas, when enabled with the
+	// `network_isolation` feature flag
+	// +kubebuilder:validation:Optional
+	NetworkIsolation ConfigNetworkIsolation `json:"networkIsolation,omitempty"`
+
+	// The backend which enforces the quotas of a Paas. Defaults to OpenShift ClusterResourceQuotas.
+	// +kubebuilder:validation:Optional
+	QuotaBackend ConfigQuotaBackend `json:"quotaBackend,omitempty"`
+
+	// The backend which provides the groups of a Paas. Defaults to OpenShift Groups.
+	// +kubebuilder:validation:Optional
+	GroupBackend ConfigGroupBackend `json:"groupBackend,omitempty"`
+
+	// The resource which the LDAP queries of the groups of all Paas'es are written to, e.g. to be used as the
+	// whitelist of an LDAP group sync. When not set, the group sync configuration is not maintained.
+	// +kubebuilder:validation:Optional
+	GroupSync *ConfigGroupSync `json:"groupSync,omitempty"`
+
+	// Limits for the expiries of users and roles in the groups of a Paas
+	// +kubebuilder:validation:Optional
+	GroupExpiry ConfigGroupExpiry `json:"groupExpiry,omitempty"`
+
+	// Settings for reporting the usage of the quotas of a Paas in its status
+	// +kubebuilder:validation:Optional
+	QuotaUsage ConfigQuotaUsage `json:"quotaUsage,omitempty"`
+
+	// Settings for QuotaRequests, with which tenants request extra quota for a Paas. QuotaRequests are denied by the
+	// webhook when no approver group is set.
+	// +kubebuilder:validation:Optional
+	QuotaRequests ConfigQuotaRequests `json:"quotaRequests,omitempty"`
+
+	// How the service accounts of capabilities are bound to the roles of their permissions, which is either Shared
+	// (one ClusterRoleBinding per role for all Paas'es) or PerPaas (one ClusterRoleBinding per Paas and role, owned
+	// by the Paas). Changing the layout migrates the service accounts of every Paas when it is reconciled.
+	// Defaults to Shared.
+	// +kubebuilder:validation:Enum=Shared;PerPaas
+	// +kubebuilder:validation:Optional
+	ClusterRoleBindingLayout ConfigClusterRoleBindingLayout `json:"clusterRoleBindingLayout,omitempty"`
+}
+
+// ConfigClusterRoleBindingLayout defines how the service accounts of capabilities are bound to the roles of their
+// permissions
+type ConfigClusterRoleBindingLayout string
+
+const (
+	// ConfigClusterRoleBindingLayoutShared binds the service accounts of all Paas'es in one ClusterRoleBinding per role
+	ConfigClusterRoleBindingLayoutShared ConfigClusterRoleBindingLayout = "Shared"
+	// ConfigClusterRoleBindingLayoutPerPaas binds the service accounts of a Paas in a ClusterRoleBinding per Paas and
+	// role, which is owned by the Paas
+	ConfigClusterRoleBindingLayoutPerPaas ConfigClusterRoleBindingLayout = "PerPaas"
+)
+
+// ConfigGroupExpiry limits the expiries of users and roles in the groups of a Paas
+type ConfigGroupExpiry struct {
+	// The maximum number of hours that the expiry of a user or role may lie in the future. Expiries beyond this window
+	// are denied by the Paas webhook. Defaults to 0, which means no limit.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Optional
+	MaxHours int `json:"maxHours,omitempty"`
+}
+
+// MaxWindow returns the time that the expiry of a user or role may lie in the future, and 0 when there is no limit
+func (ge ConfigGroupExpiry) MaxWindow() time.Duration {
+	return time.Duration(ge.MaxHours) * time.Hour
+}
+
+// ConfigQuotaUsage configures reporting the usage of the quotas of a Paas in its status
+type ConfigQuotaUsage struct {
+	// The percentage of a quota which, once used, sets the QuotaPressure condition on the Paas and emits a Warning
+	// event. Defaults to 0, which means that quota pressure is not reported.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=100
+	// +kubebuilder:validation:Optional
+	PressureThreshold int `json:"pressureThreshold,omitempty"`
+
+	// The number of minutes after which a Paas is reconciled again to refresh its quota usage, since usage changes
+	// do not trigger reconciliation. Defaults to 0, which means that the usage is only refreshed when the Paas is
+	// reconciled for another reason.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Optional
+	RefreshMinutes int `json:"refreshMinutes,omitempty"`
+}
+
+// RefreshInterval returns the time after which the quota usage of a Paas is refreshed, and 0 when it is not refreshed
+func (qu ConfigQuotaUsage) RefreshInterval() time.Duration {
+	return time.Duration(qu.RefreshMinutes) * time.Minute
+}
+
+// ConfigQuotaRequests configures the approval of QuotaRequests
+type ConfigQuotaRequests struct {
+	// The group (as known to the Kubernetes API server) of the users who may approve or reject QuotaRequests
+	// +kubebuilder:validation:Optional
+	ApproverGroup string `json:"approverGroup,omitempty"`
+}
+
+// Enabled returns true when QuotaRequests can be approved, which requires an approver group
+func (qr ConfigQuotaRequests) Enabled() bool {
+	return qr.ApproverGroup != ""
+}
+
+// ConfigGroupSyncKind is the kind of resource which the group sync configuration is written to
+type ConfigGroupSyncKind string
+
+const (
+	// ConfigGroupSyncConfigMap writes the group sync configuration to a ConfigMap
+	ConfigGroupSyncConfigMap ConfigGroupSyncKind = "ConfigMap"
+	// ConfigGroupSyncSecret writes the group sync configuration to a Secret
+	ConfigGroupSyncSecret ConfigGroupSyncKind = "Secret"
+)
+
+// ConfigGroupSyncDefaultKey is the key which holds the group sync configuration when no data templates are set
+const ConfigGroupSyncDefaultKey = "groups"
+
+// ConfigGroupSync defines the resource which the LDAP queries of the groups of all Paas'es are written to
+type ConfigGroupSync struct {
+	// The kind of resource, which is either ConfigMap or Secret. Defaults to ConfigMap.
+	// +kubebuilder:validation:Enum=ConfigMap;Secret
+	// +kubebuilder:validation:Optional
+	Kind ConfigGroupSyncKind `json:"kind,omitempty"`
+
+	// The name and namespace of the resource
+	// +kubebuilder:validation:Required
+	Target NamespacedName `json:"target"`
+
+	// Go templates for the data of the resource, where the key is used as data key. The PaasConfig and the groups of
+	// all Paas'es can be used as `.Config` and `.Groups`. Defaults to a `groups` key with one LDAP query per line.
+	// Other keys in the data of the resource are left alone.
+	// +kubebuilder:validation:Optional
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// GetKind returns the kind of resource, and defaults to ConfigGroupSyncConfigMap when none is set
+func (gs ConfigGroupSync) GetKind() ConfigGroupSyncKind {
+	if gs.Kind == "" {
+		return ConfigGroupSyncConfigMap
+	}
+	return gs.Kind
+}
+
+// GetData returns the data templates, and defaults to one LDAP query per line in the `groups` key when none are set
+func (gs ConfigGroupSync) GetData() map[string]string {
+	if len(gs.Data) == 0 {
+		return map[string]string{ConfigGroupSyncDefaultKey: "{{ .Groups.AsString }}"}
+	}
+	return gs.Data
+}
+
+// ConfigGroupBackendType is the kind of backend which provides the groups of a Paas
+type ConfigGroupBackendType string
+
+const (
+	// ConfigGroupBackendOpenShift creates an OpenShift Group for every group of a Paas
+	ConfigGroupBackendOpenShift ConfigGroupBackendType = "OpenShift"
+	// ConfigGroupBackendVirtual creates no groups, and binds roles to groups which are provided by the identity
+	// provider of the cluster, e.g. OIDC group claims
+	ConfigGroupBackendVirtual ConfigGroupBackendType = "Virtual"
+)
+
+// ConfigGroupUsersPolicy defines how groups with users are handled by the Virtual group backend
+type ConfigGroupUsersPolicy string
+
+const (
+	// ConfigGroupUsersReject has the webhook deny groups with users
+	ConfigGroupUsersReject ConfigGroupUsersPolicy = "Reject"
+	// ConfigGroupUsersMapToUsers binds roles to the users of a group directly, with User subjects
+	ConfigGroupUsersMapToUsers ConfigGroupUsersPolicy = "MapToUsers"
+)
+
+// ConfigGroupBackend selects the backend which provides the groups of a Paas
+type ConfigGroupBackend struct {
+	// The kind of backend which provides the groups, which is either OpenShift or Virtual. Defaults to OpenShift.
+	// +kubebuilder:validation:Enum=OpenShift;Virtual
+	// +kubebuilder:validation:Optional
+	Type ConfigGroupBackendType `json:"type,omitempty"`
+
+	// Go template for the name of a group in RoleBinding subjects with the Virtual backend. The Paas, PaasConfig and
+	// the group (with Key, Name and Query) can be used as `.Paas`, `.Config` and `.Group`. Defaults to the name of
+	// the group as it would be created by the OpenShift backend.
+	// +kubebuilder:validation:Optional
+	NameTemplate string `json:"nameTemplate,omitempty"`
+
+	// How groups with users (and without a query) are handled by the Virtual backend, which is either Reject or
+	// MapToUsers. Defaults to Reject.
+	// +kubebuilder:validation:Enum=Reject;MapToUsers
+	// +kubebuilder:validation:Optional
+	Users ConfigGroupUsersPolicy `json:"users,omitempty"`
+}
+
+// GetType returns the group backend type, and defaults to ConfigGroupBackendOpenShift when none is set
+func (gb ConfigGroupBackend) GetType() ConfigGroupBackendType {
+	if gb.Type == "" {
+		return ConfigGroupBackendOpenShift
+	}
+	return gb.Type
+}
+
+// GetUsers returns the policy for groups with users, and defaults to ConfigGroupUsersReject when none is set
+func (gb ConfigGroupBackend) GetUsers() ConfigGroupUsersPolicy {
+	if gb.Users == "" {
+		return ConfigGroupUsersReject
+	}
+	return gb.Users
+}
+
+// ConfigQuotaBackendType is the kind of resource which enforces the quotas of a Paas
+type ConfigQuotaBackendType string
+
+const (
+	// ConfigQuotaBackendClusterResourceQuota enforces every quota of a Paas with an OpenShift ClusterResourceQuota,
+	// which spans all namespaces that use the quota
+	ConfigQuotaBackendClusterResourceQuota ConfigQuotaBackendType = "ClusterResourceQuota"
+	// ConfigQuotaBackendResourceQuota enforces every quota of a Paas with a ResourceQuota in each namespace that
+	// uses the quota, which also works on clusters without ClusterResourceQuotas
+	ConfigQuotaBackendResourceQuota ConfigQuotaBackendType = "ResourceQuota"
+)
+
+// ConfigQuotaDistribution defines how a quota is distributed over the ResourceQuotas of the namespaces that use it
+type ConfigQuotaDistribution string
+
+const (
+	// ConfigQuotaDistributionEven divides a quota evenly over the namespaces that use it
+	ConfigQuotaDistributionEven ConfigQuotaDistribution = "Even"
+	// ConfigQuotaDistributionReplicate sets the full quota in every namespace that uses it
+	ConfigQuotaDistributionReplicate ConfigQuotaDistribution = "Replicate"
+)
+
+// ConfigQuotaBackend selects the backend which enforces the quotas of a Paas
+type ConfigQuotaBackend struct {
+	// The kind of resource which enforces the quotas, which is either ClusterResourceQuota or ResourceQuota.
+	// Defaults to ClusterResourceQuota.
+	// +kubebuilder:validation:Enum=ClusterResourceQuota;ResourceQuota
+	// +kubebuilder:validation:Optional
+	Type ConfigQuotaBackendType `json:"type,omitempty"`
+
+	// How a quota is distributed over the namespaces that use it with the ResourceQuota backend, which is either
+	// Even or Replicate. Defaults to Even.
+	// +kubebuilder:validation:Enum=Even;Replicate
+	// +kubebuilder:validation:Optional
+	Distribution ConfigQuotaDistribution `json:"distribution,omitempty"`
+}
+
+// GetType returns the quota backend type, and defaults to ConfigQuotaBackendClusterResourceQuota when none is set
+func (qb ConfigQuotaBackend) GetType() ConfigQuotaBackendType {
+	if qb.Type == "" {
+		return ConfigQuotaBackendClusterResourceQuota
+	}
+	return qb.Type
+}
+
+// GetDistribution returns the quota distribution, and defaults to ConfigQuotaDistributionEven when none is set
+func (qb ConfigQuotaBackend) GetDistribution() ConfigQuotaDistribution {
+	if qb.Distribution == "" {
+		return ConfigQuotaDistributionEven
+	}
+	return qb.Distribution
+}
+
+// ConfigNamespaceKind is the kind of a namespace which is managed for a Paas
+type ConfigNamespaceKind string
+
+const (
+	// ConfigNamespaceKindPaas is a namespace defined in `Paas.spec.namespaces`
+	ConfigNamespaceKindPaas ConfigNamespaceKind = "Paas"
+	// ConfigNamespaceKindCapability is the namespace of a capability
+	ConfigNamespaceKindCapability ConfigNamespaceKind = "Capability"
+	// ConfigNamespaceKindPaasNS is a namespace defined by a PaasNS
+	ConfigNamespaceKindPaasNS ConfigNamespaceKind = "PaasNS"
+)
+
+// ConfigNamespaceSelector selects namespaces of a Paas by their kind, and capability namespaces by their capability.
+// An empty selector selects all namespaces.
+type ConfigNamespaceSelector struct {
+	// The kinds of namespaces which are selected. Defaults to all kinds.
+	// +kubebuilder:validation:items:Enum=Paas;Capability;PaasNS
+	// +kubebuilder:validation:Optional
+	Kinds []ConfigNamespaceKind `json:"kinds,omitempty"`
+
+	// The capabilities of which the namespaces are selected. Only applies to capability namespaces, and defaults to
+	// all capabilities.
+	// +kubebuilder:validation:Optional
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+// Matches returns true when a namespace of the given kind, and (for capability namespaces) capability is selected
+func (cns ConfigNamespaceSelector) Matches(kind ConfigNamespaceKind, capName string) bool {
+	if len(cns.Kinds) > 0 && !slices.Contains(cns.Kinds, kind) {
+		return false
+	}
+	if kind == ConfigNamespaceKindCapability && len(cns.Capabilities) > 0 {
+		return slices.Contains(cns.Capabilities, capName)
+	}
+	return true
+}
+
+// ConfigBaselineResource is a resource which is applied in the namespaces of every Paas
+type ConfigBaselineResource struct {
+	// Go template of the manifest of the resource. The Paas, PaasConfig and the namespace (with Name, Kind and
+	// Capability) can be used as `.Paas`, `.Config` and `.Namespace`. The namespace of the resource is always set
+	// to the namespace it is applied in.
+	// +kubebuilder:validation:Required
+	Template string `json:"template"`
+
+	// The namespaces in which the resource is applied. Defaults to all namespaces of a Paas.
+	// +kubebuilder:validation:Optional
+	Namespaces ConfigNamespaceSelector `json:"namespaces,omitempty"`
+}
+
+// PaasDeletionPolicy defines what happens with the resources of a Paas when the Paas is deleted
+type PaasDeletionPolicy string
+
+const (
+	// PaasDeletionPolicyDelete deletes all resources of a Paas along with the Paas
+	PaasDeletionPolicyDelete PaasDeletionPolicy = "Delete"
+	// PaasDeletionPolicyRetain keeps the namespaces, quotas, groups, rolebindings and secrets of a Paas when the Paas
+	// is deleted, so that they can be re-adopted by a later Paas with the same name
+	PaasDeletionPolicyRetain PaasDeletionPolicy = "Retain"
+)
+
+// NamespaceRetentionPolicy defines what happens with a namespace which is no longer required by a Paas
+type NamespaceRetentionPolicy string
+
+const (
+	// NamespaceRetentionDelete deletes obsolete namespaces immediately
+	NamespaceRetentionDelete NamespaceRetentionPolicy = "Delete"
+	// NamespaceRetentionOrphan keeps obsolete namespaces, but removes the labels and owner references which relate
+	// them to the Paas, so that the namespaces are no longer managed by the operator
+	NamespaceRetentionOrphan NamespaceRetentionPolicy = "Orphan"
+	// NamespaceRetentionDelayedDelete marks obsolete namespaces as pending deletion, and deletes them on a later
+	// reconciliation, once the configured delay has passed
+	NamespaceRetentionDelayedDelete NamespaceRetentionPolicy = "DelayedDelete"
+)
+
+// NamespaceRetention defines the retention of namespaces which are no longer required by a Paas
+type NamespaceRetention struct {
+	// The retention policy for obsolete namespaces, which is either Delete, Orphan or DelayedDelete.
+	// Defaults to Delete.
+	// +kubebuilder:validation:Enum=Delete;Orphan;DelayedDelete
+	// +kubebuilder:validation:Optional
+	Policy NamespaceRetentionPolicy `json:"policy,omitempty"`
+
+	// The number of hours an obsolete namespace is kept before it is deleted, which is required when the policy is
+	// DelayedDelete
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Optional
+	DelayHours int `json:"delayHours,omitempty"`
+}
+
+// GetPolicy returns the retention policy, and defaults to NamespaceRetentionDelete when no policy is set
+func (nr NamespaceRetention) GetPolicy() NamespaceRetentionPolicy {
+	if nr.Policy == "" {
+		return NamespaceRetentionDelete
+	}
+	return nr.Policy
+}
+
+// Delay returns the time an obsolete namespace is kept before it is deleted, when the policy is DelayedDelete
+func (nr NamespaceRetention) Delay() time.Duration {
+	return time.Duration(nr.DelayHours) * time.Hour
+}
+
+// ConfigPaasNSLimits allows an administrator to limit the nesting of PaasNS'es, and the number of namespaces of a Paas.
+// Limits are enforced by the PaasNS webhook on creation of a PaasNS, and by the operator when reconciling a Paas.
+type ConfigPaasNSLimits struct {
+	// The maximum nesting depth of PaasNS'es. A PaasNS in a namespace defined in a Paas has depth 1, a PaasNS in the
+	// namespace of that PaasNS has depth 2, etc. Defaults to 0, which means no limit.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Optional
+	MaxDepth int `json:"maxDepth,omitempty"`
+
+	// The maximum number of namespaces of a Paas, including the namespaces for capabilities, `spec.namespaces` and
+	// PaasNS'es. Defaults to 0, which means no limit.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Optional
+	MaxNamespaces int `json:"maxNamespaces,omitempty"`
+}
+
+// DepthExceeded returns true when depth exceeds the configured maximum depth
+func (l ConfigPaasNSLimits) DepthExceeded(depth int) bool {
+	return l.MaxDepth > 0 && depth > l.MaxDepth
+}
+
+// NamespacesExceeded returns true when count exceeds the configured maximum number of namespaces
+func (l ConfigPaasNSLimits) NamespacesExceeded(count int) bool {
+	return l.MaxNamespaces > 0 && count > l.MaxNamespaces
 }
 
 type ConfigRoleMappings map[string][]string
@@ -132,12 +551,87 @@ func (crm ConfigRoleMappings) Roles(roleMaps []string) []string {
 	return mappedRoles
 }
 
+// ConfigScopedRoleMapping maps a role to technical roles, in the selected namespaces of a Paas only
+type ConfigScopedRoleMapping struct {
+	// The role, as used in the groups of a Paas
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Required
+	Role string `json:"role"`
+
+	// The namespaces in which the mapping applies. Defaults to all namespaces of a Paas.
+	// +kubebuilder:validation:Optional
+	Namespaces ConfigNamespaceSelector `json:"namespaces,omitempty"`
+
+	// The technical roles which are bound in the selected namespaces
+	// +kubebuilder:validation:Required
+	ClusterRoles []string `json:"clusterRoles"`
+}
+
+// ConfigScopedRoleMappings holds all role mappings which only apply in selected namespaces of a Paas
+type ConfigScopedRoleMappings []ConfigScopedRoleMapping
+
+// Roles returns the technical roles for roleMaps in a namespace of the given kind, and (for capability namespaces)
+// capability. For every role which has scoped role mappings that select the namespace, the scoped role mappings
+// are used. For all other roles, the role mappings in crm are used.
+func (srm ConfigScopedRoleMappings) Roles(
+	crm ConfigRoleMappings,
+	roleMaps []string,
+	kind ConfigNamespaceKind,
+	capName string,
+) []string {
+	if len(roleMaps) == 0 {
+		roleMaps = []string{"default"}
+	}
+	var mappedRoles []string
+	for _, roleMap := range roleMaps {
+		var scoped bool
+		for _, mapping := range srm {
+			if mapping.Role == roleMap && mapping.Namespaces.Matches(kind, capName) {
+				scoped = true
+				mappedRoles = append(mappedRoles, mapping.ClusterRoles...)
+			}
+		}
+		if !scoped {
+			mappedRoles = append(mappedRoles, crm[roleMap]...)
+		}
+	}
+	return mappedRoles
+}
+
+// ClusterRoles returns all technical roles which are used in scoped role mappings
+func (srm ConfigScopedRoleMappings) ClusterRoles() (roles []string) {
+	for _, mapping := range srm {
+		roles = append(roles, mapping.ClusterRoles...)
+	}
+	return roles
+}
+
+// Defines returns true when there are scoped role mappings for role
+func (srm ConfigScopedRoleMappings) Defines(role string) bool {
+	return slices.ContainsFunc(srm, func(mapping ConfigScopedRoleMapping) bool { return mapping.Role == role })
+}
+
 type ConfigFeatureFlags struct {
 	// Should the operator manage group users
 	// +kubebuilder:default:=allow
 	// +kubebuilder:validation:Enum=allow;warn;block
 	// +kubebuilder:validation:Optional
 	GroupUserManagement string `json:"group_user_management,omitempty"`
+
+	// Should the operator generate NetworkPolicies which isolate the namespaces of a Paas from other Paas'es.
+	// Ingress is allowed from namespaces of the same Paas, from the namespaces in `networkIsolation` and, for
+	// capability namespaces, from the network peers of the capability.
+	// +kubebuilder:validation:Optional
+	NetworkIsolation bool `json:"network_isolation,omitempty"`
+}
+
+// ConfigNetworkIsolation holds the settings for the NetworkPolicies which isolate the namespaces of a Paas, when
+// enabled with the `network_isolation` feature flag
+type ConfigNetworkIsolation struct {
+	// Selectors of namespaces from which ingress is allowed into all namespaces of every Paas, e.g. namespaces
+	// of ingress controllers or monitoring
+	// +kubebuilder:validation:Optional
+	AllowedNamespaces []metav1.LabelSelector `json:"allowedNamespaces,omitempty"`
 }
 
 type ConfigCapabilities map[string]ConfigCapability
@@ -160,10 +654,42 @@ type ConfigCapability struct {
 	// +kubebuilder:validation:Optional
 	DefaultPermissions ConfigCapPerm `json:"default_permissions"`
 
+	// The scope in which the roles of the default and extra permissions are granted to the service accounts, by
+	// role. Roles without a scope are granted cluster-wide.
+	// +kubebuilder:validation:Optional
+	PermissionScopes map[string]ConfigPermissionScope `json:"permission_scopes,omitempty"`
+
 	// Settings to allow specific configuration specific to a capability
 	CustomFields map[string]ConfigCustomField `json:"custom_fields,omitempty"`
+
+	// Selectors of namespaces from which ingress is allowed into the namespace of this capability, when network
+	// isolation is enabled with the `network_isolation` feature flag
+	// +kubebuilder:validation:Optional
+	NetworkPeers []metav1.LabelSelector `json:"network_peers,omitempty"`
 }
 
+// PermissionScope returns the scope in which role is granted to the service accounts of this capability, and
+// defaults to ConfigPermissionScopeCluster
+func (cc ConfigCapability) PermissionScope(role string) ConfigPermissionScope {
+	if scope, exists := cc.PermissionScopes[role]; exists {
+		return scope
+	}
+	return ConfigPermissionScopeCluster
+}
+
+// ConfigPermissionScope is the scope in which a role of the permissions of a capability is granted
+// +kubebuilder:validation:Enum=cluster;paas;namespace
+type ConfigPermissionScope string
+
+const (
+	// ConfigPermissionScopeCluster grants the role cluster-wide, with a ClusterRoleBinding
+	ConfigPermissionScopeCluster ConfigPermissionScope = "cluster"
+	// ConfigPermissionScopePaas grants the role with RoleBindings in every namespace of the Paas
+	ConfigPermissionScopePaas ConfigPermissionScope = "paas"
+	// ConfigPermissionScopeNamespace grants the role with a RoleBinding in the namespace of the capability only
+	ConfigPermissionScopeNamespace ConfigPermissionScope = "namespace"
+)
+
 // For each resource type go templating can be used to derive the labels to be set on the resource when created
 type ConfigTemplatingItems struct {
 	// Templates to add fields to all capabilities
@@ -185,6 +711,22 @@ type ConfigTemplatingItems struct {
 	// Templates to describe labels for rolebindings
 	// +kubebuilder:validation:Optional
 	RoleBindingLabels ConfigTemplatingItem `json:"roleBindingLabels,omitempty"`
+
+	// Templates to add annotations to cluster quotas
+	// +kubebuilder:validation:Optional
+	ClusterQuotaAnnotations ConfigTemplatingItem `json:"clusterQuotaAnnotations,omitempty"`
+
+	// Templates to add annotations to groups
+	// +kubebuilder:validation:Optional
+	GroupAnnotations ConfigTemplatingItem `json:"groupAnnotations,omitempty"`
+
+	// Templates to add annotations to namespaces, e.g. openshift.io/display-name or openshift.io/node-selector
+	// +kubebuilder:validation:Optional
+	NamespaceAnnotations ConfigTemplatingItem `json:"namespaceAnnotations,omitempty"`
+
+	// Templates to add annotations to rolebindings
+	// +kubebuilder:validation:Optional
+	RoleBindingAnnotations ConfigTemplatingItem `json:"roleBindingAnnotations,omitempty"`
 }
 
 // go templating can be used to derive the labels to be set on the resource when created
@@ -232,6 +774,64 @@ type ConfigQuotaSettings struct {
 	// The maximum quota which the capability gets
 	// +kubebuilder:validation:Optional
 	MaxQuotas paasquota.Quota `json:"max"`
+
+	// How the quotas of all Paas'es are aggregated into the clusterwide quota. Defaults to the Optimal strategy.
+	// +kubebuilder:validation:Optional
+	Aggregation ConfigQuotaAggregation `json:"aggregation,omitempty"`
+
+	// Aggregations for specific resources (e.a. requests.storage), overriding Aggregation
+	// +kubebuilder:validation:Optional
+	ResourceAggregations map[corev1.ResourceName]ConfigQuotaAggregation `json:"resource_aggregations,omitempty"`
+}
+
+// ConfigQuotaAggregation selects the strategy (and its parameters) which aggregates the quotas of all Paas'es into a
+// clusterwide quota
+type ConfigQuotaAggregation struct {
+	// The aggregation strategy, one of Optimal, Sum, LargestN, Percentile, Max and Min.
+	// Optimal (the default) is the largest of the sum of all quotas times the ratio and the sum of the largest two
+	// quotas.
+	// +kubebuilder:validation:Optional
+	Strategy string `json:"strategy,omitempty"`
+
+	// The number of largest quotas which are summed by the LargestN strategy. Defaults to 2.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	N int `json:"n,omitempty"`
+
+	// The percentile of all quotas which is used by the Percentile strategy
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=100
+	Percentile int `json:"percentile,omitempty"`
+}
+
+// AsAggregation returns the quota aggregation for this ConfigQuotaAggregation, where ratio is used by the Optimal
+// strategy
+func (cqa ConfigQuotaAggregation) AsAggregation(ratio float64) paasquota.Aggregation {
+	strategy := cqa.Strategy
+	if strategy == "" {
+		strategy = paasquota.AggregationOptimal
+	}
+	return paasquota.Aggregation{
+		Strategy: strategy,
+		Params: paasquota.AggregationParams{
+			Ratio:      ratio,
+			N:          cqa.N,
+			Percentile: cqa.Percentile,
+		},
+	}
+}
+
+// Aggregations returns the default quota aggregation and the per resource overrides of these quota settings
+func (cqs ConfigQuotaSettings) Aggregations() (
+	paasquota.Aggregation,
+	map[corev1.ResourceName]paasquota.Aggregation,
+) {
+	overrides := make(map[corev1.ResourceName]paasquota.Aggregation)
+	for resource, aggregation := range cqs.ResourceAggregations {
+		overrides[resource] = aggregation.AsAggregation(cqs.Ratio)
+	}
+	return cqs.Aggregation.AsAggregation(cqs.Ratio), overrides
 }
 
 // ConfigMaxAllowedSubmittedQuota allows an administrator to configure an absolute maximum
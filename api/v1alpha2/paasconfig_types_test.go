@@ -4,7 +4,9 @@ import (
 	"testing"
 	"time"
 
+	paasquota "github.com/belastingdienst/opr-paas/v5/pkg/quota"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 )
@@ -96,6 +98,201 @@ func TestConfigRoleMappings_Roles(t *testing.T) {
 	})
 }
 
+func TestConfigScopedRoleMappings(t *testing.T) {
+	crm := ConfigRoleMappings{
+		"developer": {"edit"},
+		"default":   {"view"},
+	}
+	srm := ConfigScopedRoleMappings{
+		{
+			Role: "developer",
+			Namespaces: ConfigNamespaceSelector{
+				Kinds:        []ConfigNamespaceKind{ConfigNamespaceKindCapability},
+				Capabilities: []string{"argocd"},
+			},
+			ClusterRoles: []string{"view"},
+		},
+		{
+			Role:         "developer",
+			Namespaces:   ConfigNamespaceSelector{Kinds: []ConfigNamespaceKind{ConfigNamespaceKindCapability}},
+			ClusterRoles: []string{"monitoring"},
+		},
+		{
+			Role:         "auditor",
+			Namespaces:   ConfigNamespaceSelector{Kinds: []ConfigNamespaceKind{ConfigNamespaceKindPaasNS}},
+			ClusterRoles: []string{"audit"},
+		},
+	}
+
+	t.Run("Role mappings are used in namespaces which are not selected", func(t *testing.T) {
+		assert.Equal(t, []string{"edit"}, srm.Roles(crm, []string{"developer"}, ConfigNamespaceKindPaas, ""))
+		assert.Empty(t, srm.Roles(crm, []string{"auditor"}, ConfigNamespaceKindPaas, ""))
+	})
+
+	t.Run("Scoped role mappings take precedence in selected namespaces", func(t *testing.T) {
+		assert.Equal(t, []string{"view", "monitoring"},
+			srm.Roles(crm, []string{"developer"}, ConfigNamespaceKindCapability, "argocd"))
+		assert.Equal(t, []string{"monitoring"},
+			srm.Roles(crm, []string{"developer"}, ConfigNamespaceKindCapability, "tekton"))
+		assert.Equal(t, []string{"audit", "edit"},
+			srm.Roles(crm, []string{"auditor", "developer"}, ConfigNamespaceKindPaasNS, ""))
+	})
+
+	t.Run("Empty input uses default", func(t *testing.T) {
+		assert.Equal(t, []string{"view"}, srm.Roles(crm, nil, ConfigNamespaceKindPaas, ""))
+	})
+
+	t.Run("Cluster roles and defined roles", func(t *testing.T) {
+		assert.Equal(t, []string{"view", "monitoring", "audit"}, srm.ClusterRoles())
+		assert.True(t, srm.Defines("auditor"))
+		assert.False(t, srm.Defines("default"))
+	})
+}
+
+func TestConfigPaasNSLimits(t *testing.T) {
+	t.Run("No limits", func(t *testing.T) {
+		limits := ConfigPaasNSLimits{}
+		assert.False(t, limits.DepthExceeded(100))
+		assert.False(t, limits.NamespacesExceeded(100))
+	})
+
+	t.Run("Within limits", func(t *testing.T) {
+		limits := ConfigPaasNSLimits{MaxDepth: 2, MaxNamespaces: 5}
+		assert.False(t, limits.DepthExceeded(2))
+		assert.False(t, limits.NamespacesExceeded(5))
+	})
+
+	t.Run("Exceeding limits", func(t *testing.T) {
+		limits := ConfigPaasNSLimits{MaxDepth: 2, MaxNamespaces: 5}
+		assert.True(t, limits.DepthExceeded(3))
+		assert.True(t, limits.NamespacesExceeded(6))
+	})
+}
+
+func TestConfigNamespaceSelector_Matches(t *testing.T) {
+	t.Run("Empty selector matches all namespaces", func(t *testing.T) {
+		selector := ConfigNamespaceSelector{}
+		assert.True(t, selector.Matches(ConfigNamespaceKindPaas, ""))
+		assert.True(t, selector.Matches(ConfigNamespaceKindPaasNS, ""))
+		assert.True(t, selector.Matches(ConfigNamespaceKindCapability, "argocd"))
+	})
+
+	t.Run("Selecting by kind", func(t *testing.T) {
+		selector := ConfigNamespaceSelector{Kinds: []ConfigNamespaceKind{ConfigNamespaceKindPaas}}
+		assert.True(t, selector.Matches(ConfigNamespaceKindPaas, ""))
+		assert.False(t, selector.Matches(ConfigNamespaceKindPaasNS, ""))
+		assert.False(t, selector.Matches(ConfigNamespaceKindCapability, "argocd"))
+	})
+
+	t.Run("Selecting by capability only limits capability namespaces", func(t *testing.T) {
+		selector := ConfigNamespaceSelector{Capabilities: []string{"argocd"}}
+		assert.True(t, selector.Matches(ConfigNamespaceKindPaas, ""))
+		assert.True(t, selector.Matches(ConfigNamespaceKindCapability, "argocd"))
+		assert.False(t, selector.Matches(ConfigNamespaceKindCapability, "tekton"))
+	})
+
+	t.Run("Selecting by kind and capability", func(t *testing.T) {
+		selector := ConfigNamespaceSelector{
+			Kinds:        []ConfigNamespaceKind{ConfigNamespaceKindCapability},
+			Capabilities: []string{"argocd"},
+		}
+		assert.False(t, selector.Matches(ConfigNamespaceKindPaas, ""))
+		assert.True(t, selector.Matches(ConfigNamespaceKindCapability, "argocd"))
+		assert.False(t, selector.Matches(ConfigNamespaceKindCapability, "tekton"))
+	})
+}
+
+func TestConfigQuotaBackend(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		backend := ConfigQuotaBackend{}
+		assert.Equal(t, ConfigQuotaBackendClusterResourceQuota, backend.GetType())
+		assert.Equal(t, ConfigQuotaDistributionEven, backend.GetDistribution())
+	})
+
+	t.Run("Configured", func(t *testing.T) {
+		backend := ConfigQuotaBackend{
+			Type:         ConfigQuotaBackendResourceQuota,
+			Distribution: ConfigQuotaDistributionReplicate,
+		}
+		assert.Equal(t, ConfigQuotaBackendResourceQuota, backend.GetType())
+		assert.Equal(t, ConfigQuotaDistributionReplicate, backend.GetDistribution())
+	})
+}
+
+func TestConfigGroupBackend(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		backend := ConfigGroupBackend{}
+		assert.Equal(t, ConfigGroupBackendOpenShift, backend.GetType())
+		assert.Equal(t, ConfigGroupUsersReject, backend.GetUsers())
+	})
+
+	t.Run("Configured", func(t *testing.T) {
+		backend := ConfigGroupBackend{Type: ConfigGroupBackendVirtual, Users: ConfigGroupUsersMapToUsers}
+		assert.Equal(t, ConfigGroupBackendVirtual, backend.GetType())
+		assert.Equal(t, ConfigGroupUsersMapToUsers, backend.GetUsers())
+	})
+}
+
+func TestConfigGroupExpiry_MaxWindow(t *testing.T) {
+	assert.Zero(t, ConfigGroupExpiry{}.MaxWindow())
+	assert.Equal(t, 48*time.Hour, ConfigGroupExpiry{MaxHours: 48}.MaxWindow())
+}
+
+func TestConfigGroupSync(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		groupSync := ConfigGroupSync{}
+		assert.Equal(t, ConfigGroupSyncConfigMap, groupSync.GetKind())
+		assert.Equal(t, map[string]string{"groups": "{{ .Groups.AsString }}"}, groupSync.GetData())
+	})
+
+	t.Run("Configured", func(t *testing.T) {
+		groupSync := ConfigGroupSync{Kind: ConfigGroupSyncSecret, Data: map[string]string{"whitelist.txt": "x"}}
+		assert.Equal(t, ConfigGroupSyncSecret, groupSync.GetKind())
+		assert.Equal(t, map[string]string{"whitelist.txt": "x"}, groupSync.GetData())
+	})
+}
+
+func TestConfigCapability_PermissionScope(t *testing.T) {
+	capability := ConfigCapability{PermissionScopes: map[string]ConfigPermissionScope{
+		"view": ConfigPermissionScopeNamespace,
+		"edit": ConfigPermissionScopePaas,
+	}}
+	assert.Equal(t, ConfigPermissionScopeNamespace, capability.PermissionScope("view"))
+	assert.Equal(t, ConfigPermissionScopePaas, capability.PermissionScope("edit"))
+	assert.Equal(t, ConfigPermissionScopeCluster, capability.PermissionScope("admin"))
+	assert.Equal(t, ConfigPermissionScopeCluster, ConfigCapability{}.PermissionScope("view"))
+}
+
+func TestConfigQuotaSettings_Aggregations(t *testing.T) {
+	qs := ConfigQuotaSettings{
+		Ratio:       0.7,
+		Aggregation: ConfigQuotaAggregation{Strategy: paasquota.AggregationLargestN, N: 3},
+		ResourceAggregations: map[corev1.ResourceName]ConfigQuotaAggregation{
+			"requests.storage": {Strategy: paasquota.AggregationPercentile, Percentile: 90},
+			"limits.cpu":       {},
+		},
+	}
+	aggregation, overrides := qs.Aggregations()
+	assert.Equal(t, paasquota.Aggregation{
+		Strategy: paasquota.AggregationLargestN,
+		Params:   paasquota.AggregationParams{Ratio: 0.7, N: 3},
+	}, aggregation)
+	assert.Equal(t, map[corev1.ResourceName]paasquota.Aggregation{
+		"requests.storage": {
+			Strategy: paasquota.AggregationPercentile,
+			Params:   paasquota.AggregationParams{Ratio: 0.7, Percentile: 90},
+		},
+		"limits.cpu": {
+			Strategy: paasquota.AggregationOptimal,
+			Params:   paasquota.AggregationParams{Ratio: 0.7},
+		},
+	}, overrides)
+
+	aggregation, overrides = ConfigQuotaSettings{}.Aggregations()
+	assert.Equal(t, paasquota.AggregationOptimal, aggregation.Strategy)
+	assert.Empty(t, overrides)
+}
+
 func TestConfigCapPerm_Roles(t *testing.T) {
 	t.Run("Empty map returns empty slice", func(t *testing.T) {
 		ccp := ConfigCapPerm{}
@@ -42,6 +42,21 @@ type PaasNSSpec struct {
 	Secrets map[string]string `json:"secrets,omitempty"`
 }
 
+// PaasNSStatus defines the observed state of PaasNS
+type PaasNSStatus struct {
+	// Conditions of this resource
+	// +kubebuilder:validation:Optional
+	//revive:disable-next-line
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+	// Namespace is the name of the namespace which is created for this PaasNS
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+	// Groups are the keys of the groups, as defined in the related `paas`, which effectively have access to the
+	// namespace created for this PaasNS
+	// +kubebuilder:validation:Optional
+	Groups []string `json:"groups,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
@@ -53,7 +68,18 @@ type PaasNS struct {
 	metav1.TypeMeta   `json:""`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec PaasNSSpec `json:"spec,omitempty"`
+	Spec   PaasNSSpec   `json:"spec,omitempty"`
+	Status PaasNSStatus `json:"status,omitempty"`
+}
+
+// GetConditions is required to allow a PaasNS to be used as a withStatus interface in our e2e test framework
+func (pns *PaasNS) GetConditions() *[]metav1.Condition {
+	return &pns.Status.Conditions
+}
+
+// GetGeneration is required for PaasNS to be used as api.Resource
+func (pns PaasNS) GetGeneration() int64 {
+	return pns.Generation
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,145 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha2
+
+import (
+	"slices"
+
+	paasquota "github.com/belastingdienst/opr-paas/v5/pkg/quota"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Definitions to manage status conditions
+const (
+	// TypeReadyQuotaRequest represents the status of the QuotaRequest reconciliation
+	TypeReadyQuotaRequest = "Ready"
+)
+
+// QuotaRequestDecisionAnnotation is set on a QuotaRequest by an approver, to approve or reject the QuotaRequest.
+// The value is either Approved or Rejected.
+const QuotaRequestDecisionAnnotation = "paas.cpet.belastingdienst.nl/quota-request-decision"
+
+// QuotaRequestDecidedByAnnotation is set on a QuotaRequest by an approver, along with the decision. The value must be
+// the user name of the approver.
+const QuotaRequestDecidedByAnnotation = "paas.cpet.belastingdienst.nl/quota-request-decided-by"
+
+// QuotaRequestDecision is the decision of an approver on a QuotaRequest
+type QuotaRequestDecision string
+
+const (
+	// QuotaRequestApproved approves a QuotaRequest, after which the operator applies it to the quota of the Paas
+	QuotaRequestApproved QuotaRequestDecision = "Approved"
+	// QuotaRequestRejected rejects a QuotaRequest
+	QuotaRequestRejected QuotaRequestDecision = "Rejected"
+)
+
+// QuotaRequestDecisions are all valid decisions on a QuotaRequest
+var QuotaRequestDecisions = []QuotaRequestDecision{QuotaRequestApproved, QuotaRequestRejected}
+
+// QuotaRequestPhase is the phase of a QuotaRequest
+type QuotaRequestPhase string
+
+const (
+	// QuotaRequestPending is the phase of a QuotaRequest which awaits a decision
+	QuotaRequestPending QuotaRequestPhase = "Pending"
+	// QuotaRequestApplied is the phase of an approved QuotaRequest, which is applied to the quota of the Paas
+	QuotaRequestApplied QuotaRequestPhase = "Applied"
+	// QuotaRequestRejectedPhase is the phase of a rejected QuotaRequest
+	QuotaRequestRejectedPhase QuotaRequestPhase = "Rejected"
+)
+
+// QuotaRequestSpec defines the desired state of QuotaRequest
+type QuotaRequestSpec struct {
+	// The capability of which the quota is requested. When not set, the quota of the Paas itself is requested.
+	// +kubebuilder:validation:Optional
+	Capability string `json:"capability,omitempty"`
+	// The requested quota. Every resource replaces that resource in the quota of the Paas (or capability), and must
+	// be larger than the current value.
+	// +kubebuilder:validation:Required
+	Quota paasquota.Quota `json:"quota"`
+	// Why the quota is needed, for the approvers
+	// +kubebuilder:validation:Optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// QuotaRequestStatus defines the observed state of QuotaRequest
+type QuotaRequestStatus struct {
+	// Conditions of this resource
+	// +kubebuilder:validation:Optional
+	//revive:disable-next-line
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+	// Phase is Pending, Applied or Rejected
+	// +kubebuilder:validation:Optional
+	Phase QuotaRequestPhase `json:"phase,omitempty"`
+	// Paas is the Paas which the QuotaRequest applies to
+	// +kubebuilder:validation:Optional
+	Paas string `json:"paas,omitempty"`
+	// DecidedBy is the approver who approved or rejected the QuotaRequest
+	// +kubebuilder:validation:Optional
+	DecidedBy string `json:"decidedBy,omitempty"`
+	// DecidedAt is the time at which the operator processed the decision
+	// +kubebuilder:validation:Optional
+	DecidedAt *metav1.Time `json:"decidedAt,omitempty"`
+	// PreviousQuota holds the values of the requested resources before the QuotaRequest was applied
+	// +kubebuilder:validation:Optional
+	PreviousQuota paasquota.Quota `json:"previousQuota,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=quotarequests,shortName=qr,scope=Namespaced
+// +kubebuilder:printcolumn:name="Paas",type=string,JSONPath=`.status.paas`
+// +kubebuilder:printcolumn:name="Capability",type=string,JSONPath=`.spec.capability`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+
+// QuotaRequest is the Schema for the QuotaRequests API. A tenant creates a QuotaRequest in a namespace of a Paas to
+// request extra quota for the Paas, or for one of its capabilities.
+type QuotaRequest struct {
+	metav1.TypeMeta   `json:""`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   QuotaRequestSpec   `json:"spec,omitempty"`
+	Status QuotaRequestStatus `json:"status,omitempty"`
+}
+
+// GetConditions is required to allow a QuotaRequest to be used as a withStatus interface in our e2e test framework
+func (qr *QuotaRequest) GetConditions() *[]metav1.Condition {
+	return &qr.Status.Conditions
+}
+
+// GetGeneration is required for QuotaRequest to be used as api.Resource
+func (qr QuotaRequest) GetGeneration() int64 {
+	return qr.Generation
+}
+
+// Decision returns the decision of an approver on the QuotaRequest, which is empty when no decision was made
+func (qr QuotaRequest) Decision() QuotaRequestDecision {
+	return QuotaRequestDecision(qr.Annotations[QuotaRequestDecisionAnnotation])
+}
+
+// DecidedBy returns the approver who made the decision on the QuotaRequest
+func (qr QuotaRequest) DecidedBy() string {
+	return qr.Annotations[QuotaRequestDecidedByAnnotation]
+}
+
+// IsDecided is true when the decision of an approver on the QuotaRequest was processed by the operator
+func (qr QuotaRequest) IsDecided() bool {
+	return slices.Contains([]QuotaRequestPhase{QuotaRequestApplied, QuotaRequestRejectedPhase}, qr.Status.Phase)
+}
+
+// +kubebuilder:object:root=true
+
+// QuotaRequestList contains a list of QuotaRequest
+type QuotaRequestList struct {
+	metav1.TypeMeta `json:""`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []QuotaRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&QuotaRequest{}, &QuotaRequestList{})
+}
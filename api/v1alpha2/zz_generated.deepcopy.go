@@ -11,10 +11,27 @@ See LICENSE.md for details.
 package v1alpha2
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigBaselineResource) DeepCopyInto(out *ConfigBaselineResource) {
+	*out = *in
+	in.Namespaces.DeepCopyInto(&out.Namespaces)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigBaselineResource.
+func (in *ConfigBaselineResource) DeepCopy() *ConfigBaselineResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigBaselineResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in ConfigCapPerm) DeepCopyInto(out *ConfigCapPerm) {
 	{
@@ -102,6 +119,13 @@ func (in *ConfigCapability) DeepCopyInto(out *ConfigCapability) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.PermissionScopes != nil {
+		in, out := &in.PermissionScopes, &out.PermissionScopes
+		*out = make(map[string]ConfigPermissionScope, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.CustomFields != nil {
 		in, out := &in.CustomFields, &out.CustomFields
 		*out = make(map[string]ConfigCustomField, len(*in))
@@ -109,6 +133,13 @@ func (in *ConfigCapability) DeepCopyInto(out *ConfigCapability) {
 			(*out)[key] = val
 		}
 	}
+	if in.NetworkPeers != nil {
+		in, out := &in.NetworkPeers, &out.NetworkPeers
+		*out = make([]v1.LabelSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigCapability.
@@ -151,6 +182,59 @@ func (in *ConfigFeatureFlags) DeepCopy() *ConfigFeatureFlags {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigGroupBackend) DeepCopyInto(out *ConfigGroupBackend) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigGroupBackend.
+func (in *ConfigGroupBackend) DeepCopy() *ConfigGroupBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigGroupBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigGroupExpiry) DeepCopyInto(out *ConfigGroupExpiry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigGroupExpiry.
+func (in *ConfigGroupExpiry) DeepCopy() *ConfigGroupExpiry {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigGroupExpiry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigGroupSync) DeepCopyInto(out *ConfigGroupSync) {
+	*out = *in
+	out.Target = in.Target
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigGroupSync.
+func (in *ConfigGroupSync) DeepCopy() *ConfigGroupSync {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigGroupSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMaxAllowedSubmittedQuota) DeepCopyInto(out *ConfigMaxAllowedSubmittedQuota) {
 	*out = *in
@@ -167,12 +251,127 @@ func (in *ConfigMaxAllowedSubmittedQuota) DeepCopy() *ConfigMaxAllowedSubmittedQ
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigNamespaceSelector) DeepCopyInto(out *ConfigNamespaceSelector) {
+	*out = *in
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]ConfigNamespaceKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigNamespaceSelector.
+func (in *ConfigNamespaceSelector) DeepCopy() *ConfigNamespaceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigNamespaceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigNetworkIsolation) DeepCopyInto(out *ConfigNetworkIsolation) {
+	*out = *in
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]v1.LabelSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigNetworkIsolation.
+func (in *ConfigNetworkIsolation) DeepCopy() *ConfigNetworkIsolation {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigNetworkIsolation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigPaasNSLimits) DeepCopyInto(out *ConfigPaasNSLimits) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigPaasNSLimits.
+func (in *ConfigPaasNSLimits) DeepCopy() *ConfigPaasNSLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigPaasNSLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigQuotaAggregation) DeepCopyInto(out *ConfigQuotaAggregation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigQuotaAggregation.
+func (in *ConfigQuotaAggregation) DeepCopy() *ConfigQuotaAggregation {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigQuotaAggregation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigQuotaBackend) DeepCopyInto(out *ConfigQuotaBackend) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigQuotaBackend.
+func (in *ConfigQuotaBackend) DeepCopy() *ConfigQuotaBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigQuotaBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigQuotaRequests) DeepCopyInto(out *ConfigQuotaRequests) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigQuotaRequests.
+func (in *ConfigQuotaRequests) DeepCopy() *ConfigQuotaRequests {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigQuotaRequests)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigQuotaSettings) DeepCopyInto(out *ConfigQuotaSettings) {
 	*out = *in
 	out.DefQuota = in.DefQuota.DeepCopy()
 	out.MinQuotas = in.MinQuotas.DeepCopy()
 	out.MaxQuotas = in.MaxQuotas.DeepCopy()
+	out.Aggregation = in.Aggregation
+	if in.ResourceAggregations != nil {
+		in, out := &in.ResourceAggregations, &out.ResourceAggregations
+		*out = make(map[corev1.ResourceName]ConfigQuotaAggregation, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigQuotaSettings.
@@ -185,6 +384,21 @@ func (in *ConfigQuotaSettings) DeepCopy() *ConfigQuotaSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigQuotaUsage) DeepCopyInto(out *ConfigQuotaUsage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigQuotaUsage.
+func (in *ConfigQuotaUsage) DeepCopy() *ConfigQuotaUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigQuotaUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in ConfigRoleMappings) DeepCopyInto(out *ConfigRoleMappings) {
 	{
@@ -247,6 +461,48 @@ func (in ConfigRolesSas) DeepCopy() ConfigRolesSas {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigScopedRoleMapping) DeepCopyInto(out *ConfigScopedRoleMapping) {
+	*out = *in
+	in.Namespaces.DeepCopyInto(&out.Namespaces)
+	if in.ClusterRoles != nil {
+		in, out := &in.ClusterRoles, &out.ClusterRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigScopedRoleMapping.
+func (in *ConfigScopedRoleMapping) DeepCopy() *ConfigScopedRoleMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigScopedRoleMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in ConfigScopedRoleMappings) DeepCopyInto(out *ConfigScopedRoleMappings) {
+	{
+		in := &in
+		*out = make(ConfigScopedRoleMappings, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigScopedRoleMappings.
+func (in ConfigScopedRoleMappings) DeepCopy() ConfigScopedRoleMappings {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigScopedRoleMappings)
+	in.DeepCopyInto(out)
+	return *out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in ConfigTemplatingItem) DeepCopyInto(out *ConfigTemplatingItem) {
 	{
@@ -306,6 +562,34 @@ func (in *ConfigTemplatingItems) DeepCopyInto(out *ConfigTemplatingItems) {
 			(*out)[key] = val
 		}
 	}
+	if in.ClusterQuotaAnnotations != nil {
+		in, out := &in.ClusterQuotaAnnotations, &out.ClusterQuotaAnnotations
+		*out = make(ConfigTemplatingItem, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.GroupAnnotations != nil {
+		in, out := &in.GroupAnnotations, &out.GroupAnnotations
+		*out = make(ConfigTemplatingItem, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NamespaceAnnotations != nil {
+		in, out := &in.NamespaceAnnotations, &out.NamespaceAnnotations
+		*out = make(ConfigTemplatingItem, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RoleBindingAnnotations != nil {
+		in, out := &in.RoleBindingAnnotations, &out.RoleBindingAnnotations
+		*out = make(ConfigTemplatingItem, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigTemplatingItems.
@@ -318,6 +602,21 @@ func (in *ConfigTemplatingItems) DeepCopy() *ConfigTemplatingItems {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceRetention) DeepCopyInto(out *NamespaceRetention) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceRetention.
+func (in *NamespaceRetention) DeepCopy() *NamespaceRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NamespacedName) DeepCopyInto(out *NamespacedName) {
 	*out = *in
@@ -505,6 +804,13 @@ func (in *PaasConfigSpec) DeepCopyInto(out *PaasConfigSpec) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.ScopedRoleMappings != nil {
+		in, out := &in.ScopedRoleMappings, &out.ScopedRoleMappings
+		*out = make(ConfigScopedRoleMappings, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.FeatureFlags = in.FeatureFlags
 	if in.Validations != nil {
 		in, out := &in.Validations, &out.Validations
@@ -525,6 +831,31 @@ func (in *PaasConfigSpec) DeepCopyInto(out *PaasConfigSpec) {
 		}
 	}
 	in.Templating.DeepCopyInto(&out.Templating)
+	out.PaasNSLimits = in.PaasNSLimits
+	out.NamespaceRetention = in.NamespaceRetention
+	if in.AllowedNamespaceRetentionPolicies != nil {
+		in, out := &in.AllowedNamespaceRetentionPolicies, &out.AllowedNamespaceRetentionPolicies
+		*out = make([]NamespaceRetentionPolicy, len(*in))
+		copy(*out, *in)
+	}
+	if in.BaselineResources != nil {
+		in, out := &in.BaselineResources, &out.BaselineResources
+		*out = make(map[string]ConfigBaselineResource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	in.NetworkIsolation.DeepCopyInto(&out.NetworkIsolation)
+	out.QuotaBackend = in.QuotaBackend
+	out.GroupBackend = in.GroupBackend
+	if in.GroupSync != nil {
+		in, out := &in.GroupSync, &out.GroupSync
+		*out = new(ConfigGroupSync)
+		(*in).DeepCopyInto(*out)
+	}
+	out.GroupExpiry = in.GroupExpiry
+	out.QuotaUsage = in.QuotaUsage
+	out.QuotaRequests = in.QuotaRequests
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasConfigSpec.
@@ -612,6 +943,22 @@ func (in PaasConfigValidations) DeepCopy() PaasConfigValidations {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PaasExpiration) DeepCopyInto(out *PaasExpiration) {
+	*out = *in
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasExpiration.
+func (in *PaasExpiration) DeepCopy() *PaasExpiration {
+	if in == nil {
+		return nil
+	}
+	out := new(PaasExpiration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PaasGroup) DeepCopyInto(out *PaasGroup) {
 	*out = *in
@@ -625,6 +972,30 @@ func (in *PaasGroup) DeepCopyInto(out *PaasGroup) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.UserExpiries != nil {
+		in, out := &in.UserExpiries, &out.UserExpiries
+		*out = make(map[string]v1.Time, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.RoleExpiries != nil {
+		in, out := &in.RoleExpiries, &out.RoleExpiries
+		*out = make(map[string]v1.Time, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]PaasServiceAccount, len(*in))
+		copy(*out, *in)
+	}
+	if in.UserSubjects != nil {
+		in, out := &in.UserSubjects, &out.UserSubjects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasGroup.
@@ -658,6 +1029,21 @@ func (in PaasGroups) DeepCopy() PaasGroups {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PaasInventoryItem) DeepCopyInto(out *PaasInventoryItem) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasInventoryItem.
+func (in *PaasInventoryItem) DeepCopy() *PaasInventoryItem {
+	if in == nil {
+		return nil
+	}
+	out := new(PaasInventoryItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PaasList) DeepCopyInto(out *PaasList) {
 	*out = *in
@@ -696,6 +1082,7 @@ func (in *PaasNS) DeepCopyInto(out *PaasNS) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasNS.
@@ -775,6 +1162,33 @@ func (in *PaasNSSpec) DeepCopy() *PaasNSSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PaasNSStatus) DeepCopyInto(out *PaasNSStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasNSStatus.
+func (in *PaasNSStatus) DeepCopy() *PaasNSStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PaasNSStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PaasNamespace) DeepCopyInto(out *PaasNamespace) {
 	*out = *in
@@ -823,6 +1237,144 @@ func (in PaasNamespaces) DeepCopy() PaasNamespaces {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PaasPendingNamespaceDeletion) DeepCopyInto(out *PaasPendingNamespaceDeletion) {
+	*out = *in
+	in.DeleteAfter.DeepCopyInto(&out.DeleteAfter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasPendingNamespaceDeletion.
+func (in *PaasPendingNamespaceDeletion) DeepCopy() *PaasPendingNamespaceDeletion {
+	if in == nil {
+		return nil
+	}
+	out := new(PaasPendingNamespaceDeletion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PaasPlan) DeepCopyInto(out *PaasPlan) {
+	*out = *in
+	if in.WouldCreate != nil {
+		in, out := &in.WouldCreate, &out.WouldCreate
+		*out = make([]PaasPlanItem, len(*in))
+		copy(*out, *in)
+	}
+	if in.WouldUpdate != nil {
+		in, out := &in.WouldUpdate, &out.WouldUpdate
+		*out = make([]PaasPlanItem, len(*in))
+		copy(*out, *in)
+	}
+	if in.WouldDelete != nil {
+		in, out := &in.WouldDelete, &out.WouldDelete
+		*out = make([]PaasPlanItem, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasPlan.
+func (in *PaasPlan) DeepCopy() *PaasPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(PaasPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PaasPlanItem) DeepCopyInto(out *PaasPlanItem) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasPlanItem.
+func (in *PaasPlanItem) DeepCopy() *PaasPlanItem {
+	if in == nil {
+		return nil
+	}
+	out := new(PaasPlanItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PaasQuotaApproval) DeepCopyInto(out *PaasQuotaApproval) {
+	*out = *in
+	out.QuotaRequest = in.QuotaRequest
+	out.Previous = in.Previous.DeepCopy()
+	out.Approved = in.Approved.DeepCopy()
+	in.ApprovedAt.DeepCopyInto(&out.ApprovedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasQuotaApproval.
+func (in *PaasQuotaApproval) DeepCopy() *PaasQuotaApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(PaasQuotaApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PaasQuotaUsage) DeepCopyInto(out *PaasQuotaUsage) {
+	*out = *in
+	if in.Hard != nil {
+		in, out := &in.Hard, &out.Hard
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Contribution != nil {
+		in, out := &in.Contribution, &out.Contribution
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.UsedByPaas != nil {
+		in, out := &in.UsedByPaas, &out.UsedByPaas
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasQuotaUsage.
+func (in *PaasQuotaUsage) DeepCopy() *PaasQuotaUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(PaasQuotaUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PaasServiceAccount) DeepCopyInto(out *PaasServiceAccount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasServiceAccount.
+func (in *PaasServiceAccount) DeepCopy() *PaasServiceAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(PaasServiceAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PaasSpec) DeepCopyInto(out *PaasSpec) {
 	*out = *in
@@ -855,6 +1407,11 @@ func (in *PaasSpec) DeepCopyInto(out *PaasSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.NamespaceRetention != nil {
+		in, out := &in.NamespaceRetention, &out.NamespaceRetention
+		*out = new(NamespaceRetention)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasSpec.
@@ -877,6 +1434,44 @@ func (in *PaasStatus) DeepCopyInto(out *PaasStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Inventory != nil {
+		in, out := &in.Inventory, &out.Inventory
+		*out = make([]PaasInventoryItem, len(*in))
+		copy(*out, *in)
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(PaasPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingNamespaceDeletions != nil {
+		in, out := &in.PendingNamespaceDeletions, &out.PendingNamespaceDeletions
+		*out = make([]PaasPendingNamespaceDeletion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UpcomingExpirations != nil {
+		in, out := &in.UpcomingExpirations, &out.UpcomingExpirations
+		*out = make([]PaasExpiration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QuotaUsage != nil {
+		in, out := &in.QuotaUsage, &out.QuotaUsage
+		*out = make([]PaasQuotaUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QuotaApprovals != nil {
+		in, out := &in.QuotaApprovals, &out.QuotaApprovals
+		*out = make([]PaasQuotaApproval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PaasStatus.
@@ -888,3 +1483,105 @@ func (in *PaasStatus) DeepCopy() *PaasStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaRequest) DeepCopyInto(out *QuotaRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaRequest.
+func (in *QuotaRequest) DeepCopy() *QuotaRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QuotaRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaRequestList) DeepCopyInto(out *QuotaRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]QuotaRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaRequestList.
+func (in *QuotaRequestList) DeepCopy() *QuotaRequestList {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaRequestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QuotaRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaRequestSpec) DeepCopyInto(out *QuotaRequestSpec) {
+	*out = *in
+	out.Quota = in.Quota.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaRequestSpec.
+func (in *QuotaRequestSpec) DeepCopy() *QuotaRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaRequestStatus) DeepCopyInto(out *QuotaRequestStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DecidedAt != nil {
+		in, out := &in.DecidedAt, &out.DecidedAt
+		*out = (*in).DeepCopy()
+	}
+	out.PreviousQuota = in.PreviousQuota.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaRequestStatus.
+func (in *QuotaRequestStatus) DeepCopy() *QuotaRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -69,6 +69,7 @@ type flags struct {
 	metricsCertPath, metricsCertName, metricsCertKey string
 	webhookCertPath, webhookCertName, webhookCertKey string
 	argocdPluginGenAddr                              string
+	maxConcurrentReconciles                          int
 }
 
 func init() {
@@ -133,6 +134,8 @@ func configureFlags() *flags {
 		"Comma-separated list of components to log debug messages for.",
 	)
 	flag.BoolVar(&f.splitLogOutput, "split-log-output", false, "Send error logs to stderr, and the rest to stdout.")
+	flag.IntVar(&f.maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The maximum number of Paas'es which are reconciled concurrently.")
 	flag.Parse()
 
 	return f
@@ -220,7 +223,7 @@ func configureManager(f *flags) ctrl.Manager {
 	mgr := createManager(f, metricsServerOptions, webhookTLSOpts)
 	addCertWatchers(mgr, metricsCertWatcher, webhookCertWatcher)
 	setupPluginGenerator(f, mgr)
-	setupControllers(mgr)
+	setupControllers(f, mgr)
 	setupWebhooks(mgr)
 	setupHealthChecks(mgr)
 
@@ -360,23 +363,43 @@ func addCertWatchers(mgr ctrl.Manager, metricsWatcher, webhookWatcher *certwatch
 	}
 }
 
-func setupControllers(mgr ctrl.Manager) {
+func setupControllers(f *flags, mgr ctrl.Manager) {
 	if err := config.SetupPaasConfigInformer(mgr); err != nil {
 		log.Fatal().Err(err).Msg("unable to set up PaasConfig informer")
 	}
 
 	if err := (&controller.PaasConfigReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorder("paasconfig-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		log.Fatal().Err(err).Str("controller", "PaasConfig").Msg("unable to create controller")
 	}
 
 	if err := (&controller.PaasReconciler{
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		Recorder:                mgr.GetEventRecorder("paas-controller"),
+		MaxConcurrentReconciles: f.maxConcurrentReconciles,
+	}).SetupWithManager(mgr); err != nil {
+		log.Fatal().Err(err).Str("controller", "Paas").Msg("unable to create controller")
+	}
+
+	if err := (&controller.GroupSyncReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
-		log.Fatal().Err(err).Str("controller", "Paas").Msg("unable to create controller")
+		log.Fatal().Err(err).Str("controller", "GroupSync").Msg("unable to create controller")
+	}
+
+	if err := (&controller.QuotaRequestReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorder("quotarequest-controller"),
+		// Decisions on QuotaRequests are verified by the webhook
+		WebhooksEnabled: os.Getenv("ENABLE_WEBHOOKS") != "false",
+	}).SetupWithManager(mgr); err != nil {
+		log.Fatal().Err(err).Str("controller", "QuotaRequest").Msg("unable to create controller")
 	}
 }
 
@@ -400,5 +423,8 @@ func setupWebhooks(mgr ctrl.Manager) {
 		if err := webhookv1alpha2.SetupPaasNsWebhookWithManager(mgr); err != nil {
 			log.Fatal().Err(err).Str("webhook", "PaasNS").Msg(webhookErrMsg)
 		}
+		if err := webhookv1alpha2.SetupQuotaRequestWebhookWithManager(mgr); err != nil {
+			log.Fatal().Err(err).Str("webhook", "QuotaRequest").Msg(webhookErrMsg)
+		}
 	}
 }
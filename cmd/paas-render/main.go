@@ -0,0 +1,195 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+// paas-render renders all resources which the Paas controller would create for a Paas, and all parameters which the
+// ArgoCD plugin generator would generate for its capabilities, without a connection to a cluster.
+// It can be used to test a PaasConfig (e.a. templating and capability definitions) in CI.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/belastingdienst/opr-paas-cli/v2/pkg/crypt"
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	argocdplugingenerator "github.com/belastingdienst/opr-paas/v5/internal/argocd-plugin-generator"
+	"github.com/belastingdienst/opr-paas/v5/internal/controller"
+	"github.com/belastingdienst/opr-paas/v5/internal/logging"
+	"github.com/belastingdienst/opr-paas/v5/pkg/fields"
+	quotav1 "github.com/openshift/api/quota/v1"
+	userv1 "github.com/openshift/api/user/v1"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+var scheme = runtime.NewScheme()
+
+// stringList is a flag which can be specified multiple times
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+type flags struct {
+	paasPath        string
+	paasConfigPath  string
+	paasNSPaths     stringList
+	privateKeyPaths stringList
+	debug           bool
+}
+
+// rendered is the output of paas-render
+type rendered struct {
+	*controller.RenderedPaas
+	PluginGeneratorParameters map[string]fields.ElementMap `json:"pluginGeneratorParameters"`
+}
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(quotav1.AddToScheme(scheme))
+	utilruntime.Must(userv1.AddToScheme(scheme))
+	utilruntime.Must(v1alpha2.AddToScheme(scheme))
+}
+
+func main() {
+	f := configureFlags()
+	configureLogging(f.debug)
+
+	if err := run(context.Background(), f, os.Stdout); err != nil {
+		log.Fatal().Err(err).Msg("failed to render paas")
+	}
+}
+
+func configureFlags() *flags {
+	f := &flags{}
+	flag.StringVar(&f.paasPath, "paas", "", "Path to a yaml file with the Paas to render.")
+	flag.StringVar(&f.paasConfigPath, "paasconfig", "", "Path to a yaml file with the PaasConfig to render with.")
+	flag.Var(&f.paasNSPaths, "paasns",
+		"Path to a yaml file with a PaasNS of the Paas. Can be specified multiple times.")
+	flag.Var(&f.privateKeyPaths, "private-key", "Path to a private key to decrypt paas secrets with in "+
+		"plugin generator templates. Can be specified multiple times.")
+	flag.BoolVar(&f.debug, "debug", false, "Log all debug messages")
+	flag.Parse()
+
+	return f
+}
+
+// configureLogging only logs warnings and errors to stderr, unless debug is set, so that stdout has only the output
+func configureLogging(debug bool) {
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+	zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	if debug {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	}
+	logging.SetStaticLoggingConfig(debug, nil)
+}
+
+func run(ctx context.Context, f *flags, out io.Writer) error {
+	if f.paasPath == "" || f.paasConfigPath == "" {
+		return errors.New("both -paas and -paasconfig are required")
+	}
+	paas := &v1alpha2.Paas{}
+	if err := readObject(f.paasPath, "Paas", paas); err != nil {
+		return err
+	}
+	paasConfig := v1alpha2.PaasConfig{}
+	if err := readObject(f.paasConfigPath, "PaasConfig", &paasConfig); err != nil {
+		return err
+	}
+	var paasNSs []v1alpha2.PaasNS
+	for _, path := range f.paasNSPaths {
+		paasns := v1alpha2.PaasNS{}
+		if err := readObject(path, "PaasNS", &paasns); err != nil {
+			return err
+		}
+		paasNSs = append(paasNSs, paasns)
+	}
+	var keys crypt.PrivateKeys
+	if len(f.privateKeyPaths) > 0 {
+		var err error
+		if keys, err = crypt.NewPrivateKeysFromFiles(f.privateKeyPaths); err != nil {
+			return fmt.Errorf("failed to read private keys: %w", err)
+		}
+	}
+
+	result, err := render(ctx, paas, paasConfig, paasNSs, keys)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(result)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+func render(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	paasConfig v1alpha2.PaasConfig,
+	paasNSs []v1alpha2.PaasNS,
+	keys crypt.PrivateKeys,
+) (*rendered, error) {
+	renderedPaas, err := controller.Render(ctx, scheme, paas, paasConfig, paasNSs)
+	if err != nil {
+		return nil, err
+	}
+	result := &rendered{
+		RenderedPaas:              renderedPaas,
+		PluginGeneratorParameters: map[string]fields.ElementMap{},
+	}
+	capNames := make([]string, 0, len(paas.Spec.Capabilities))
+	for capName := range paas.Spec.Capabilities {
+		capNames = append(capNames, capName)
+	}
+	slices.Sort(capNames)
+	for _, capName := range capNames {
+		var elements fields.ElementMap
+		if elements, err = argocdplugingenerator.CapElementsFromPaas(ctx, paas, capName, paasConfig, keys); err != nil {
+			return nil, fmt.Errorf("failed to generate parameters for capability %s: %w", capName, err)
+		}
+		result.PluginGeneratorParameters[capName] = elements
+	}
+	return result, nil
+}
+
+// readObject reads a yaml file into obj, and checks that it has the expected kind and api version
+func readObject(path string, kind string, obj runtime.Object) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	typeMeta := metav1.TypeMeta{}
+	if err = yaml.Unmarshal(data, &typeMeta); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if typeMeta.Kind != kind || typeMeta.APIVersion != v1alpha2.GroupVersion.String() {
+		return fmt.Errorf("%s does not hold a %s/%s: found %s/%s",
+			path, v1alpha2.GroupVersion, kind, typeMeta.APIVersion, typeMeta.Kind)
+	}
+	if err = yaml.UnmarshalStrict(data, obj); err != nil {
+		return fmt.Errorf("failed to parse %s from %s: %w", kind, path, err)
+	}
+	return nil
+}
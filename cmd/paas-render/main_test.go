@@ -0,0 +1,74 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestRun(t *testing.T) {
+	f := &flags{
+		paasPath:       "testdata/paas.yaml",
+		paasConfigPath: "testdata/paasconfig.yaml",
+		paasNSPaths:    stringList{"testdata/paasns.yaml"},
+	}
+	var out bytes.Buffer
+	require.NoError(t, run(context.Background(), f, &out))
+
+	var result struct {
+		Namespaces []struct {
+			Metadata struct {
+				Name   string            `json:"name"`
+				Labels map[string]string `json:"labels"`
+			} `json:"metadata"`
+		} `json:"namespaces"`
+		ClusterResourceQuotas []struct {
+			Kind string `json:"kind"`
+		} `json:"clusterResourceQuotas"`
+		ClusterRoleBindings       []map[string]any          `json:"clusterRoleBindings"`
+		RoleBindings              []map[string]any          `json:"roleBindings"`
+		PluginGeneratorParameters map[string]map[string]any `json:"pluginGeneratorParameters"`
+	}
+	require.NoError(t, yaml.Unmarshal(out.Bytes(), &result))
+
+	var nsNames []string
+	for _, ns := range result.Namespaces {
+		nsNames = append(nsNames, ns.Metadata.Name)
+		assert.Equal(t, "acme", ns.Metadata.Labels["requestor"])
+	}
+	assert.Equal(t, []string{"my-paas-argocd", "my-paas-extra", "my-paas-test"}, nsNames)
+	assert.Len(t, result.ClusterResourceQuotas, 2)
+	assert.Equal(t, "ClusterResourceQuota", result.ClusterResourceQuotas[0].Kind)
+	assert.Len(t, result.ClusterRoleBindings, 1)
+	assert.Len(t, result.RoleBindings, 3)
+	assert.Equal(t, map[string]any{
+		"git_url":      "ssh://git@scm.org/repo.git",
+		"git_revision": "main",
+		"paas":         "my-paas",
+	}, result.PluginGeneratorParameters["argocd"])
+}
+
+func TestRun_InvalidInput(t *testing.T) {
+	var out bytes.Buffer
+	assert.ErrorContains(t, run(context.Background(), &flags{paasPath: "testdata/paas.yaml"}, &out),
+		"-paasconfig are required")
+	assert.ErrorContains(t, run(context.Background(), &flags{
+		paasPath:       "testdata/paasconfig.yaml",
+		paasConfigPath: "testdata/paasconfig.yaml",
+	}, &out), "does not hold a cpet.belastingdienst.nl/v1alpha2/Paas")
+	assert.ErrorContains(t, run(context.Background(), &flags{
+		paasPath:       "testdata/missing.yaml",
+		paasConfigPath: "testdata/paasconfig.yaml",
+	}, &out), "failed to read testdata/missing.yaml")
+	assert.Empty(t, out.String())
+}
@@ -140,6 +140,29 @@ func getCryptFunc(
 	}, nil
 }
 
+// CapElementsFromPaas returns the parameters which are generated for a capability of a Paas, exactly as Generate
+// would return them, without a connection to a cluster. When keys is nil, the decryptPaasSecret template function
+// returns an error, since secrets cannot be decrypted without the private keys.
+// It returns nil when the capability is not enabled in the Paas.
+func CapElementsFromPaas(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	capName string,
+	paasConfig v1alpha2.PaasConfig,
+	keys crypt.PrivateKeys,
+) (fields.ElementMap, error) {
+	decryptFunc := func(string) (string, error) {
+		return "", errors.New("no private keys to decrypt paas secrets")
+	}
+	if keys != nil {
+		var err error
+		if decryptFunc, err = getCryptFunc(keys, paas.Name); err != nil {
+			return nil, fmt.Errorf("failed to create decrypt func: %w", err)
+		}
+	}
+	return capElementsFromPaas(ctx, paas, capName, paasConfig, map[string]any{"decryptPaasSecret": decryptFunc})
+}
+
 func capElementsFromPaas(
 	ctx context.Context,
 	paas *v1alpha2.Paas,
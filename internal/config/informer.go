@@ -9,7 +9,9 @@ package config
 import (
 	"context"
 
+	toolscache "k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
@@ -20,16 +22,45 @@ type configInformer struct {
 	mgr manager.Manager
 }
 
-// SetupPaasConfigInformer will add an informer to the manager and inform on PaasConfig changes
+// SetupPaasConfigInformer will add an informer to the manager and inform on PaasConfig changes.
+// On every change, the debug settings of the active PaasConfig are applied to the runtime logging configuration.
 func SetupPaasConfigInformer(mgr manager.Manager) error {
 	// Adds informer for PaasConfig to force the cache to sync
-	_, err := mgr.GetCache().GetInformer(context.Background(), &v1alpha2.PaasConfig{})
+	informer, err := mgr.GetCache().GetInformer(context.Background(), &v1alpha2.PaasConfig{})
 	if err != nil {
 		return err
 	}
+	applyConfig := func(any) { applyLoggingConfig(context.Background(), mgr.GetClient()) }
+	if _, err = informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    applyConfig,
+		UpdateFunc: func(_, _ any) { applyConfig(nil) },
+		DeleteFunc: applyConfig,
+	}); err != nil {
+		return err
+	}
 	return mgr.Add(&configInformer{mgr: mgr})
 }
 
+// applyLoggingConfig applies the debug settings of the active PaasConfig to the runtime logging configuration.
+// When there is no active PaasConfig, the debug settings are reset.
+func applyLoggingConfig(ctx context.Context, c client.Client) {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ConfigComponent)
+	myConfig, err := GetConfig(ctx, c)
+	if err != nil {
+		logger.Debug().Err(err).Msg("no active config, resetting debug settings")
+		logging.SetDynamicLoggingConfig(false, nil)
+		return
+	}
+	logger.Debug().
+		Bool("debug", myConfig.Spec.Debug).
+		Any("components_debug", myConfig.Spec.ComponentsDebug).
+		Msg("applying debug settings from active config")
+	logging.SetDynamicLoggingConfig(
+		myConfig.Spec.Debug,
+		logging.NewComponentsFromStringMap(myConfig.Spec.ComponentsDebug),
+	)
+}
+
 // Start is the runnable for the PaasConfigInformer
 func (w *configInformer) Start(ctx context.Context) error {
 	ctx, logger := logging.GetLogComponent(ctx, logging.ConfigComponent)
@@ -0,0 +1,48 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/logging"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_applyLoggingConfig(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha2.AddToScheme(scheme))
+	activeConfig := &v1alpha2.PaasConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "paas-config"},
+		Spec: v1alpha2.PaasConfigSpec{
+			ComponentsDebug: map[string]bool{"unittest_component": true},
+		},
+		Status: v1alpha2.PaasConfigStatus{Conditions: []metav1.Condition{{
+			Type:   v1alpha2.TypeActivePaasConfig,
+			Status: metav1.ConditionTrue,
+		}}},
+	}
+	logging.SetStaticLoggingConfig(false, nil)
+	componentLevel := func() zerolog.Level {
+		_, logger := logging.GetLogComponent(ctx, logging.TestComponent)
+		return logger.GetLevel()
+	}
+
+	applyLoggingConfig(ctx, fake.NewClientBuilder().WithScheme(scheme).WithObjects(activeConfig).Build())
+	assert.Equal(t, zerolog.DebugLevel, componentLevel(), "component debugging should be enabled by the config")
+
+	applyLoggingConfig(ctx, fake.NewClientBuilder().WithScheme(scheme).Build())
+	assert.Equal(t, zerolog.InfoLevel, componentLevel(), "debugging should be reset without an active config")
+}
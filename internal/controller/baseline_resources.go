@@ -0,0 +1,287 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	"github.com/belastingdienst/opr-paas/v5/internal/logging"
+	"github.com/belastingdienst/opr-paas/v5/pkg/templating"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+// BaselineResourceLabelKey is the key of the label which holds the name of the baseline resource in the PaasConfig
+// that a resource was rendered from
+const BaselineResourceLabelKey = "paas.cpet.belastingdienst.nl/baseline-resource"
+
+// backendBaselineResource renders a baseline resource for a namespace of a Paas
+func (r *PaasReconciler) backendBaselineResource(
+	paas *v1alpha2.Paas,
+	templater templating.Templater[v1alpha2.Paas, v1alpha2.PaasConfig, v1alpha2.PaasConfigSpec],
+	name string,
+	baseline v1alpha2.ConfigBaselineResource,
+	nsDef namespaceDef,
+) (*unstructured.Unstructured, error) {
+	manifest, err := templater.WithNamespace(templating.Namespace{
+		Name:       nsDef.nsName,
+		Kind:       nsDef.kind(),
+		Capability: nsDef.capName,
+	}).TemplateToString(name, baseline.Template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to template baseline resource %s: %w", name, err)
+	}
+	obj := &unstructured.Unstructured{}
+	if err = yaml.Unmarshal([]byte(manifest), &obj.Object); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline resource %s: %w", name, err)
+	}
+	if obj.GetKind() == "" || obj.GetAPIVersion() == "" || obj.GetName() == "" {
+		return nil, fmt.Errorf("baseline resource %s should have an apiVersion, kind and metadata.name", name)
+	}
+	obj.SetNamespace(nsDef.nsName)
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[ManagedByLabelKey] = paas.Name
+	labels[BaselineResourceLabelKey] = name
+	obj.SetLabels(labels)
+	if err = controllerutil.SetControllerReference(paas, obj, r.Scheme); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// backendBaselineResources returns all baseline resources which are desired in a namespace of a Paas
+func (r *PaasReconciler) backendBaselineResources(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	nsDef namespaceDef,
+) (objs []*unstructured.Unstructured, err error) {
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	templater := templating.NewTemplater(*paas, myConfig)
+	for _, name := range slices.Sorted(maps.Keys(myConfig.Spec.BaselineResources)) {
+		baseline := myConfig.Spec.BaselineResources[name]
+		if !baseline.Namespaces.Matches(nsDef.kind(), nsDef.capName) {
+			continue
+		}
+		var obj *unstructured.Unstructured
+		if obj, err = r.backendBaselineResource(paas, templater, name, baseline, nsDef); err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// baselineResourceKinds returns the kinds which are checked for obsolete baseline resources. These are the kinds of
+// all baseline resources in the PaasConfig, and the kinds of the baseline resources in the inventory of the Paas, so
+// that baseline resources are also pruned after the last baseline resource of their kind is removed from the
+// PaasConfig. Baseline resources which cannot be rendered are skipped, since they cannot be reconciled either.
+func baselineResourceKinds(
+	paas *v1alpha2.Paas,
+	myConfig v1alpha2.PaasConfig,
+) (kinds []schema.GroupVersionKind) {
+	addKind := func(gvk schema.GroupVersionKind) {
+		if gvk.Kind != "" && !slices.Contains(kinds, gvk) {
+			kinds = append(kinds, gvk)
+		}
+	}
+	templater := templating.NewTemplater(*paas, myConfig)
+	for name, baseline := range myConfig.Spec.BaselineResources {
+		manifest, err := templater.TemplateToString(name, baseline.Template)
+		if err != nil {
+			continue
+		}
+		var typeMeta unstructured.Unstructured
+		if err = yaml.Unmarshal([]byte(manifest), &typeMeta.Object); err != nil {
+			continue
+		}
+		addKind(typeMeta.GroupVersionKind())
+	}
+	for _, item := range paas.Status.Inventory {
+		// Only baseline resources have an apiVersion in the inventory
+		if item.APIVersion != "" {
+			addKind(schema.FromAPIVersionAndKind(item.APIVersion, item.Kind))
+		}
+	}
+	return kinds
+}
+
+// isSubset returns true when all fields which are set in desired have the same value in found. Fields which are only
+// set in found (e.g. defaults and status which are set by the API server) are ignored.
+func isSubset(desired any, found any) bool {
+	switch d := desired.(type) {
+	case map[string]any:
+		f, ok := found.(map[string]any)
+		if !ok {
+			return false
+		}
+		for key, value := range d {
+			if !isSubset(value, f[key]) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		f, ok := found.([]any)
+		if !ok || len(f) != len(d) {
+			return false
+		}
+		for i := range d {
+			if !isSubset(d[i], f[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return equality.Semantic.DeepEqual(desired, found)
+	}
+}
+
+// baselineResourceUpToDate returns true when an existing baseline resource is owned by paas, has the labels and
+// annotations of the desired resource, and all other fields which are set in the desired resource
+func baselineResourceUpToDate(
+	paas *v1alpha2.Paas,
+	found *unstructured.Unstructured,
+	desired *unstructured.Unstructured,
+) bool {
+	desiredFields := maps.Clone(desired.Object)
+	delete(desiredFields, "metadata")
+	return paas.AmIOwner(found.GetOwnerReferences()) && maps.Equal(found.GetLabels(), desired.GetLabels()) &&
+		maps.Equal(found.GetAnnotations(), desired.GetAnnotations()) && isSubset(desiredFields, found.Object)
+}
+
+// ensureBaselineResource ensures the presence of a baseline resource, which is created, or overwritten when it
+// differs from the desired resource
+func (r *PaasReconciler) ensureBaselineResource(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	obj *unstructured.Unstructured,
+) error {
+	found := &unstructured.Unstructured{}
+	found.SetGroupVersionKind(obj.GroupVersionKind())
+	err := r.Get(ctx, client.ObjectKeyFromObject(obj), found)
+	if err != nil && errors.IsNotFound(err) {
+		err = r.Create(ctx, obj)
+		r.recordEvent(paas, obj, eventActionCreate, err)
+		return err
+	} else if err != nil {
+		return err
+	}
+	if baselineResourceUpToDate(paas, found, obj) {
+		return nil
+	}
+	obj.SetResourceVersion(found.GetResourceVersion())
+	err = r.Update(ctx, obj)
+	r.recordEvent(paas, obj, eventActionUpdate, err)
+	return err
+}
+
+// obsoleteBaselineResources returns all baseline resources of a Paas in a namespace, which are not desired
+func (r *PaasReconciler) obsoleteBaselineResources(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	namespace string,
+	kinds []schema.GroupVersionKind,
+	desired []*unstructured.Unstructured,
+) (obsolete []unstructured.Unstructured, err error) {
+	for _, gvk := range kinds {
+		existing := &unstructured.UnstructuredList{}
+		existing.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err = r.List(ctx, existing,
+			client.InNamespace(namespace),
+			client.MatchingLabels{ManagedByLabelKey: paas.Name},
+			client.HasLabels{BaselineResourceLabelKey},
+		); meta.IsNoMatchError(err) {
+			// The kind is no longer known in the cluster, so there is nothing left to prune
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to list %s baseline resources in %s: %w", gvk.Kind, namespace, err)
+		}
+		for _, obj := range existing.Items {
+			if !slices.ContainsFunc(desired, func(d *unstructured.Unstructured) bool {
+				return d.GroupVersionKind() == obj.GroupVersionKind() && d.GetName() == obj.GetName()
+			}) {
+				obsolete = append(obsolete, obj)
+			}
+		}
+	}
+	return obsolete, nil
+}
+
+// deleteObsoleteBaselineResources deletes all baseline resources of a Paas in a namespace, which are not desired
+func (r *PaasReconciler) deleteObsoleteBaselineResources(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	namespace string,
+	kinds []schema.GroupVersionKind,
+	desired []*unstructured.Unstructured,
+) error {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerNamespaceComponent)
+	obsolete, err := r.obsoleteBaselineResources(ctx, paas, namespace, kinds, desired)
+	if err != nil {
+		return err
+	}
+	for _, obj := range obsolete {
+		err = r.Delete(ctx, &obj)
+		r.recordEvent(paas, &obj, eventActionDelete, err)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		logger.Info().Str("kind", obj.GetKind()).Str("name", obj.GetName()).Msg("deleted obsolete baseline resource")
+	}
+	return nil
+}
+
+// reconcileBaselineResources applies the baseline resources from the PaasConfig in all namespaces of a Paas, and
+// deletes baseline resources which are no longer desired. When obsolete namespaces are deleted, that cascade deletes
+// the baseline resources in that namespace.
+func (r *PaasReconciler) reconcileBaselineResources(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	nsDefs namespaceDefs,
+) error {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerNamespaceComponent)
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	kinds := baselineResourceKinds(paas, myConfig)
+	for _, nsDef := range nsDefs {
+		var desired []*unstructured.Unstructured
+		if desired, err = r.backendBaselineResources(ctx, paas, nsDef); err != nil {
+			return newNamespaceError(nsDef.nsName, err)
+		}
+		if err = r.deleteObsoleteBaselineResources(ctx, paas, nsDef.nsName, kinds, desired); err != nil {
+			return newNamespaceError(nsDef.nsName, err)
+		}
+		for _, obj := range desired {
+			if err = r.ensureBaselineResource(ctx, paas, obj); err != nil {
+				err = fmt.Errorf("failure while reconciling baseline resource %s %s/%s: %w",
+					obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+				return newNamespaceError(nsDef.nsName, err)
+			}
+		}
+		logger.Debug().Msgf("%d baseline resources reconciled in namespace %s", len(desired), nsDef.nsName)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Baseline resources", Ordered, func() {
+	const (
+		paasName = "baseline-paas"
+		capName  = "argocd"
+		nsName   = "app"
+		limits   = "limits"
+		denyAll  = "deny-all"
+	)
+	var (
+		ctx        context.Context
+		paas       *v1alpha2.Paas
+		reconciler *PaasReconciler
+		myConfig   v1alpha2.PaasConfig
+		nsDefs     namespaceDefs
+		appNs      = join(paasName, nsName)
+		capNs      = join(paasName, capName)
+	)
+
+	reconcileWith := func(baselineResources map[string]v1alpha2.ConfigBaselineResource) {
+		myConfig.Spec.BaselineResources = baselineResources
+		ctx = context.WithValue(context.Background(), config.ContextKeyPaasConfig, myConfig)
+		var err error
+		nsDefs, err = reconciler.nsDefsFromPaas(ctx, paas)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.reconcileBaselineResources(ctx, paas, nsDefs)).To(Succeed())
+	}
+	getLimitRange := func(namespace string) (*corev1.LimitRange, error) {
+		lr := &corev1.LimitRange{}
+		return lr, reconciler.Get(ctx, types.NamespacedName{Namespace: namespace, Name: limits}, lr)
+	}
+
+	BeforeAll(func() {
+		ctx = context.Background()
+		reconciler = &PaasReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+		myConfig = v1alpha2.PaasConfig{
+			Spec: v1alpha2.PaasConfigSpec{
+				Capabilities: v1alpha2.ConfigCapabilities{capName: v1alpha2.ConfigCapability{
+					QuotaSettings: v1alpha2.ConfigQuotaSettings{
+						DefQuota: map[corev1.ResourceName]resourcev1.Quantity{
+							corev1.ResourceLimitsCPU: resourcev1.MustParse("1"),
+						},
+					},
+				}},
+			},
+		}
+		paas = &v1alpha2.Paas{
+			ObjectMeta: metav1.ObjectMeta{Name: paasName},
+			Spec: v1alpha2.PaasSpec{
+				Requestor:    paasName,
+				Capabilities: v1alpha2.PaasCapabilities{capName: v1alpha2.PaasCapability{}},
+				Namespaces:   v1alpha2.PaasNamespaces{nsName: v1alpha2.PaasNamespace{}},
+			},
+		}
+		assurePaas(ctx, *paas)
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: paasName}, paas)).To(Succeed())
+		assureNamespace(ctx, appNs)
+		assureNamespace(ctx, capNs)
+	})
+
+	limitRange := v1alpha2.ConfigBaselineResource{
+		Template: `apiVersion: v1
+kind: LimitRange
+metadata:
+  name: ` + limits + `
+  namespace: somewhere-else
+  labels:
+    namespace-kind: "{{ .Namespace.Kind }}"
+spec:
+  limits:
+    - type: Container
+      default:
+        cpu: 500m`,
+	}
+	networkPolicy := v1alpha2.ConfigBaselineResource{
+		Template: `apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: ` + denyAll + `
+spec:
+  podSelector: {}
+  policyTypes: [Ingress]`,
+		Namespaces: v1alpha2.ConfigNamespaceSelector{
+			Kinds:        []v1alpha2.ConfigNamespaceKind{v1alpha2.ConfigNamespaceKindCapability},
+			Capabilities: []string{capName},
+		},
+	}
+
+	It("applies baseline resources in the selected namespaces", func() {
+		reconcileWith(map[string]v1alpha2.ConfigBaselineResource{limits: limitRange, denyAll: networkPolicy})
+
+		for namespace, kind := range map[string]string{appNs: "Paas", capNs: "Capability"} {
+			lr, err := getLimitRange(namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lr.Labels).To(HaveKeyWithValue(ManagedByLabelKey, paasName))
+			Expect(lr.Labels).To(HaveKeyWithValue(BaselineResourceLabelKey, limits))
+			Expect(lr.Labels).To(HaveKeyWithValue("namespace-kind", kind))
+			Expect(paas.AmIOwner(lr.OwnerReferences)).To(BeTrue())
+		}
+
+		np := &networkingv1.NetworkPolicy{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: capNs, Name: denyAll}, np)).To(Succeed())
+		err := reconciler.Get(ctx, types.NamespacedName{Namespace: appNs, Name: denyAll}, np)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("restores baseline resources which are changed by hand", func() {
+		lr, err := getLimitRange(appNs)
+		Expect(err).NotTo(HaveOccurred())
+		lr.Labels["namespace-kind"] = "changed"
+		Expect(reconciler.Update(ctx, lr)).To(Succeed())
+
+		reconcileWith(map[string]v1alpha2.ConfigBaselineResource{limits: limitRange, denyAll: networkPolicy})
+		lr, err = getLimitRange(appNs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lr.Labels).To(HaveKeyWithValue("namespace-kind", "Paas"))
+	})
+
+	It("does not update baseline resources which are unchanged", func() {
+		lr, err := getLimitRange(appNs)
+		Expect(err).NotTo(HaveOccurred())
+		reconcileWith(map[string]v1alpha2.ConfigBaselineResource{limits: limitRange, denyAll: networkPolicy})
+		unchanged, err := getLimitRange(appNs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(unchanged.ResourceVersion).To(Equal(lr.ResourceVersion))
+	})
+
+	It("deletes baseline resources which are no longer desired, and leaves other resources", func() {
+		unmanaged := &corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: "unmanaged", Namespace: appNs},
+			Spec:       corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{Type: corev1.LimitTypeContainer}}},
+		}
+		Expect(reconciler.Create(ctx, unmanaged)).To(Succeed())
+
+		limitRange.Namespaces = v1alpha2.ConfigNamespaceSelector{
+			Kinds: []v1alpha2.ConfigNamespaceKind{v1alpha2.ConfigNamespaceKindCapability},
+		}
+		reconcileWith(map[string]v1alpha2.ConfigBaselineResource{limits: limitRange, denyAll: networkPolicy})
+
+		_, err := getLimitRange(appNs)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		_, err = getLimitRange(capNs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: appNs, Name: "unmanaged"},
+			&corev1.LimitRange{})).To(Succeed())
+	})
+
+	It("deletes baseline resources of a kind which is no longer in the PaasConfig", func() {
+		paas.Status.Inventory = []v1alpha2.PaasInventoryItem{{
+			Kind:       "NetworkPolicy",
+			APIVersion: "networking.k8s.io/v1",
+			Name:       denyAll,
+			Namespace:  capNs,
+		}}
+		DeferCleanup(func() { paas.Status.Inventory = nil })
+		reconcileWith(map[string]v1alpha2.ConfigBaselineResource{limits: limitRange})
+
+		err := reconciler.Get(ctx, types.NamespacedName{Namespace: capNs, Name: denyAll}, &networkingv1.NetworkPolicy{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("fails on baseline resources which cannot be parsed", func() {
+		myConfig.Spec.BaselineResources = map[string]v1alpha2.ConfigBaselineResource{
+			"invalid": {Template: "metadata:\n  name: no-kind"},
+		}
+		ctx = context.WithValue(context.Background(), config.ContextKeyPaasConfig, myConfig)
+		err := reconciler.reconcileBaselineResources(ctx, paas, nsDefs)
+		Expect(err).To(MatchError(ContainSubstring("should have an apiVersion, kind and metadata.name")))
+	})
+})
@@ -9,18 +9,17 @@ package controller
 import (
 	"context"
 	"errors"
-	"maps"
 
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v5/internal/config"
 	"github.com/belastingdienst/opr-paas/v5/internal/logging"
-	"github.com/belastingdienst/opr-paas/v5/pkg/fields"
 	paasquota "github.com/belastingdienst/opr-paas/v5/pkg/quota"
 	"github.com/belastingdienst/opr-paas/v5/pkg/templating"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	quotav1 "github.com/openshift/api/quota/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,6 +29,7 @@ import (
 
 func (r *PaasReconciler) ensureQuota(
 	ctx context.Context,
+	paas *v1alpha2.Paas,
 	quota *quotav1.ClusterResourceQuota,
 ) error {
 	// See if quota already exists and create if it doesn't
@@ -37,7 +37,9 @@ func (r *PaasReconciler) ensureQuota(
 	err := r.Get(ctx, client.ObjectKeyFromObject(quota), found)
 	if err != nil && k8serrors.IsNotFound(err) {
 		// Create the quota
-		if err = r.Create(ctx, quota); err != nil {
+		err = r.Create(ctx, quota)
+		r.recordEvent(paas, quota, eventActionCreate, err)
+		if err != nil {
 			// creating the quota failed
 			return err
 		}
@@ -47,10 +49,18 @@ func (r *PaasReconciler) ensureQuota(
 		// Error that isn't due to the quota not existing
 		return err
 	}
-	// Update the quota
+	// Update the quota, when it differs from the desired quota
+	labelsChanged := ensureLabels(found, quota)
+	annotationsChanged := ensureAnnotations(found, quota)
+	if !labelsChanged && !annotationsChanged && paas.AmIOwner(found.OwnerReferences) &&
+		equality.Semantic.DeepEqual(found.Spec, quota.Spec) {
+		return nil
+	}
 	found.OwnerReferences = quota.OwnerReferences
 	found.Spec = quota.Spec
-	if err = r.Update(ctx, found); err != nil {
+	err = r.Update(ctx, found)
+	r.recordEvent(paas, found, eventActionUpdate, err)
+	if err != nil {
 		// updating the quota failed
 		return err
 	}
@@ -73,27 +83,27 @@ func (r *PaasReconciler) backendQuota(
 	_, logger := logging.GetLogComponent(ctx, logging.ControllerClusterQuotaComponent)
 	logger.Info().Msg("defining quota")
 
-	labels := map[string]string{}
 	myConfig, err := config.GetConfigFromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
-	labelTemplater := templating.NewTemplater(*paas, myConfig)
-	for name, tpl := range myConfig.Spec.Templating.ClusterQuotaLabels {
-		var result fields.ElementMap
-		result, err = labelTemplater.TemplateToMap(name, tpl)
-		if err != nil {
-			return nil, err
-		}
-		r := result.AsElementMap()
-		maps.Copy(labels, r.AsLabels())
+	templater := templating.NewTemplater(*paas, myConfig)
+	labels, err := templater.TemplateItemsToMap(myConfig.Spec.Templating.ClusterQuotaLabels)
+	if err != nil {
+		return nil, err
+	}
+	labels[ManagedByLabelKey] = paas.Name
+	annotations, err := templater.TemplateItemsToMap(myConfig.Spec.Templating.ClusterQuotaAnnotations)
+	if err != nil {
+		return nil, err
 	}
 
 	// matchLabels := map[string]string{"dcs.itsmoplosgroep": paas.Name}
 	quota := &quotav1.ClusterResourceQuota{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:   quotaName,
-			Labels: labels,
+			Name:        quotaName,
+			Labels:      labels,
+			Annotations: annotations,
 		},
 		Spec: quotav1.ClusterResourceQuotaSpec{
 			Selector: quotav1.ClusterResourceQuotaSelector{
@@ -219,7 +229,7 @@ func (r *PaasReconciler) reconcileQuotas(
 	}
 	for _, q := range quotas {
 		logger.Info().Msg("creating quota " + q.Name + " for PAAS object ")
-		if err = r.ensureQuota(ctx, q); err != nil {
+		if err = r.ensureQuota(ctx, paas, q); err != nil {
 			logger.Err(err).Msgf("failure while creating quota %s", q.Name)
 			return err
 		}
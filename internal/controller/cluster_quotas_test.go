@@ -163,8 +163,9 @@ var _ = Describe("Cluster Quotas", Ordered, func() {
 
 		It("have set all expected labels", func() {
 			expectedLabels := map[string]string{
-				lbl1Key: lbl1Value,
-				lbl2Key: lbl2Value,
+				lbl1Key:           lbl1Value,
+				lbl2Key:           lbl2Value,
+				ManagedByLabelKey: paasName,
 			}
 			for _, quotaName := range expectedQuotas {
 				fmt.Fprintf(GinkgoWriter, "DEBUG - Quota: %v\n", quotaName)
@@ -93,11 +93,17 @@ func (r *PaasReconciler) updateClusterWideQuotaResources(
 	if err != nil {
 		return err
 	}
-	quota.Spec.Quota.Hard = corev1.ResourceList(allPaasResources.OptimalValues(
-		c.QuotaSettings.Ratio,
+	aggregation, overrides := c.QuotaSettings.Aggregations()
+	hard, err := allPaasResources.AggregatedValues(
+		aggregation,
+		overrides,
 		c.QuotaSettings.MinQuotas,
 		c.QuotaSettings.MaxQuotas,
-	))
+	)
+	if err != nil {
+		return err
+	}
+	quota.Spec.Quota.Hard = corev1.ResourceList(hard)
 	return nil
 }
 
@@ -9,6 +9,8 @@ package controller
 import (
 	"context"
 	"fmt"
+	"maps"
+	"reflect"
 	"regexp"
 	"slices"
 	"strings"
@@ -16,20 +18,28 @@ import (
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v5/internal/config"
 	"github.com/belastingdienst/opr-paas/v5/internal/logging"
+	corev1 "k8s.io/api/core/v1"
 	rbac "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-const crbNamePrefix string = "paas"
+const (
+	crbNamePrefix   string = "paas"
+	crbTypeLabelKey string = "paas.belastingdienst.nl/crb-type"
+	// perPaasCRBType is the crb-type of ClusterRoleBindings in the PerPaas layout. It differs from the crb-type of
+	// the shared ClusterRoleBindings, so that these are never cleaned up as shared ClusterRoleBindings.
+	perPaasCRBType string = "paas-capability"
+)
 
 // TODO are these labels still correct?
 var defaultCRBLabels = map[string]string{
-	"app.kubernetes.io/created-by":     "opr-paas",
-	"app.kubernetes.io/part-of":        "opr-paas",
-	"paas.belastingdienst.nl/crb-type": "capability",
+	"app.kubernetes.io/created-by": "opr-paas",
+	"app.kubernetes.io/part-of":    "opr-paas",
+	crbTypeLabelKey:                "capability",
 }
 
 func (r *PaasReconciler) getClusterRoleBinding(
@@ -96,6 +106,49 @@ func backendClusterRoleBinding(
 	return rb
 }
 
+// paasClusterRoleBindingName returns the name of the ClusterRoleBinding for role in the PerPaas layout. Since Paas
+// names and role names may both contain dashes, the name has a hash suffix of the Paas and role, so that e.g. Paas
+// `a` with role `b-c` and Paas `a-b` with role `c` get different ClusterRoleBindings.
+func paasClusterRoleBindingName(paasName string, role string) string {
+	return join(crbNamePrefix, paasName, role, hashData(paasName + "/" + role)[:8])
+}
+
+// backendPaasClusterRoleBinding returns a ClusterRoleBinding without subjects for role, in the PerPaas layout
+func backendPaasClusterRoleBinding(
+	paas *v1alpha2.Paas,
+	role string,
+) *rbac.ClusterRoleBinding {
+	return &rbac.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: paasClusterRoleBindingName(paas.Name, role),
+			Labels: map[string]string{
+				"app.kubernetes.io/created-by": "opr-paas",
+				"app.kubernetes.io/part-of":    "opr-paas",
+				crbTypeLabelKey:                perPaasCRBType,
+				ManagedByLabelKey:              paas.Name,
+			},
+		},
+		RoleRef: rbac.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     role,
+		},
+	}
+}
+
+// backendLayoutClusterRoleBinding returns a ClusterRoleBinding without subjects for role, in the layout which is
+// configured in myConfig
+func backendLayoutClusterRoleBinding(
+	myConfig v1alpha2.PaasConfig,
+	paas *v1alpha2.Paas,
+	role string,
+) *rbac.ClusterRoleBinding {
+	if myConfig.Spec.ClusterRoleBindingLayout == v1alpha2.ConfigClusterRoleBindingLayoutPerPaas {
+		return backendPaasClusterRoleBinding(paas, role)
+	}
+	return backendClusterRoleBinding(role)
+}
+
 func addSAToClusterRoleBinding(
 	crb *rbac.ClusterRoleBinding,
 	namespace string,
@@ -163,6 +216,27 @@ func addOrUpdateCrb(
 	return changed
 }
 
+// capabilityPermissions returns all roles and service accounts which should be bound for a capability in scope,
+// being the default permissions and (when enabled in the Paas) the extra permissions. Service accounts of roles
+// which are granted in another scope are returned as not to be bound, so that they are cleaned.
+func capabilityPermissions(
+	capConfig v1alpha2.ConfigCapability,
+	capability v1alpha2.PaasCapability,
+	scope v1alpha2.ConfigPermissionScope,
+) v1alpha2.ConfigRolesSas {
+	permissions := capConfig.ExtraPermissions.AsConfigRolesSas(capability.ExtraPermissions)
+	permissions.Merge(capConfig.DefaultPermissions.AsConfigRolesSas(true))
+	for role, sas := range permissions {
+		if capConfig.PermissionScope(role) == scope {
+			continue
+		}
+		for sa := range sas {
+			sas[sa] = false
+		}
+	}
+	return permissions
+}
+
 func (r *PaasReconciler) reconcileClusterRoleBinding(
 	ctx context.Context,
 	paas *v1alpha2.Paas,
@@ -181,8 +255,7 @@ func (r *PaasReconciler) reconcileClusterRoleBinding(
 	}
 
 	ctx, _ = logging.GetLogComponent(ctx, logging.ControllerClusterRoleBindingsComponent)
-	permissions := capConfig.ExtraPermissions.AsConfigRolesSas(capability.ExtraPermissions)
-	permissions.Merge(capConfig.DefaultPermissions.AsConfigRolesSas(true))
+	permissions := capabilityPermissions(capConfig, capability, v1alpha2.ConfigPermissionScopeCluster)
 	for role, sas := range permissions {
 		if crb, err = r.getClusterRoleBinding(ctx, role); err != nil {
 			return err
@@ -302,13 +375,116 @@ func (r *PaasReconciler) reconcileClusterRoleBindings(
 	paas *v1alpha2.Paas,
 	nsDefs namespaceDefs,
 ) (err error) {
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if myConfig.Spec.ClusterRoleBindingLayout == v1alpha2.ConfigClusterRoleBindingLayoutPerPaas {
+		return r.reconcilePaasClusterRoleBindings(ctx, myConfig, paas, nsDefs)
+	}
 	for _, nsDef := range nsDefs {
 		err = r.reconcileClusterRoleBinding(ctx, paas, nsDef.nsName, nsDef.capName)
 		if err != nil {
+			return newNamespaceError(nsDef.nsName, err)
+		}
+	}
+	if err = r.finalizeCapClusterRoleBindings(ctx, paas); err != nil {
+		return err
+	}
+	// Migrate from the PerPaas layout, now that the service accounts are bound in the shared ClusterRoleBindings
+	return r.cleanPaasClusterRoleBindings(ctx, paas, nil)
+}
+
+// reconcilePaasClusterRoleBindings ensures the ClusterRoleBindings of a Paas in the PerPaas layout. Afterwards the
+// service accounts of the Paas are removed from the shared ClusterRoleBindings, which migrates a Paas from the
+// Shared layout.
+func (r *PaasReconciler) reconcilePaasClusterRoleBindings(
+	ctx context.Context,
+	myConfig v1alpha2.PaasConfig,
+	paas *v1alpha2.Paas,
+	nsDefs namespaceDefs,
+) error {
+	ctx, _ = logging.GetLogComponent(ctx, logging.ControllerClusterRoleBindingsComponent)
+	crbs := renderClusterRoleBindings(ctx, myConfig, paas, nsDefs)
+	for _, crb := range crbs {
+		if err := r.ensurePaasClusterRoleBinding(ctx, paas, crb); err != nil {
 			return err
 		}
 	}
-	return r.finalizeCapClusterRoleBindings(ctx, paas)
+	if err := r.cleanPaasClusterRoleBindings(ctx, paas, crbs); err != nil {
+		return err
+	}
+	return r.finalizePaasClusterRoleBindings(ctx, paas)
+}
+
+// ensurePaasClusterRoleBinding ensures that a ClusterRoleBinding in the PerPaas layout exists and is owned by paas.
+// An existing ClusterRoleBinding which is not owned by paas is only adopted when it was released by a retained Paas
+// (it has no controller, and the crb-type of the PerPaas layout). Any other ClusterRoleBinding is never taken over.
+func (r *PaasReconciler) ensurePaasClusterRoleBinding(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	crb *rbac.ClusterRoleBinding,
+) error {
+	_, logger := logging.GetLogComponent(ctx, logging.ControllerClusterRoleBindingsComponent)
+	if err := controllerutil.SetControllerReference(paas, crb, r.getScheme()); err != nil {
+		return err
+	}
+	found := &rbac.ClusterRoleBinding{}
+	err := r.Get(ctx, types.NamespacedName{Name: crb.Name}, found)
+	if err != nil && errors.IsNotFound(err) {
+		logger.Info().Msgf("creating new ClusterRoleBinding %s", crb.Name)
+		err = r.Create(ctx, crb)
+		r.recordEvent(paas, crb, eventActionCreate, err)
+		return err
+	} else if err != nil {
+		return err
+	}
+	owned := paas.AmIOwner(found.OwnerReferences)
+	released := metav1.GetControllerOf(found) == nil && found.Labels[crbTypeLabelKey] == perPaasCRBType
+	if !owned && !released {
+		return fmt.Errorf("clusterrolebinding %s already exists and is not owned by paas %s", crb.Name, paas.Name)
+	}
+	if owned && reflect.DeepEqual(found.Subjects, crb.Subjects) && maps.Equal(found.Labels, crb.Labels) {
+		return nil
+	}
+	logger.Info().Msgf("updating existing ClusterRoleBinding %s", crb.Name)
+	found.Subjects = crb.Subjects
+	found.Labels = crb.Labels
+	if !owned {
+		found.OwnerReferences = append(found.OwnerReferences, crb.OwnerReferences...)
+	}
+	err = r.Update(ctx, found)
+	r.recordEvent(paas, found, eventActionUpdate, err)
+	return err
+}
+
+// cleanPaasClusterRoleBindings deletes all ClusterRoleBindings of a Paas in the PerPaas layout, except for keep
+func (r *PaasReconciler) cleanPaasClusterRoleBindings(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	keep []*rbac.ClusterRoleBinding,
+) error {
+	_, logger := logging.GetLogComponent(ctx, logging.ControllerClusterRoleBindingsComponent)
+	crbs, err := r.getClusterRoleBindingsWithLabel(ctx, client.MatchingLabels{
+		crbTypeLabelKey:   perPaasCRBType,
+		ManagedByLabelKey: paas.Name,
+	})
+	if err != nil {
+		return err
+	}
+	for _, crb := range crbs.Items {
+		if !paas.AmIOwner(crb.OwnerReferences) ||
+			slices.ContainsFunc(keep, func(k *rbac.ClusterRoleBinding) bool { return k.Name == crb.Name }) {
+			continue
+		}
+		logger.Info().Msgf("deleting ClusterRoleBinding %s", crb.Name)
+		err = r.Delete(ctx, &crb)
+		r.recordEvent(paas, &crb, eventActionDelete, err)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
 }
 
 func subjectsFromCrb(crb rbac.ClusterRoleBinding) []string {
@@ -376,17 +552,46 @@ func (r *PaasReconciler) finalizePaasClusterRoleBindings(
 	if err != nil {
 		return err
 	}
+	nsRE, err := r.paasNamespacesRE(ctx, myConfig, paas)
+	if err != nil {
+		return err
+	}
 	var capRoles []string
 	for _, capConfig := range myConfig.Spec.Capabilities {
 		capRoles = append(capRoles, capConfig.ExtraPermissions.Roles()...)
 		capRoles = append(capRoles, capConfig.DefaultPermissions.Roles()...)
 	}
 	for _, role := range capRoles {
-		re := regexp.MustCompile(fmt.Sprintf("^%s-", paas.Name))
-		err = r.finalizeClusterRoleBinding(ctx, role, *re)
+		err = r.finalizeClusterRoleBinding(ctx, role, *nsRE)
 		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// paasNamespacesRE returns a regular expression which exactly matches the namespaces of a Paas, being the namespaces
+// of all capabilities in the PaasConfig, and all namespaces which are managed by the Paas. Matching on the name of the
+// Paas as a prefix would also match the namespaces of other Paas'es (e.g. `my-paas-` matches `my-paas-two-argocd`).
+func (r *PaasReconciler) paasNamespacesRE(
+	ctx context.Context,
+	myConfig v1alpha2.PaasConfig,
+	paas *v1alpha2.Paas,
+) (*regexp.Regexp, error) {
+	nsNames := map[string]bool{}
+	for capName := range myConfig.Spec.Capabilities {
+		nsNames[join(paas.Name, capName)] = true
+	}
+	var nss corev1.NamespaceList
+	if err := r.List(ctx, &nss, client.MatchingLabels{ManagedByLabelKey: paas.Name}); err != nil {
+		return nil, err
+	}
+	for _, ns := range nss.Items {
+		nsNames[ns.Name] = true
+	}
+	var quoted []string
+	for _, nsName := range slices.Sorted(maps.Keys(nsNames)) {
+		quoted = append(quoted, regexp.QuoteMeta(nsName))
+	}
+	return regexp.MustCompile(fmt.Sprintf("^(%s)$", strings.Join(quoted, "|"))), nil
+}
@@ -315,3 +315,144 @@ var _ = Describe("Clusterrolebindings", Ordered, func() {
 		})
 	})
 })
+
+var _ = Describe("Per-Paas clusterrolebindings", Ordered, func() {
+	const (
+		capName   = "crbpp"
+		role      = capName + "-view"
+		paasName  = capName + "-test"
+		capNSName = paasName + "-" + capName
+	)
+	var (
+		ctx          context.Context
+		paas         *v1alpha2.Paas
+		nsDefs       namespaceDefs
+		reconciler   *PaasReconciler
+		paasConfig   v1alpha2.PaasConfig
+		sharedName   = join("paas", role)
+		perPaasName  = paasClusterRoleBindingName(paasName, role)
+		saSubject    = rbac.Subject{Kind: "ServiceAccount", Name: capName, Namespace: capNSName}
+		reconcileFor = func(layout v1alpha2.ConfigClusterRoleBindingLayout) {
+			paasConfig.Spec.ClusterRoleBindingLayout = layout
+			ctx = context.WithValue(context.Background(), config.ContextKeyPaasConfig, paasConfig)
+			Expect(reconciler.reconcileClusterRoleBindings(ctx, paas, nsDefs)).To(Succeed())
+		}
+	)
+	BeforeAll(func() {
+		capConfig := v1alpha2.ConfigCapability{
+			AppSet:             capName + "-as",
+			DefaultPermissions: v1alpha2.ConfigCapPerm{capName: []string{role}},
+		}
+		paasConfig = v1alpha2.PaasConfig{
+			Spec: v1alpha2.PaasConfigSpec{
+				Capabilities: map[string]v1alpha2.ConfigCapability{capName: capConfig},
+			},
+		}
+		reconciler = &PaasReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+		paas = &v1alpha2.Paas{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: paasName,
+				UID:  "crbpp-uid",
+			},
+			Spec: v1alpha2.PaasSpec{
+				Requestor:    capName,
+				Capabilities: v1alpha2.PaasCapabilities{capName: v1alpha2.PaasCapability{}},
+			},
+		}
+		nsDefs = namespaceDefs{
+			capNSName: namespaceDef{nsName: capNSName, capName: capName, capConfig: capConfig},
+		}
+	})
+
+	It("binds the service accounts in the shared clusterRoleBinding with the Shared layout", func() {
+		reconcileFor(v1alpha2.ConfigClusterRoleBindingLayoutShared)
+		var crb rbac.ClusterRoleBinding
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: sharedName}, &crb)).To(Succeed())
+		Expect(crb.Subjects).To(ContainElement(saSubject))
+	})
+
+	It("migrates the service accounts to a clusterRoleBinding owned by the Paas with the PerPaas layout", func() {
+		reconcileFor(v1alpha2.ConfigClusterRoleBindingLayoutPerPaas)
+		var crb rbac.ClusterRoleBinding
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: perPaasName}, &crb)).To(Succeed())
+		Expect(crb.Subjects).To(ConsistOf(saSubject))
+		Expect(crb.RoleRef.Name).To(Equal(role))
+		Expect(crb.Labels).To(HaveKeyWithValue(ManagedByLabelKey, paasName))
+		Expect(paas.AmIOwner(crb.OwnerReferences)).To(BeTrue())
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: sharedName}, &crb)).
+			To(MatchError(ContainSubstring("not found")))
+	})
+
+	It("removes the clusterRoleBinding of the Paas when the capability is disabled", func() {
+		capNsDefs := nsDefs
+		delete(paas.Spec.Capabilities, capName)
+		nsDefs = namespaceDefs{}
+		reconcileFor(v1alpha2.ConfigClusterRoleBindingLayoutPerPaas)
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: perPaasName}, &rbac.ClusterRoleBinding{})).
+			To(MatchError(ContainSubstring("not found")))
+		paas.Spec.Capabilities = v1alpha2.PaasCapabilities{capName: v1alpha2.PaasCapability{}}
+		nsDefs = capNsDefs
+		reconcileFor(v1alpha2.ConfigClusterRoleBindingLayoutPerPaas)
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: perPaasName}, &rbac.ClusterRoleBinding{})).
+			To(Succeed())
+	})
+
+	It("migrates the service accounts back to the shared clusterRoleBinding with the Shared layout", func() {
+		reconcileFor(v1alpha2.ConfigClusterRoleBindingLayoutShared)
+		var crb rbac.ClusterRoleBinding
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: sharedName}, &crb)).To(Succeed())
+		Expect(crb.Subjects).To(ContainElement(saSubject))
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: perPaasName}, &crb)).
+			To(MatchError(ContainSubstring("not found")))
+	})
+
+	It("uses different names for roles of Paas'es with overlapping names", func() {
+		Expect(paasClusterRoleBindingName("a", "b-c")).NotTo(Equal(paasClusterRoleBindingName("a-b", "c")))
+	})
+
+	It("does not take over a clusterRoleBinding which is not owned by the Paas", func() {
+		foreign := &rbac.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: paasClusterRoleBindingName(paasName, "foreign")},
+			RoleRef:    rbac.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "foreign"},
+			Subjects:   []rbac.Subject{{Kind: "User", Name: "someone"}},
+		}
+		Expect(k8sClient.Create(ctx, foreign)).To(Succeed())
+		DeferCleanup(func() { Expect(k8sClient.Delete(ctx, foreign)).To(Succeed()) })
+		err := reconciler.ensurePaasClusterRoleBinding(ctx, paas, backendPaasClusterRoleBinding(paas, "foreign"))
+		Expect(err).To(MatchError(ContainSubstring("is not owned by paas " + paasName)))
+		var crb rbac.ClusterRoleBinding
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: foreign.Name}, &crb)).To(Succeed())
+		Expect(crb.Subjects).To(Equal(foreign.Subjects))
+		Expect(crb.OwnerReferences).To(BeEmpty())
+	})
+
+	It("only matches the namespaces of the Paas itself", func() {
+		nsRE, err := reconciler.paasNamespacesRE(ctx, paasConfig, paas)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nsRE.MatchString(capNSName)).To(BeTrue())
+		Expect(nsRE.MatchString(join(paasName, "other", capName))).To(BeFalse())
+		Expect(nsRE.MatchString(join(paasName, capName, "other"))).To(BeFalse())
+	})
+
+	It("no longer binds roles cluster-wide when they are granted in another scope", func() {
+		capConfig := paasConfig.Spec.Capabilities[capName]
+		capConfig.PermissionScopes = map[string]v1alpha2.ConfigPermissionScope{role: v1alpha2.ConfigPermissionScopePaas}
+		paasConfig.Spec.Capabilities[capName] = capConfig
+		nsDefs = namespaceDefs{
+			capNSName: namespaceDef{nsName: capNSName, capName: capName, capConfig: capConfig},
+		}
+		for _, layout := range []v1alpha2.ConfigClusterRoleBindingLayout{
+			v1alpha2.ConfigClusterRoleBindingLayoutShared,
+			v1alpha2.ConfigClusterRoleBindingLayoutPerPaas,
+		} {
+			reconcileFor(layout)
+			for _, crbName := range []string{sharedName, perPaasName} {
+				Expect(reconciler.Get(ctx, types.NamespacedName{Name: crbName}, &rbac.ClusterRoleBinding{})).
+					To(MatchError(ContainSubstring("not found")))
+			}
+		}
+	})
+})
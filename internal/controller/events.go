@@ -0,0 +1,76 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Actions (and the reasons of the events emitted for them) taken on sub resources
+const (
+	eventActionCreate = "Create"
+	eventActionUpdate = "Update"
+	eventActionDelete = "Delete"
+)
+
+var eventReasons = map[string]string{
+	eventActionCreate: "Created",
+	eventActionUpdate: "Updated",
+	eventActionDelete: "Deleted",
+}
+
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+
+// recordEvent emits an event on a resource (e.a. a Paas) regarding an action on one of its sub resources.
+// A Warning event is emitted when the action failed, and a Normal event otherwise.
+// When no recorder is set (e.a. in unittests), no events are emitted.
+func recordEvent(
+	recorder events.EventRecorder,
+	scheme *runtime.Scheme,
+	regarding runtime.Object,
+	related client.Object,
+	action string,
+	err error,
+) {
+	if recorder == nil {
+		return
+	}
+	target := relatedName(scheme, related)
+	if err != nil {
+		recorder.Eventf(regarding, related, corev1.EventTypeWarning, action+"Failed", action,
+			"failed to %s %s: %s", strings.ToLower(action), target, err.Error())
+		return
+	}
+	recorder.Eventf(regarding, related, corev1.EventTypeNormal, eventReasons[action], action,
+		"%s %s", strings.ToLower(eventReasons[action]), target)
+}
+
+// relatedName returns a human-readable reference (kind and name) to a sub resource
+func relatedName(scheme *runtime.Scheme, obj client.Object) string {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if scheme != nil {
+		if gvk, err := apiutil.GVKForObject(obj, scheme); err == nil {
+			kind = gvk.Kind
+		}
+	}
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", kind, obj.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", kind, obj.GetNamespace(), obj.GetName())
+}
+
+// recordEvent emits an event on a Paas regarding an action on one of its sub resources
+func (r *PaasReconciler) recordEvent(regarding runtime.Object, related client.Object, action string, err error) {
+	recordEvent(r.Recorder, r.Scheme, regarding, related, action, err)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	quotav1 "github.com/openshift/api/quota/v1"
+	userv1 "github.com/openshift/api/user/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ = Describe("Events", func() {
+	var (
+		recorder *events.FakeRecorder
+		paas     *v1alpha2.Paas
+	)
+	BeforeEach(func() {
+		recorder = events.NewFakeRecorder(10)
+		paas = &v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{Name: "events-paas", UID: "events-uid"}}
+	})
+
+	When("no recorder is set", func() {
+		It("should not emit events", func() {
+			Expect(func() {
+				recordEvent(nil, k8sClient.Scheme(), paas, &corev1.Namespace{}, eventActionCreate, nil)
+			}).NotTo(Panic())
+		})
+	})
+	When("an action on a sub resource succeeds", func() {
+		It("should emit a Normal event with the kind and name of the sub resource", func() {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "events-paas-ns"}}
+			recordEvent(recorder, k8sClient.Scheme(), paas, ns, eventActionCreate, nil)
+			Expect(recorder.Events).To(Receive(Equal("Normal Created created Namespace events-paas-ns")))
+		})
+	})
+	When("an action on a sub resource fails", func() {
+		It("should emit a Warning event with the namespaced name and the error", func() {
+			rb := &rbac.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "paas-admin", Namespace: "events-paas-ns"}}
+			recordEvent(recorder, k8sClient.Scheme(), paas, rb, eventActionDelete, errors.New("boom"))
+			Expect(recorder.Events).To(Receive(Equal(
+				"Warning DeleteFailed failed to delete RoleBinding events-paas-ns/paas-admin: boom")))
+		})
+	})
+	When("the reconciler creates and updates a group", func() {
+		It("should emit events on the Paas", func() {
+			ctx := context.Background()
+			reconciler := &PaasReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}
+			group := &userv1.Group{
+				ObjectMeta: metav1.ObjectMeta{Name: "events-group"},
+				Users:      []string{"user1"},
+			}
+			Expect(reconciler.ensureGroup(ctx, paas, group)).To(Succeed())
+			Expect(recorder.Events).To(Receive(Equal("Normal Created created Group events-group")))
+			group.Users = []string{"user1", "user2"}
+			Expect(reconciler.ensureGroup(ctx, paas, group)).To(Succeed())
+			Expect(recorder.Events).To(Receive(Equal("Normal Updated updated Group events-group")))
+			Expect(reconciler.deleteObsoleteGroups(ctx, paas, nil, []*userv1.Group{group})).To(Succeed())
+			Expect(recorder.Events).To(Receive(Equal("Normal Deleted deleted Group events-group")))
+		})
+	})
+	When("the reconciler ensures resources which are unchanged", func() {
+		It("should not emit events", func() {
+			ctx := context.Background()
+			reconciler := &PaasReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "events-secret", Namespace: "default"},
+				Data:       map[string][]byte{"url": []byte("ssh://events")},
+			}
+			Expect(controllerutil.SetControllerReference(paas, secret, k8sClient.Scheme())).To(Succeed())
+			Expect(reconciler.ensureSecret(ctx, paas, secret.DeepCopy())).To(Succeed())
+			Expect(recorder.Events).To(Receive(Equal("Normal Created created Secret default/events-secret")))
+			Expect(reconciler.ensureSecret(ctx, paas, secret.DeepCopy())).To(Succeed())
+
+			quota := &quotav1.ClusterResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "events-quota"},
+				Spec: quotav1.ClusterResourceQuotaSpec{
+					Quota: corev1.ResourceQuotaSpec{
+						Hard: corev1.ResourceList{corev1.ResourceLimitsCPU: resourcev1.MustParse("1")},
+					},
+				},
+			}
+			Expect(controllerutil.SetControllerReference(paas, quota, k8sClient.Scheme())).To(Succeed())
+			Expect(reconciler.ensureQuota(ctx, paas, quota.DeepCopy())).To(Succeed())
+			Expect(recorder.Events).To(Receive(Equal("Normal Created created ClusterResourceQuota events-quota")))
+			Expect(reconciler.ensureQuota(ctx, paas, quota.DeepCopy())).To(Succeed())
+
+			Expect(recorder.Events).NotTo(Receive())
+		})
+	})
+})
@@ -0,0 +1,111 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	userv1 "github.com/openshift/api/user/v1"
+	rbac "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Virtual group backend", Ordered, func() {
+	const (
+		paasName   = "virtual-groups-paas"
+		queryGroup = "query-group"
+		usersGroup = "users-group"
+		funcRole   = "developer"
+		tecRole    = "edit"
+	)
+	var (
+		ctx        context.Context
+		paas       *v1alpha2.Paas
+		reconciler *PaasReconciler
+		myConfig   v1alpha2.PaasConfig
+		nsName     = join(paasName, "app")
+	)
+
+	reconcileWith := func(backend v1alpha2.ConfigGroupBackend) {
+		myConfig.Spec.GroupBackend = backend
+		ctx = context.WithValue(context.Background(), config.ContextKeyPaasConfig, myConfig)
+		Expect(reconciler.reconcileGroups(ctx, paas)).To(Succeed())
+		nsDef := newNamespaceDef(nsName, paasName, paas.Spec.Groups.Keys(), nil)
+		Expect(reconciler.reconcileNamespaceRolebindings(ctx, paas, nsDef)).To(Succeed())
+	}
+	getSubjects := func() []rbac.Subject {
+		var rb rbac.RoleBinding
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: nsName, Name: join("paas", tecRole)}, &rb)).
+			To(Succeed())
+		return rb.Subjects
+	}
+	subject := func(kind string, name string) rbac.Subject {
+		return rbac.Subject{Kind: kind, APIGroup: "rbac.authorization.k8s.io", Name: name}
+	}
+
+	BeforeAll(func() {
+		ctx = context.Background()
+		reconciler = &PaasReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+		myConfig = v1alpha2.PaasConfig{
+			Spec: v1alpha2.PaasConfigSpec{
+				RoleMappings: v1alpha2.ConfigRoleMappings{funcRole: {tecRole}},
+			},
+		}
+		paas = &v1alpha2.Paas{
+			ObjectMeta: metav1.ObjectMeta{Name: paasName},
+			Spec: v1alpha2.PaasSpec{
+				Requestor: paasName,
+				Groups: v1alpha2.PaasGroups{
+					queryGroup: {Query: "CN=developers,OU=org", Roles: []string{funcRole}},
+					usersGroup: {Users: []string{"u2", "u1"}, Roles: []string{funcRole}},
+				},
+			},
+		}
+		assurePaas(ctx, *paas)
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: paasName}, paas)).To(Succeed())
+		assureNamespace(ctx, nsName)
+	})
+
+	It("does not create OpenShift Groups", func() {
+		reconcileWith(v1alpha2.ConfigGroupBackend{Type: v1alpha2.ConfigGroupBackendVirtual})
+		for _, groupKey := range paas.Spec.Groups.Keys() {
+			err := reconciler.Get(ctx, types.NamespacedName{Name: paas.GroupKey2GroupName(groupKey)}, &userv1.Group{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		}
+		Expect(getSubjects()).To(ConsistOf(subject("Group", paas.GroupKey2GroupName(queryGroup))))
+	})
+
+	It("binds groups by their templated name", func() {
+		reconcileWith(v1alpha2.ConfigGroupBackend{
+			Type:         v1alpha2.ConfigGroupBackendVirtual,
+			NameTemplate: "oidc:{{ .Group.Key }}",
+		})
+		Expect(getSubjects()).To(ConsistOf(subject("Group", "oidc:"+queryGroup)))
+	})
+
+	It("binds the users of groups with users when they are mapped to users", func() {
+		reconcileWith(v1alpha2.ConfigGroupBackend{
+			Type:         v1alpha2.ConfigGroupBackendVirtual,
+			NameTemplate: "oidc:{{ .Group.Key }}",
+			Users:        v1alpha2.ConfigGroupUsersMapToUsers,
+		})
+		Expect(getSubjects()).To(Equal([]rbac.Subject{
+			subject("Group", "oidc:"+queryGroup),
+			subject("User", "u1"),
+			subject("User", "u2"),
+		}))
+	})
+})
@@ -0,0 +1,35 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+)
+
+// untilGroupExpiry returns the duration until the first upcoming expiration of a user or role in the groups of a
+// Paas, and 0 when no expirations are upcoming
+func untilGroupExpiry(paas *v1alpha2.Paas, now time.Time) time.Duration {
+	expirations := paas.Status.UpcomingExpirations
+	if len(expirations) == 0 {
+		return 0
+	}
+	// Expirations are in order of expiry. Requeue just after the first is due, so that it is not a moment too early.
+	return max(expirations[0].ExpiresAt.Sub(now)+time.Second, time.Second)
+}
+
+// earliestRequeue returns the shortest of durations which is not 0, and 0 when all durations are 0
+func earliestRequeue(durations ...time.Duration) time.Duration {
+	var earliest time.Duration
+	for _, d := range durations {
+		if d > 0 && (earliest == 0 || d < earliest) {
+			earliest = d
+		}
+	}
+	return earliest
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUntilGroupExpiry(t *testing.T) {
+	now := time.Now()
+	paas := &v1alpha2.Paas{}
+	assert.Zero(t, untilGroupExpiry(paas, now), "no requeue without upcoming expirations")
+
+	paas.Status.UpcomingExpirations = []v1alpha2.PaasExpiration{
+		{Group: "g1", User: "u1", ExpiresAt: metav1.NewTime(now.Add(time.Hour))},
+		{Group: "g1", Role: "admin", ExpiresAt: metav1.NewTime(now.Add(2 * time.Hour))},
+	}
+	assert.Equal(t, time.Hour+time.Second, untilGroupExpiry(paas, now))
+	assert.Equal(t, time.Second, untilGroupExpiry(paas, now.Add(3*time.Hour)), "overdue expiries requeue right away")
+}
+
+func TestEarliestRequeue(t *testing.T) {
+	assert.Zero(t, earliestRequeue())
+	assert.Zero(t, earliestRequeue(0, 0))
+	assert.Equal(t, time.Minute, earliestRequeue(0, time.Hour, time.Minute))
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	"github.com/belastingdienst/opr-paas/v5/internal/logging"
+	"github.com/belastingdienst/opr-paas/v5/pkg/groups"
+	"github.com/belastingdienst/opr-paas/v5/pkg/templating"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// groupSyncRequestName is the name of the single request which the group sync controller reconciles. All Paas'es
+// are aggregated into one resource, so every change is reconciled with the same request.
+const groupSyncRequestName = "group-sync"
+
+// GroupSyncReconciler aggregates the LDAP queries of the groups of all Paas'es into the group sync configuration
+type GroupSyncReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//revive:disable:line-length-limit
+// +kubebuilder:rbac:groups=cpet.belastingdienst.nl,resources=paas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps;secrets,verbs=create;get;list;patch;update;watch
+//revive:enable:line-length-limit
+
+// SetupWithManager sets up the controller with the Manager.
+func (gsr *GroupSyncReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	groupSyncRequest := handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: groupSyncRequestName}}}
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("groupsync").
+		Watches(&v1alpha2.Paas{}, groupSyncRequest, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&v1alpha2.PaasConfig{}, groupSyncRequest, builder.WithPredicates(v1alpha2.ActivePaasConfigUpdated())).
+		Complete(gsr)
+}
+
+// Reconcile writes the LDAP queries of the groups of all Paas'es to the group sync configuration, when it is
+// configured in the active PaasConfig
+func (gsr *GroupSyncReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerGroupSyncComponent)
+	myConfig, err := config.GetConfig(ctx, gsr.Client)
+	if err != nil {
+		logger.Err(err).Msg("could not get PaasConfig")
+		return ctrl.Result{}, err
+	}
+	if err = gsr.reconcileGroupSync(ctx, myConfig); err != nil {
+		logger.Err(err).Msg("failed to reconcile group sync configuration")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileGroupSync ensures the group sync configuration which is defined in myConfig
+func (gsr *GroupSyncReconciler) reconcileGroupSync(ctx context.Context, myConfig v1alpha2.PaasConfig) error {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerGroupSyncComponent)
+	groupSync := myConfig.Spec.GroupSync
+	if groupSync == nil {
+		logger.Debug().Msg("group sync is not configured")
+		return nil
+	}
+	var paasList v1alpha2.PaasList
+	if err := gsr.List(ctx, &paasList); err != nil {
+		return err
+	}
+	data, err := backendGroupSyncData(myConfig, paasList.Items)
+	if err != nil {
+		return err
+	}
+	key := types.NamespacedName{Namespace: groupSync.Target.Namespace, Name: groupSync.Target.Name}
+	logger.Info().Msgf("writing group sync configuration to %s %s", groupSync.GetKind(), key)
+	if groupSync.GetKind() == v1alpha2.ConfigGroupSyncSecret {
+		return gsr.ensureGroupSyncSecret(ctx, key, data)
+	}
+	return gsr.ensureGroupSyncConfigMap(ctx, key, data)
+}
+
+// backendGroupSyncData returns the templated data of the group sync configuration, for the groups with a query of all
+// Paas'es which are not being deleted
+func backendGroupSyncData(myConfig v1alpha2.PaasConfig, paases []v1alpha2.Paas) (map[string]string, error) {
+	allGroups := groups.NewGroups()
+	for _, paas := range paases {
+		if paas.DeletionTimestamp != nil {
+			continue
+		}
+		paasGroups := paas.Spec.Groups.AsGroups()
+		allGroups.Add(&paasGroups)
+	}
+	templater := templating.NewTemplater(v1alpha2.Paas{}, myConfig).WithGroups(*allGroups)
+	data := map[string]string{}
+	for name, tpl := range myConfig.Spec.GroupSync.GetData() {
+		result, err := templater.TemplateToString(name, tpl)
+		if err != nil {
+			return nil, fmt.Errorf("failed to template group sync key %s: %w", name, err)
+		}
+		data[name] = result
+	}
+	return data, nil
+}
+
+// ensureGroupSyncConfigMap ensures that a ConfigMap exists with data, leaving other keys of the ConfigMap alone
+func (gsr *GroupSyncReconciler) ensureGroupSyncConfigMap(
+	ctx context.Context,
+	key types.NamespacedName,
+	data map[string]string,
+) error {
+	found := &corev1.ConfigMap{}
+	err := gsr.Get(ctx, key, found)
+	if err != nil && errors.IsNotFound(err) {
+		return gsr.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Data:       data,
+		})
+	} else if err != nil {
+		return err
+	}
+	desired := maps.Clone(found.Data)
+	if desired == nil {
+		desired = map[string]string{}
+	}
+	maps.Copy(desired, data)
+	if maps.Equal(found.Data, desired) {
+		return nil
+	}
+	found.Data = desired
+	return gsr.Update(ctx, found)
+}
+
+// ensureGroupSyncSecret ensures that a Secret exists with data, leaving other keys of the Secret alone
+func (gsr *GroupSyncReconciler) ensureGroupSyncSecret(
+	ctx context.Context,
+	key types.NamespacedName,
+	data map[string]string,
+) error {
+	secretData := map[string][]byte{}
+	for name, value := range data {
+		secretData[name] = []byte(value)
+	}
+	found := &corev1.Secret{}
+	err := gsr.Get(ctx, key, found)
+	if err != nil && errors.IsNotFound(err) {
+		return gsr.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Data:       secretData,
+		})
+	} else if err != nil {
+		return err
+	}
+	desired := maps.Clone(found.Data)
+	if desired == nil {
+		desired = map[string][]byte{}
+	}
+	maps.Copy(desired, secretData)
+	if maps.EqualFunc(found.Data, desired, bytes.Equal) {
+		return nil
+	}
+	found.Data = desired
+	return gsr.Update(ctx, found)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Group sync", Ordered, func() {
+	const (
+		paasName    = "group-sync-paas"
+		targetNs    = "group-sync-target"
+		targetName  = "ldap-groups"
+		devsQuery   = "CN=group-sync-devs,OU=org,DC=example,DC=org"
+		opsQuery    = "CN=group-sync-ops,OU=org,DC=example,DC=org"
+		othersQuery = "CN=group-sync-others,OU=org,DC=example,DC=org"
+	)
+	var (
+		ctx        context.Context
+		paas       *v1alpha2.Paas
+		reconciler *GroupSyncReconciler
+		myConfig   v1alpha2.PaasConfig
+		target     = types.NamespacedName{Namespace: targetNs, Name: targetName}
+	)
+
+	BeforeAll(func() {
+		ctx = context.Background()
+		reconciler = &GroupSyncReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+		myConfig = v1alpha2.PaasConfig{
+			Spec: v1alpha2.PaasConfigSpec{
+				GroupSync: &v1alpha2.ConfigGroupSync{
+					Target: v1alpha2.NamespacedName{Namespace: targetNs, Name: targetName},
+				},
+			},
+		}
+		paas = &v1alpha2.Paas{
+			ObjectMeta: metav1.ObjectMeta{Name: paasName},
+			Spec: v1alpha2.PaasSpec{
+				Requestor: paasName,
+				Groups: v1alpha2.PaasGroups{
+					"devs":  {Query: devsQuery},
+					"ops":   {Query: opsQuery},
+					"users": {Users: []string{"u1"}},
+				},
+			},
+		}
+		assurePaas(ctx, *paas)
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: paasName}, paas)).To(Succeed())
+		assureNamespace(ctx, targetNs)
+	})
+
+	It("does nothing when group sync is not configured", func() {
+		Expect(reconciler.reconcileGroupSync(ctx, v1alpha2.PaasConfig{})).To(Succeed())
+		Expect(reconciler.Get(ctx, target, &corev1.ConfigMap{})).To(MatchError(ContainSubstring("not found")))
+	})
+
+	It("writes the queries of all Paas'es to a ConfigMap", func() {
+		Expect(reconciler.reconcileGroupSync(ctx, myConfig)).To(Succeed())
+		var cm corev1.ConfigMap
+		Expect(reconciler.Get(ctx, target, &cm)).To(Succeed())
+		queries := strings.Split(cm.Data[v1alpha2.ConfigGroupSyncDefaultKey], "\n")
+		Expect(queries).To(ContainElements(devsQuery, opsQuery))
+	})
+
+	It("updates the ConfigMap when query groups are removed, and leaves other keys alone", func() {
+		var cm corev1.ConfigMap
+		Expect(reconciler.Get(ctx, target, &cm)).To(Succeed())
+		cm.Data["other"] = "value"
+		Expect(reconciler.Update(ctx, &cm)).To(Succeed())
+
+		delete(paas.Spec.Groups, "ops")
+		paas.Spec.Groups["others"] = v1alpha2.PaasGroup{Query: othersQuery}
+		Expect(reconciler.Update(ctx, paas)).To(Succeed())
+		Expect(reconciler.reconcileGroupSync(ctx, myConfig)).To(Succeed())
+
+		Expect(reconciler.Get(ctx, target, &cm)).To(Succeed())
+		queries := strings.Split(cm.Data[v1alpha2.ConfigGroupSyncDefaultKey], "\n")
+		Expect(queries).To(ContainElements(devsQuery, othersQuery))
+		Expect(queries).NotTo(ContainElement(opsQuery))
+		Expect(cm.Data).To(HaveKeyWithValue("other", "value"))
+	})
+
+	It("renders the data templates to a Secret", func() {
+		myConfig.Spec.GroupSync.Kind = v1alpha2.ConfigGroupSyncSecret
+		myConfig.Spec.GroupSync.Data = map[string]string{
+			"whitelist.txt": `{{ range .Groups.Queries }}` +
+				`{{ if hasSuffix "DC=example,DC=org" . }}{{ . }};{{ end }}` +
+				`{{ end }}`,
+		}
+		Expect(reconciler.reconcileGroupSync(ctx, myConfig)).To(Succeed())
+		var secret corev1.Secret
+		Expect(reconciler.Get(ctx, target, &secret)).To(Succeed())
+		Expect(string(secret.Data["whitelist.txt"])).To(ContainSubstring(devsQuery + ";"))
+		Expect(string(secret.Data["whitelist.txt"])).NotTo(ContainSubstring(opsQuery))
+	})
+})
@@ -8,18 +8,21 @@ package controller
 
 import (
 	"context"
-	"maps"
+	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v5/internal/config"
 	"github.com/belastingdienst/opr-paas/v5/internal/logging"
-	"github.com/belastingdienst/opr-paas/v5/pkg/fields"
 	"github.com/belastingdienst/opr-paas/v5/pkg/templating"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	userv1 "github.com/openshift/api/user/v1"
+	rbac "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -47,7 +50,9 @@ func (r *PaasReconciler) ensureGroup(
 	if err != nil && errors.IsNotFound(err) {
 		logger.Info().Msg("creating group " + groupName)
 		// Create the group
-		if err = r.Create(ctx, group); err != nil {
+		err = r.Create(ctx, group)
+		r.recordEvent(paas, group, eventActionCreate, err)
+		if err != nil {
 			// creating the group failed
 			return err
 		}
@@ -76,8 +81,14 @@ func (r *PaasReconciler) ensureGroup(
 		found.Labels = group.Labels
 		changed = true
 	}
+	if ensureAnnotations(found, group) {
+		logger.Debug().Msg("group " + groupName + " annotations changed")
+		changed = true
+	}
 	if changed {
-		return r.Update(ctx, found)
+		err = r.Update(ctx, found)
+		r.recordEvent(paas, found, eventActionUpdate, err)
+		return err
 	}
 	return nil
 }
@@ -109,25 +120,24 @@ func (r *PaasReconciler) backendGroup(
 		return nil, nil
 	}
 
-	labels := map[string]string{}
-	labelTemplater := templating.NewTemplater(*paas, myConfig)
-	for name, tpl := range myConfig.Spec.Templating.GroupLabels {
-		var result fields.ElementMap
-		result, err = labelTemplater.TemplateToMap(name, tpl)
-		if err != nil {
-			return nil, err
-		}
-		r := result.AsElementMap()
-		maps.Copy(labels, r.AsLabels())
+	templater := templating.NewTemplater(*paas, myConfig)
+	labels, err := templater.TemplateItemsToMap(myConfig.Spec.Templating.GroupLabels)
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := templater.TemplateItemsToMap(myConfig.Spec.Templating.GroupAnnotations)
+	if err != nil {
+		return nil, err
 	}
 
 	g := &userv1.Group{}
 	groupName := paas.GroupKey2GroupName(paasGroupKey)
 	g.ObjectMeta = metav1.ObjectMeta{
-		Name:   groupName,
-		Labels: labels,
+		Name:        groupName,
+		Labels:      labels,
+		Annotations: annotations,
 	}
-	g.Users = group.Users
+	g.Users = group.ActiveUsers(time.Now())
 	g.Labels[ManagedByLabelKey] = paas.Name
 
 	if err = controllerutil.SetOwnerReference(paas, g, r.Scheme); err != nil {
@@ -136,10 +146,18 @@ func (r *PaasReconciler) backendGroup(
 	return g, nil
 }
 
+// backendGroups returns the desired groups of a Paas. The Virtual group backend has no groups.
 func (r *PaasReconciler) backendGroups(
 	ctx context.Context,
 	paas *v1alpha2.Paas,
 ) (groups []*userv1.Group, err error) {
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if myConfig.Spec.GroupBackend.GetType() == v1alpha2.ConfigGroupBackendVirtual {
+		return nil, nil
+	}
 	for key, group := range paas.Spec.Groups {
 		var beGroup *userv1.Group
 		beGroup, err = r.backendGroup(ctx, paas, key, group)
@@ -163,7 +181,7 @@ func (r *PaasReconciler) finalizeGroups(
 	if err != nil {
 		return err
 	}
-	err = r.deleteObsoleteGroups(ctx, []*userv1.Group{}, existingGroups)
+	err = r.deleteObsoleteGroups(ctx, paas, []*userv1.Group{}, existingGroups)
 	if err != nil {
 		return err
 	}
@@ -184,7 +202,7 @@ func (r *PaasReconciler) reconcileGroups(
 	if err != nil {
 		return err
 	}
-	err = r.deleteObsoleteGroups(ctx, desiredGroups, existingGroups)
+	err = r.deleteObsoleteGroups(ctx, paas, desiredGroups, existingGroups)
 	if err != nil {
 		return err
 	}
@@ -200,6 +218,7 @@ func (r *PaasReconciler) reconcileGroups(
 // deleteObsoleteGroups delete groups which are no longer desired from a Paas desired state.
 func (r *PaasReconciler) deleteObsoleteGroups(
 	ctx context.Context,
+	paas *v1alpha2.Paas,
 	desiredGroups []*userv1.Group,
 	existingGroups []*userv1.Group,
 ) error {
@@ -207,7 +226,9 @@ func (r *PaasReconciler) deleteObsoleteGroups(
 	logger.Info().Msg("deleting obsolete groups")
 	for _, existingGroup := range existingGroups {
 		if !isGroupInGroups(existingGroup, desiredGroups) {
-			if err := r.Delete(ctx, existingGroup); err != nil {
+			err := r.Delete(ctx, existingGroup)
+			r.recordEvent(paas, existingGroup, eventActionDelete, err)
+			if err != nil {
 				return err
 			}
 		}
@@ -225,7 +246,9 @@ func isGroupInGroups(group *userv1.Group, groups []*userv1.Group) bool {
 	return false
 }
 
-// getExistingGroups returns all groups owned by the specified Paas
+// getExistingGroups returns all groups owned by the specified Paas. Groups are also listed with the Virtual group
+// backend, so that groups which were created before switching to the Virtual backend are removed. When OpenShift
+// Groups are not available in the cluster, no groups are returned.
 func (r *PaasReconciler) getExistingGroups(
 	ctx context.Context,
 	paas *v1alpha2.Paas,
@@ -236,7 +259,9 @@ func (r *PaasReconciler) getExistingGroups(
 		client.MatchingLabels(map[string]string{ManagedByLabelKey: paas.Name}),
 	}
 	err = r.List(ctx, &groups, listOpts...)
-	if err != nil {
+	if meta.IsNoMatchError(err) {
+		return nil, nil
+	} else if err != nil {
 		return existingGroups, err
 	}
 	for _, group := range groups.Items {
@@ -245,3 +270,61 @@ func (r *PaasReconciler) getExistingGroups(
 	logger.Debug().Msgf("found %d existing groups owned by Paas %s", len(existingGroups), paas.Name)
 	return existingGroups, nil
 }
+
+// groupSubjects returns the RoleBinding subjects for a group of a Paas. With the OpenShift group backend, this is the
+// OpenShift Group for the group. With the Virtual group backend, this is a group with a templated name, or for groups
+// with users (and without a query) the users of the group which have not expired, when they are mapped to users.
+func groupSubjects(
+	paas *v1alpha2.Paas,
+	myConfig v1alpha2.PaasConfig,
+	groupKey string,
+	now time.Time,
+) ([]rbac.Subject, error) {
+	groupName := paas.GroupKey2GroupName(groupKey)
+	backend := myConfig.Spec.GroupBackend
+	if backend.GetType() != v1alpha2.ConfigGroupBackendVirtual {
+		return []rbac.Subject{{Kind: "Group", APIGroup: "rbac.authorization.k8s.io", Name: groupName}}, nil
+	}
+	group := paas.Spec.Groups[groupKey]
+	if len(group.Users) > 0 && len(group.Query) == 0 {
+		if backend.GetUsers() != v1alpha2.ConfigGroupUsersMapToUsers {
+			// There is no group to bind to, and such groups are rejected by the webhook
+			return nil, nil
+		}
+		var subjects []rbac.Subject
+		for _, user := range group.ActiveUsers(now) {
+			subjects = append(subjects, rbac.Subject{Kind: "User", APIGroup: "rbac.authorization.k8s.io", Name: user})
+		}
+		return subjects, nil
+	}
+	if backend.NameTemplate != "" {
+		templater := templating.NewTemplater(*paas, myConfig).WithGroup(templating.Group{
+			Key:   groupKey,
+			Name:  groupName,
+			Query: group.Query,
+		})
+		name, err := templater.TemplateToString("groupName", backend.NameTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to template name of group %s: %w", groupKey, err)
+		}
+		groupName = strings.TrimSpace(name)
+	}
+	return []rbac.Subject{{Kind: "Group", APIGroup: "rbac.authorization.k8s.io", Name: groupName}}, nil
+}
+
+// directSubjects returns the RoleBinding subjects for the service accounts and users of a group, which are bound
+// directly rather than through the group. Service accounts without a namespace are in the namespace nsName of the
+// RoleBinding.
+func directSubjects(group v1alpha2.PaasGroup, nsName string) (subjects []rbac.Subject) {
+	for _, sa := range group.ServiceAccounts {
+		namespace := sa.Namespace
+		if namespace == "" {
+			namespace = nsName
+		}
+		subjects = append(subjects, rbac.Subject{Kind: "ServiceAccount", Namespace: namespace, Name: sa.Name})
+	}
+	for _, user := range group.UserSubjects {
+		subjects = append(subjects, rbac.Subject{Kind: "User", APIGroup: "rbac.authorization.k8s.io", Name: user})
+	}
+	return subjects
+}
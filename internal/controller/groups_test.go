@@ -9,12 +9,14 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v5/internal/config"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	userv1 "github.com/openshift/api/user/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -190,4 +192,46 @@ var _ = Describe("Group controller", Ordered, func() {
 			}
 		})
 	})
+	Context("When switching to the Virtual group backend", func() {
+		It("should delete the groups which were created before", func() {
+			paas = &v1alpha2.Paas{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-virtual-paas",
+					UID:  "abc", // Needed or owner references fail
+				},
+				Spec: v1alpha2.PaasSpec{
+					Groups: v1alpha2.PaasGroups{"virtual-group": v1alpha2.PaasGroup{Users: []string{"hank"}}},
+				},
+			}
+			group.Name = paas.GroupKey2GroupName("virtual-group")
+			Expect(reconciler.reconcileGroups(ctx, paas)).To(Succeed())
+			found := &userv1.Group{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: group.Name}, found)).To(Succeed())
+
+			myConfig.Spec.GroupBackend = v1alpha2.ConfigGroupBackend{Type: v1alpha2.ConfigGroupBackendVirtual}
+			ctx = context.WithValue(context.Background(), config.ContextKeyPaasConfig, myConfig)
+			Expect(reconciler.reconcileGroups(ctx, paas)).To(Succeed())
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: group.Name}, found)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+	Context("When users of a group have expired", func() {
+		It("should only add the users which have not expired", func() {
+			paas = &v1alpha2.Paas{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-paas",
+					UID:  "abc", // Needed or owner references fail
+				},
+			}
+			beGroup, err := reconciler.backendGroup(ctx, paas, "contractors", v1alpha2.PaasGroup{
+				Users: []string{"expired", "active", "permanent"},
+				UserExpiries: map[string]metav1.Time{
+					"expired": metav1.NewTime(time.Now().Add(-time.Minute)),
+					"active":  metav1.NewTime(time.Now().Add(time.Hour)),
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(beGroup.Users).To(Equal(userv1.OptionalNames{"active", "permanent"}))
+		})
+	})
 })
@@ -0,0 +1,291 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	"github.com/belastingdienst/opr-paas/v5/internal/logging"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbac "k8s.io/api/rbac/v1"
+)
+
+// inventoryHashLength is the number of characters of the hash that are kept in the inventory
+const inventoryHashLength = 16
+
+// inventoryHash returns a hash of the json representation of the desired spec of a resource
+func inventoryHash(desired any) (string, error) {
+	data, err := json.Marshal(desired)
+	if err != nil {
+		return "", err
+	}
+	return hashData(string(data))[:inventoryHashLength], nil
+}
+
+// newInventoryItem returns an inventory item for a resource which is defined for a namespace.
+// The capability and / or PaasNS the namespace originates from are taken from the namespaceDef.
+func newInventoryItem(kind string, name string, nsDef namespaceDef, desired any) (v1alpha2.PaasInventoryItem, error) {
+	hash, err := inventoryHash(desired)
+	if err != nil {
+		return v1alpha2.PaasInventoryItem{}, fmt.Errorf("failed to hash %s %s: %w", kind, name, err)
+	}
+	item := v1alpha2.PaasInventoryItem{
+		Kind:       kind,
+		Name:       name,
+		Capability: nsDef.capName,
+		Hash:       hash,
+	}
+	if nsDef.paasns != nil {
+		item.PaasNS = fmt.Sprintf("%s/%s", nsDef.paasns.Namespace, nsDef.paasns.Name)
+	}
+	return item, nil
+}
+
+// backendInventory returns an inventory of all resources that are managed for a Paas.
+// The inventory is derived from the same backend functions that are used to reconcile these resources.
+func (r *PaasReconciler) backendInventory(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+) (inventory []v1alpha2.PaasInventoryItem, err error) {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerPaasComponent)
+	logger.Debug().Msg("building inventory")
+
+	quotaItems, err := r.quotaInventory(ctx, paas)
+	if err != nil {
+		return nil, err
+	}
+	inventory = append(inventory, quotaItems...)
+
+	groupItems, err := r.groupInventory(ctx, paas)
+	if err != nil {
+		return nil, err
+	}
+	inventory = append(inventory, groupItems...)
+
+	nsDefs, err := r.nsDefsFromPaas(ctx, paas)
+	if err != nil {
+		return nil, err
+	}
+	for _, nsDef := range nsDefs {
+		var nsItems []v1alpha2.PaasInventoryItem
+		if nsItems, err = r.namespaceInventory(ctx, paas, nsDef); err != nil {
+			return nil, err
+		}
+		inventory = append(inventory, nsItems...)
+	}
+
+	slices.SortFunc(inventory, func(a, b v1alpha2.PaasInventoryItem) int {
+		return cmp.Or(
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Subject, b.Subject),
+		)
+	})
+	return inventory, nil
+}
+
+func (r *PaasReconciler) quotaInventory(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+) (items []v1alpha2.PaasInventoryItem, err error) {
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if myConfig.Spec.QuotaBackend.GetType() == v1alpha2.ConfigQuotaBackendResourceQuota {
+		return r.resourceQuotaInventory(ctx, paas)
+	}
+	quotas, err := r.backendEnabledQuotas(ctx, paas)
+	if err != nil {
+		return nil, err
+	}
+	for _, quota := range quotas {
+		var item v1alpha2.PaasInventoryItem
+		if item, err = newInventoryItem("ClusterResourceQuota", quota.Name, namespaceDef{}, struct {
+			Labels map[string]string `json:"labels"`
+			Spec   any               `json:"spec"`
+		}{quota.Labels, quota.Spec}); err != nil {
+			return nil, err
+		}
+		if capName, isCapQuota := strings.CutPrefix(quota.Name, paas.Name+"-"); isCapQuota {
+			item.Capability = capName
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// resourceQuotaInventory returns the inventory items for the ResourceQuotas of the ResourceQuota quota backend
+func (r *PaasReconciler) resourceQuotaInventory(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+) (items []v1alpha2.PaasInventoryItem, err error) {
+	nsDefs, err := r.nsDefsFromPaas(ctx, paas)
+	if err != nil {
+		return nil, err
+	}
+	quotas, err := r.backendResourceQuotas(ctx, paas, nsDefs)
+	if err != nil {
+		return nil, err
+	}
+	for _, quota := range quotas {
+		var item v1alpha2.PaasInventoryItem
+		if item, err = newInventoryItem("ResourceQuota", quota.Name, nsDefs[quota.Namespace], struct {
+			Labels map[string]string `json:"labels"`
+			Spec   any               `json:"spec"`
+		}{quota.Labels, quota.Spec}); err != nil {
+			return nil, err
+		}
+		item.Namespace = quota.Namespace
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (r *PaasReconciler) groupInventory(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+) (items []v1alpha2.PaasInventoryItem, err error) {
+	groups, err := r.backendGroups(ctx, paas)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		var item v1alpha2.PaasInventoryItem
+		if item, err = newInventoryItem("Group", group.Name, namespaceDef{}, struct {
+			Labels map[string]string `json:"labels"`
+			Users  []string          `json:"users"`
+		}{group.Labels, group.Users}); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// namespaceInventory returns the inventory items for a namespace, and everything that is managed in (or for) it
+func (r *PaasReconciler) namespaceInventory(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	nsDef namespaceDef,
+) (items []v1alpha2.PaasInventoryItem, err error) {
+	ns, err := r.backendNamespace(ctx, paas, nsDef.nsName, nsDef.quotaName)
+	if err != nil {
+		return nil, err
+	}
+	item, err := newInventoryItem("Namespace", ns.Name, nsDef, struct {
+		Labels map[string]string `json:"labels"`
+	}{ns.Labels})
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, item)
+
+	rbs, err := r.backendNamespaceRoleBindings(ctx, paas, nsDef)
+	if err != nil {
+		return nil, err
+	}
+	for _, rb := range rbs {
+		// RoleBindings without subjects are removed instead of managed
+		if len(rb.Subjects) == 0 {
+			continue
+		}
+		if item, err = newInventoryItem("RoleBinding", rb.Name, nsDef, struct {
+			Labels   map[string]string `json:"labels"`
+			Subjects []rbac.Subject    `json:"subjects"`
+			RoleRef  rbac.RoleRef      `json:"roleRef"`
+		}{rb.Labels, rb.Subjects, rb.RoleRef}); err != nil {
+			return nil, err
+		}
+		item.Namespace = rb.Namespace
+		items = append(items, item)
+	}
+
+	// The inventory is readable by tenants, so Secrets are hashed from the encrypted value in the spec, instead of
+	// from the decrypted data
+	for url, encryptedSecretData := range nsDef.secrets {
+		var secret *corev1.Secret
+		namespacedName := secretNamespacedName(nsDef.nsName, url)
+		if secret, err = r.backendSecret(ctx, paas, nsDef.paasns, namespacedName, url); err != nil {
+			return nil, err
+		}
+		if item, err = newInventoryItem("Secret", secret.Name, nsDef, struct {
+			Labels    map[string]string `json:"labels"`
+			URL       string            `json:"url"`
+			Encrypted string            `json:"encrypted"`
+		}{secret.Labels, url, encryptedSecretData}); err != nil {
+			return nil, err
+		}
+		item.Namespace = secret.Namespace
+		items = append(items, item)
+	}
+
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if myConfig.Spec.FeatureFlags.NetworkIsolation {
+		var np *networkingv1.NetworkPolicy
+		if np, err = r.backendNetworkPolicy(paas, myConfig, nsDef); err != nil {
+			return nil, err
+		}
+		if item, err = newInventoryItem("NetworkPolicy", np.Name, nsDef, np.Spec); err != nil {
+			return nil, err
+		}
+		item.Namespace = np.Namespace
+		items = append(items, item)
+	}
+
+	baselineResources, err := r.backendBaselineResources(ctx, paas, nsDef)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range baselineResources {
+		if item, err = newInventoryItem(obj.GetKind(), obj.GetName(), nsDef, obj.Object); err != nil {
+			return nil, err
+		}
+		item.Namespace = obj.GetNamespace()
+		item.APIVersion = obj.GetAPIVersion()
+		items = append(items, item)
+	}
+
+	if nsDef.capName == "" {
+		return items, nil
+	}
+	permissions := capabilityPermissions(
+		nsDef.capConfig,
+		paas.Spec.Capabilities[nsDef.capName],
+		v1alpha2.ConfigPermissionScopeCluster,
+	)
+	for role, sas := range permissions {
+		crb := backendLayoutClusterRoleBinding(myConfig, paas, role)
+		for sa, add := range sas {
+			if !add {
+				continue
+			}
+			subject := rbac.Subject{Kind: "ServiceAccount", Namespace: nsDef.nsName, Name: sa}
+			if item, err = newInventoryItem("ClusterRoleBinding", crb.Name, nsDef, struct {
+				Subject rbac.Subject `json:"subject"`
+				RoleRef rbac.RoleRef `json:"roleRef"`
+			}{subject, crb.RoleRef}); err != nil {
+				return nil, err
+			}
+			item.Subject = fmt.Sprintf("%s/%s/%s", subject.Kind, subject.Namespace, subject.Name)
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
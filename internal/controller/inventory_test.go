@@ -0,0 +1,68 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("inventory", func() {
+	When("hashing a desired spec", func() {
+		It("should return a stable hash which changes with the spec", func() {
+			hash1, err := inventoryHash(map[string]string{"key": "value1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hash1).To(HaveLen(inventoryHashLength))
+			hash2, err := inventoryHash(map[string]string{"key": "value1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hash2).To(Equal(hash1))
+			hash3, err := inventoryHash(map[string]string{"key": "value2"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hash3).NotTo(Equal(hash1))
+		})
+	})
+	When("creating an inventory item for a namespace", func() {
+		It("should set the capability and PaasNS it originates from", func() {
+			nsDef := namespaceDef{nsName: "my-paas-my-paasns", capName: "my-cap", paasns: &v1alpha2.PaasNS{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-paasns", Namespace: "my-paas-my-cap"},
+			}}
+			item, err := newInventoryItem("Namespace", nsDef.nsName, nsDef, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(item.Kind).To(Equal("Namespace"))
+			Expect(item.Name).To(Equal(nsDef.nsName))
+			Expect(item.Capability).To(Equal("my-cap"))
+			Expect(item.PaasNS).To(Equal("my-paas-my-cap/my-paasns"))
+			Expect(item.Hash).To(HaveLen(inventoryHashLength))
+		})
+	})
+	When("creating inventory items for secrets", func() {
+		It("should hash the encrypted value, without decrypting it", func() {
+			const url = "ssh://git@example.com/repo.git"
+			ctx := context.WithValue(context.Background(), config.ContextKeyPaasConfig, v1alpha2.PaasConfig{})
+			reconciler := &PaasReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			paas := &v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{Name: "inventory-paas", UID: "inventory-uid"}}
+			nsDef := namespaceDef{nsName: "inventory-paas-ns", secrets: map[string]string{url: "not-decryptable"}}
+			items, err := reconciler.namespaceInventory(ctx, paas, nsDef)
+			Expect(err).NotTo(HaveOccurred())
+			secretName := secretNamespacedName(nsDef.nsName, url).Name
+			Expect(items).To(ContainElement(And(
+				HaveField("Kind", "Secret"),
+				HaveField("Name", secretName),
+			)))
+
+			nsDef.secrets[url] = "other-encrypted-value"
+			changedItems, err := reconciler.namespaceInventory(ctx, paas, nsDef)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changedItems).NotTo(Equal(items))
+		})
+	})
+})
@@ -9,6 +9,8 @@ package controller
 import (
 	"maps"
 	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -45,3 +47,43 @@ func mergeSecrets(base, override map[string]string) map[string]string {
 	maps.Copy(merged, override)
 	return merged
 }
+
+// ensureLabels sets all labels of desired on found, and returns true when found was changed.
+// Labels which are set on found, but not on desired, are left as is, so that they can be managed by others.
+func ensureLabels(found client.Object, desired client.Object) bool {
+	labels := found.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	var changed bool
+	for key, value := range desired.GetLabels() {
+		if orgValue, exists := labels[key]; !exists || orgValue != value {
+			labels[key] = value
+			changed = true
+		}
+	}
+	if changed {
+		found.SetLabels(labels)
+	}
+	return changed
+}
+
+// ensureAnnotations sets all annotations of desired on found, and returns true when found was changed.
+// Annotations which are set on found, but not on desired, are left as is, so that they can be managed by others.
+func ensureAnnotations(found client.Object, desired client.Object) bool {
+	annotations := found.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	var changed bool
+	for key, value := range desired.GetAnnotations() {
+		if orgValue, exists := annotations[key]; !exists || orgValue != value {
+			annotations[key] = value
+			changed = true
+		}
+	}
+	if changed {
+		found.SetAnnotations(annotations)
+	}
+	return changed
+}
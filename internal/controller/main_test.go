@@ -12,6 +12,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestMain_intersection(t *testing.T) {
@@ -81,3 +83,39 @@ func TestMergeSecrets(t *testing.T) {
 		})
 	}
 }
+
+func TestEnsureAnnotations(t *testing.T) {
+	found := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{"a": "1", "b": "2"},
+	}}
+	desired := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{"b": "3", "c": "4"},
+	}}
+	assert.True(t, ensureAnnotations(found, desired))
+	assert.Equal(t, map[string]string{"a": "1", "b": "3", "c": "4"}, found.Annotations)
+	assert.False(t, ensureAnnotations(found, desired), "annotations are already as desired")
+
+	found = &corev1.Namespace{}
+	assert.False(t, ensureAnnotations(found, &corev1.Namespace{}))
+	assert.Nil(t, found.Annotations)
+	assert.True(t, ensureAnnotations(found, desired))
+	assert.Equal(t, desired.Annotations, found.Annotations)
+}
+
+func TestEnsureLabels(t *testing.T) {
+	found := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Labels: map[string]string{"a": "1", "b": "2"},
+	}}
+	desired := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Labels: map[string]string{"b": "3", "c": "4"},
+	}}
+	assert.True(t, ensureLabels(found, desired))
+	assert.Equal(t, map[string]string{"a": "1", "b": "3", "c": "4"}, found.Labels)
+	assert.False(t, ensureLabels(found, desired), "labels are already as desired")
+
+	found = &corev1.Namespace{}
+	assert.False(t, ensureLabels(found, &corev1.Namespace{}))
+	assert.Nil(t, found.Labels)
+	assert.True(t, ensureLabels(found, desired))
+	assert.Equal(t, desired.Labels, found.Labels)
+}
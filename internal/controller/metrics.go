@@ -0,0 +1,154 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// PaasReconcileStepDuration is a prometheus metric which is a histogram of
+// the duration of every step (sub-reconciler) of the Paas reconciliation.
+var PaasReconcileStepDuration = func() *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "opr_paas_reconcile_step_duration_seconds",
+			Help:    "Duration of the steps of the Paas reconciliation in seconds by step.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"step"},
+	)
+}()
+
+// PaasReconcileStepErrorsTotal is a prometheus metric which is a counter of
+// the total number of errors in every step (sub-reconciler) of the Paas reconciliation.
+var PaasReconcileStepErrorsTotal = func() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "opr_paas_reconcile_step_errors_total",
+			Help: "Total number of errors in the steps of the Paas reconciliation by step.",
+		},
+		[]string{"step"},
+	)
+}()
+
+// PaasSecretDecryptFailuresTotal is a prometheus metric which is a counter of
+// the total number of secrets which could not be decrypted.
+var PaasSecretDecryptFailuresTotal = func() prometheus.Counter {
+	return prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "opr_paas_secret_decrypt_failures_total",
+			Help: "Total number of secrets which could not be decrypted while reconciling Paas'es.",
+		},
+	)
+}()
+
+// PaasesWithErrors is a prometheus metric which is a gauge of
+// the number of Paas'es which currently have the HasErrors condition set.
+var PaasesWithErrors = func() prometheus.Gauge {
+	return prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "opr_paas_paases_with_errors",
+			Help: "Number of Paas'es which currently have the HasErrors condition set.",
+		},
+	)
+}()
+
+func init() {
+	// Register custom metrics with the global prometheus registry
+	metrics.Registry.MustRegister(
+		PaasReconcileStepDuration,
+		PaasReconcileStepErrorsTotal,
+		PaasSecretDecryptFailuresTotal,
+		PaasesWithErrors,
+	)
+}
+
+// paasesWithErrors keeps track of the Paas'es which have errors, so that PaasesWithErrors can be set
+var paasesWithErrors = struct {
+	sync.Mutex
+	names map[string]bool
+}{names: map[string]bool{}}
+
+// setPaasHasErrors registers whether a Paas has errors and updates the PaasesWithErrors gauge accordingly
+func setPaasHasErrors(paasName string, hasErrors bool) {
+	paasesWithErrors.Lock()
+	defer paasesWithErrors.Unlock()
+	if hasErrors {
+		paasesWithErrors.names[paasName] = true
+	} else {
+		delete(paasesWithErrors.names, paasName)
+	}
+	PaasesWithErrors.Set(float64(len(paasesWithErrors.names)))
+}
+
+// initPaasesWithErrors registers which Paas'es have the HasErrors condition set in their status. This makes
+// PaasesWithErrors correct right after the operator starts, instead of only after all Paas'es are reconciled again.
+func initPaasesWithErrors(paases []v1alpha2.Paas) {
+	for _, paas := range paases {
+		setPaasHasErrors(paas.Name, meta.IsStatusConditionTrue(paas.Status.Conditions, v1alpha2.TypeHasErrorsPaas))
+	}
+}
+
+// paasesWithErrorsInitializer returns a runnable which initializes PaasesWithErrors from the Paas'es in the cluster,
+// once the caches of the manager have synced
+func paasesWithErrorsInitializer(mgr ctrl.Manager) manager.RunnableFunc {
+	return func(ctx context.Context) error {
+		_, logger := logging.GetLogComponent(ctx, logging.ControllerPaasComponent)
+		var paasList v1alpha2.PaasList
+		if err := mgr.GetClient().List(ctx, &paasList); err != nil {
+			logger.Error().AnErr("error", err).Msg("unable to list paases to initialize metrics")
+			return nil
+		}
+		initPaasesWithErrors(paasList.Items)
+		return nil
+	}
+}
+
+// observeStep records the duration of a reconciliation step, and counts the error when the step failed
+func observeStep(step string, start time.Time, err error) {
+	PaasReconcileStepDuration.WithLabelValues(step).Observe(time.Since(start).Seconds())
+	if err != nil {
+		PaasReconcileStepErrorsTotal.WithLabelValues(step).Inc()
+	}
+}
+
+// withMetrics wraps a Paas reconciler, so that its duration and errors are recorded as step metrics
+func withMetrics(
+	step string,
+	reconciler func(context.Context, *v1alpha2.Paas) error,
+) func(context.Context, *v1alpha2.Paas) error {
+	return func(ctx context.Context, paas *v1alpha2.Paas) error {
+		start := time.Now()
+		err := reconciler(ctx, paas)
+		observeStep(step, start, err)
+		return err
+	}
+}
+
+// withNsMetrics wraps a Paas namespaced resource reconciler, so that its duration and errors are recorded as
+// step metrics
+func withNsMetrics(
+	step string,
+	reconciler func(context.Context, *v1alpha2.Paas, namespaceDefs) error,
+) func(context.Context, *v1alpha2.Paas, namespaceDefs) error {
+	return func(ctx context.Context, paas *v1alpha2.Paas, nsDefs namespaceDefs) error {
+		start := time.Now()
+		err := reconciler(ctx, paas, nsDefs)
+		observeStep(step, start, err)
+		return err
+	}
+}
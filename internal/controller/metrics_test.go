@@ -0,0 +1,72 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWithMetrics_CountsErrors(t *testing.T) {
+	PaasReconcileStepErrorsTotal.Reset()
+	PaasReconcileStepDuration.Reset()
+
+	succeeding := withMetrics("succeeding", func(context.Context, *v1alpha2.Paas) error { return nil })
+	failing := withNsMetrics("failing", func(context.Context, *v1alpha2.Paas, namespaceDefs) error {
+		return errors.New("step failed")
+	})
+
+	assert.NoError(t, succeeding(context.TODO(), &v1alpha2.Paas{}))
+	assert.Error(t, failing(context.TODO(), &v1alpha2.Paas{}, namespaceDefs{}))
+
+	assert.InDelta(t, 0, testutil.ToFloat64(PaasReconcileStepErrorsTotal.WithLabelValues("succeeding")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(PaasReconcileStepErrorsTotal.WithLabelValues("failing")), 0)
+	assert.Equal(t, 2, testutil.CollectAndCount(PaasReconcileStepDuration))
+
+	PaasReconcileStepErrorsTotal.Reset()
+	PaasReconcileStepDuration.Reset()
+}
+
+func TestSetPaasHasErrors(t *testing.T) {
+	setPaasHasErrors("metrics-paas1", true)
+	setPaasHasErrors("metrics-paas2", true)
+	// Setting twice should not count twice
+	setPaasHasErrors("metrics-paas2", true)
+	assert.InDelta(t, 2, testutil.ToFloat64(PaasesWithErrors), 0)
+
+	setPaasHasErrors("metrics-paas1", false)
+	setPaasHasErrors("metrics-paas2", false)
+	assert.InDelta(t, 0, testutil.ToFloat64(PaasesWithErrors), 0)
+}
+
+func TestInitPaasesWithErrors(t *testing.T) {
+	initPaasesWithErrors([]v1alpha2.Paas{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "metrics-failed-paas"},
+			Status: v1alpha2.PaasStatus{Conditions: []metav1.Condition{
+				{Type: v1alpha2.TypeHasErrorsPaas, Status: metav1.ConditionTrue},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "metrics-ready-paas"},
+			Status: v1alpha2.PaasStatus{Conditions: []metav1.Condition{
+				{Type: v1alpha2.TypeHasErrorsPaas, Status: metav1.ConditionFalse},
+			}},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "metrics-new-paas"}},
+	})
+	assert.InDelta(t, 1, testutil.ToFloat64(PaasesWithErrors), 0)
+
+	setPaasHasErrors("metrics-failed-paas", false)
+	assert.InDelta(t, 0, testutil.ToFloat64(PaasesWithErrors), 0)
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"maps"
 
@@ -29,6 +30,17 @@ type namespaceDef struct {
 
 type namespaceDefs map[string]namespaceDef
 
+// kind returns the kind of the namespace, which is either a namespace defined by a PaasNS, the namespace of a
+// capability, or a namespace defined in the Paas
+func (nsDef namespaceDef) kind() v1alpha2.ConfigNamespaceKind {
+	if nsDef.paasns != nil {
+		return v1alpha2.ConfigNamespaceKindPaasNS
+	} else if nsDef.capName != "" {
+		return v1alpha2.ConfigNamespaceKindCapability
+	}
+	return v1alpha2.ConfigNamespaceKindPaas
+}
+
 // Helper to create a base namespaceDef
 func newNamespaceDef(nsName, quota string, groups []string, secrets map[string]string) namespaceDef {
 	return namespaceDef{
@@ -39,7 +51,9 @@ func newNamespaceDef(nsName, quota string, groups []string, secrets map[string]s
 	}
 }
 
-// Helper to create a namespaceDef from a PaasNS
+// Helper to create a namespaceDef from a PaasNS.
+// The resulting namespace and effective groups are reported in the status of the PaasNS after reconciliation
+// (see updatePaasNSStatuses).
 func newNamespaceDefFromPaasNS(nsName string, paasns *v1alpha2.PaasNS,
 	quota string, defaultGroups []string, secrets map[string]string,
 ) namespaceDef {
@@ -60,28 +74,76 @@ func newNamespaceDefFromPaasNS(nsName string, paasns *v1alpha2.PaasNS,
 }
 
 // paasNSsFromNs gets all PaasNs objects from a namespace and returns a map of all paasNS's.
-// Key of the map is based on the namespaced name of the PaasNS, so that we have uniqueness
-// paasNSsFromNs runs recursively, to collect all PaasNS's in the namespaces of founds PaasNS namespaces too.
-func (r *PaasReconciler) paasNSsFromNs(ctx context.Context, ns string) map[string]v1alpha2.PaasNS {
-	nss := map[string]v1alpha2.PaasNS{}
-	pnsList := &v1alpha2.PaasNSList{}
-	if err := r.List(ctx, pnsList, &client.ListOptions{Namespace: ns}); err != nil {
-		// In this case panic is ok, since this situation can only occur when either k8s is down,
-		// or permissions are insufficient. Both cases we should not continue executing code...
-		panic(err)
-	}
-	for _, pns := range pnsList.Items {
-		nameFromPaasNs, err := r.getPaasNameFromPaasNs(ctx, &pns)
-		if err != nil {
+// Key of the map is the name of the namespace which should be created for the PaasNS, so that we have uniqueness.
+// paasNSsFromNs walks iteratively through the namespaces of found PaasNS's, to collect all nested PaasNS's too.
+// Every namespace is visited only once, so that PaasNS's which (indirectly) point back to a parent namespace cannot
+// cause an endless walk. An error is returned when PaasNS's cannot be listed, or when the PaasNS limits as configured
+// in the PaasConfig are exceeded. The namespaces of found PaasNS's are added to counted, which holds all namespaces of
+// the Paas that are counted against the maximum number of namespaces. Errors of a PaasNS are reported on that PaasNS,
+// unless paas is in plan mode, in which case nothing should be applied.
+func (r *PaasReconciler) paasNSsFromNs(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	ns string,
+	counted map[string]bool,
+) (map[string]v1alpha2.PaasNS, error) {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerNamespaceComponent)
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	limits := myConfig.Spec.PaasNSLimits
+	reportError := func(pns *v1alpha2.PaasNS, pnsErr error) error {
+		if isPlanMode(paas) {
 			return nil
 		}
-		nsName := nameFromPaasNs + "-" + pns.Name
-		nss[nsName] = pns
-		for key, value := range r.paasNSsFromNs(ctx, nsName) {
-			nss[key] = value
+		return r.setPaasNSErrorStatus(ctx, pns, pnsErr)
+	}
+	nss := map[string]v1alpha2.PaasNS{}
+	visited := map[string]bool{ns: true}
+	parents := []string{ns}
+	for depth := 1; len(parents) > 0; depth++ {
+		var children []string
+		for _, parent := range parents {
+			pnsList := &v1alpha2.PaasNSList{}
+			if err = r.List(ctx, pnsList, &client.ListOptions{Namespace: parent}); err != nil {
+				return nil, fmt.Errorf("failed to list PaasNS's in namespace %s: %w", parent, err)
+			}
+			for _, pns := range pnsList.Items {
+				nameFromPaasNs, nameErr := r.getPaasNameFromPaasNs(ctx, &pns)
+				if nameErr != nil {
+					// The error is already logged, and the PaasNS is not processed. Report it on the PaasNS so that
+					// the tenant can see why.
+					if err = reportError(&pns, nameErr); err != nil {
+						return nil, errors.Join(nameErr, err)
+					}
+					continue
+				}
+				nsName := nameFromPaasNs + "-" + pns.Name
+				if visited[nsName] {
+					logger.Warn().Msgf("skipping PaasNS %s/%s, namespace %s is already processed",
+						pns.Namespace, pns.Name, nsName)
+					continue
+				}
+				if limits.DepthExceeded(depth) {
+					err = fmt.Errorf("PaasNS %s/%s exceeds the maximum nesting depth of %d",
+						pns.Namespace, pns.Name, limits.MaxDepth)
+					return nil, errors.Join(err, reportError(&pns, err))
+				}
+				visited[nsName] = true
+				nss[nsName] = pns
+				counted[nsName] = true
+				if limits.NamespacesExceeded(len(counted)) {
+					err = fmt.Errorf("PaasNS %s/%s exceeds the maximum number of %d namespaces of paas %s",
+						pns.Namespace, pns.Name, limits.MaxNamespaces, nameFromPaasNs)
+					return nil, errors.Join(err, reportError(&pns, err))
+				}
+				children = append(children, nsName)
+			}
 		}
+		parents = children
 	}
-	return nss
+	return nss, nil
 }
 
 func (r *PaasReconciler) getPaasNameFromPaasNs(ctx context.Context, paasNsObj client.Object) (string, error) {
@@ -105,7 +167,8 @@ func (r *PaasReconciler) nsDefsFromPaasNamespaces(
 	ctx context.Context,
 	paas *v1alpha2.Paas,
 	paasGroups []string,
-) namespaceDefs {
+	counted map[string]bool,
+) (namespaceDefs, error) {
 	result := namespaceDefs{}
 	for namespace, nsConfig := range paas.Spec.Namespaces {
 		fullNsName := join(paas.Name, namespace)
@@ -119,7 +182,11 @@ func (r *PaasReconciler) nsDefsFromPaasNamespaces(
 		base := newNamespaceDef(fullNsName, paas.Name, paasNsGroups, secrets)
 		result[base.nsName] = base
 
-		for nsName, paasns := range r.paasNSsFromNs(ctx, base.nsName) {
+		paasNSs, err := r.paasNSsFromNs(ctx, paas, base.nsName, counted)
+		if err != nil {
+			return nil, err
+		}
+		for nsName, paasns := range paasNSs {
 			secrets = map[string]string{}
 			maps.Copy(secrets, paas.Spec.Secrets)
 			maps.Copy(secrets, paasns.Spec.Secrets)
@@ -137,13 +204,14 @@ func (r *PaasReconciler) nsDefsFromPaasNamespaces(
 			result[ns.nsName] = ns
 		}
 	}
-	return result
+	return result, nil
 }
 
 func (r *PaasReconciler) paasCapabilityNss(
 	ctx context.Context,
 	paas *v1alpha2.Paas,
 	paasGroups []string,
+	counted map[string]bool,
 ) (namespaceDefs, error) {
 	result := namespaceDefs{}
 	myConfig, err := config.GetConfigFromContext(ctx)
@@ -177,7 +245,11 @@ func (r *PaasReconciler) paasCapabilityNss(
 			secrets:   secrets,
 		}
 		result[base.nsName] = base
-		for nsName, paasns := range r.paasNSsFromNs(ctx, capNS) {
+		var paasNSs map[string]v1alpha2.PaasNS
+		if paasNSs, err = r.paasNSsFromNs(ctx, paas, capNS, counted); err != nil {
+			return nil, err
+		}
+		for nsName, paasns := range paasNSs {
 			ns := newNamespaceDefFromPaasNS(nsName, &paasns, paas.Name, paasGroups, paas.Spec.Secrets)
 			result[ns.nsName] = ns
 		}
@@ -193,12 +265,32 @@ func (r *PaasReconciler) paasCapabilityNss(
 func (r *PaasReconciler) nsDefsFromPaas(ctx context.Context, paas *v1alpha2.Paas) (namespaceDefs, error) {
 	paasGroups := paas.Spec.Groups.Keys()
 	nsDefs := namespaceDefs{}
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The namespaces for spec.namespaces and capabilities are counted up front, so that the maximum number of
+	// namespaces applies to the total number of namespaces of the Paas while walking the PaasNS's
+	counted := map[string]bool{}
+	for namespace := range paas.Spec.Namespaces {
+		counted[join(paas.Name, namespace)] = true
+	}
+	for capName := range paas.Spec.Capabilities {
+		if capConfig, exists := myConfig.Spec.Capabilities[capName]; exists && !capConfig.QuotaSettings.External() {
+			counted[join(paas.Name, capName)] = true
+		}
+	}
 
-	for _, ns := range r.nsDefsFromPaasNamespaces(ctx, paas, paasGroups) {
+	paasNsDefs, err := r.nsDefsFromPaasNamespaces(ctx, paas, paasGroups, counted)
+	if err != nil {
+		return nil, err
+	}
+	for _, ns := range paasNsDefs {
 		nsDefs[ns.nsName] = ns
 	}
 
-	capNss, err := r.paasCapabilityNss(ctx, paas, paasGroups)
+	capNss, err := r.paasCapabilityNss(ctx, paas, paasGroups, counted)
 	if err != nil {
 		return nil, err
 	}
@@ -207,5 +299,10 @@ func (r *PaasReconciler) nsDefsFromPaas(ctx context.Context, paas *v1alpha2.Paas
 		nsDefs[ns.nsName] = ns
 	}
 
+	if limits := myConfig.Spec.PaasNSLimits; limits.NamespacesExceeded(len(nsDefs)) {
+		return nil, fmt.Errorf("paas %s has %d namespaces, which exceeds the maximum number of %d namespaces",
+			paas.Name, len(nsDefs), limits.MaxNamespaces)
+	}
+
 	return nsDefs, nil
 }
@@ -289,5 +289,59 @@ var _ = Describe("NamespaceDef", func() {
 				Expect(ns.secrets).To(HaveKeyWithValue("default-secret", "overridden-value"))
 			})
 		})
+		Context("with nested paasns objects pointing back to a parent namespace", func() {
+			var pnss []v1alpha2.PaasNS
+			withLimits := func(limits v1alpha2.ConfigPaasNSLimits) context.Context {
+				limitedConfig := paasConfig
+				limitedConfig.Spec.PaasNSLimits = limits
+				return context.WithValue(ctx, config.ContextKeyPaasConfig, limitedConfig)
+			}
+
+			BeforeEach(func() {
+				// loop-a in ns1 creates loop-b, and loop-b holds a paasns which would create ns1 again
+				pnss = []v1alpha2.PaasNS{
+					{ObjectMeta: metav1.ObjectMeta{Name: "loop-a", Namespace: join(paasName, ns1)}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "loop-b", Namespace: join(paasName, "loop-a")}},
+					{ObjectMeta: metav1.ObjectMeta{Name: ns1, Namespace: join(paasName, "loop-b")}},
+				}
+				for _, pns := range pnss {
+					assureNamespaceWithPaasReference(ctx, pns.Namespace, paasName)
+					Expect(reconciler.Create(ctx, &pns)).To(Succeed())
+					validatePaasNSExists(ctx, pns.Namespace, pns.Name)
+				}
+			})
+			AfterEach(func() {
+				for _, pns := range pnss {
+					_ = reconciler.Delete(ctx, &pns)
+				}
+			})
+			It("should return every namespace only once", func() {
+				nsDefs, err := reconciler.nsDefsFromPaas(ctx, &paas)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(nsDefs).To(HaveKey(join(paasName, "loop-a")))
+				Expect(nsDefs).To(HaveKey(join(paasName, "loop-b")))
+				Expect(nsDefs[join(paasName, ns1)].paasns).To(BeNil())
+			})
+			It("should fail when exceeding the maximum nesting depth", func() {
+				_, err := reconciler.nsDefsFromPaas(withLimits(v1alpha2.ConfigPaasNSLimits{MaxDepth: 1}), &paas)
+				Expect(err).To(MatchError(ContainSubstring("exceeds the maximum nesting depth of 1")))
+			})
+			It("should fail when exceeding the maximum number of namespaces", func() {
+				_, err := reconciler.nsDefsFromPaas(withLimits(v1alpha2.ConfigPaasNSLimits{MaxNamespaces: 3}), &paas)
+				Expect(err).To(MatchError(ContainSubstring("exceeds the maximum number of 3 namespaces")))
+			})
+			It("should count the namespaces of the whole Paas, not only those of the PaasNS's", func() {
+				// ns1, ns2 and the capability namespace leave room for only one PaasNS, so the limit is already
+				// exceeded while walking the PaasNS's
+				_, err := reconciler.nsDefsFromPaas(withLimits(v1alpha2.ConfigPaasNSLimits{MaxNamespaces: 4}), &paas)
+				Expect(err).To(MatchError(MatchRegexp(
+					"PaasNS .* exceeds the maximum number of 4 namespaces of paas %s", paasName)))
+			})
+			It("should succeed within limits", func() {
+				_, err := reconciler.nsDefsFromPaas(
+					withLimits(v1alpha2.ConfigPaasNSLimits{MaxDepth: 10, MaxNamespaces: 100}), &paas)
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
 	})
 })
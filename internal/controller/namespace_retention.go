@@ -0,0 +1,139 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// obsoleteNamespaceAction is the action to take on a namespace which is no longer required by a Paas
+type obsoleteNamespaceAction int
+
+const (
+	// obsoleteNamespaceDelete means the namespace should be deleted
+	obsoleteNamespaceDelete obsoleteNamespaceAction = iota
+	// obsoleteNamespaceOrphan means the namespace should be released from the Paas
+	obsoleteNamespaceOrphan
+	// obsoleteNamespaceMarkPending means the namespace should be marked as pending deletion
+	obsoleteNamespaceMarkPending
+	// obsoleteNamespaceKeepPending means the namespace is already pending deletion, and should be kept for now
+	obsoleteNamespaceKeepPending
+)
+
+// retainObsoleteNamespace determines what to do with an obsolete namespace according to a namespace retention, and
+// returns the time after which the namespace should be deleted when it is (to be) marked as pending deletion
+func retainObsoleteNamespace(
+	retention v1alpha2.NamespaceRetention,
+	ns corev1.Namespace,
+	now time.Time,
+) (obsoleteNamespaceAction, time.Time) {
+	switch retention.GetPolicy() {
+	case v1alpha2.NamespaceRetentionOrphan:
+		return obsoleteNamespaceOrphan, time.Time{}
+	case v1alpha2.NamespaceRetentionDelayedDelete:
+		since, pending := pendingDeletionSince(ns)
+		if !pending {
+			since = now
+		}
+		deleteAfter := since.Add(retention.Delay())
+		if !now.Before(deleteAfter) {
+			return obsoleteNamespaceDelete, time.Time{}
+		} else if !pending {
+			return obsoleteNamespaceMarkPending, deleteAfter
+		}
+		return obsoleteNamespaceKeepPending, deleteAfter
+	default:
+		return obsoleteNamespaceDelete, time.Time{}
+	}
+}
+
+// pendingDeletionSince returns the time at which a namespace was marked as pending deletion, and false when the
+// namespace is not (properly) marked as pending deletion
+func pendingDeletionSince(ns corev1.Namespace) (time.Time, bool) {
+	if ns.Labels[v1alpha2.PendingDeletionLabel] != "true" {
+		return time.Time{}, false
+	}
+	since, err := time.Parse(time.RFC3339, ns.Annotations[v1alpha2.PendingDeletionSinceAnnotation])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return since, true
+}
+
+// clearPendingDeletion removes the pending deletion marks from a namespace, and returns true when it had any
+func clearPendingDeletion(ns *corev1.Namespace) bool {
+	_, labeled := ns.Labels[v1alpha2.PendingDeletionLabel]
+	_, annotated := ns.Annotations[v1alpha2.PendingDeletionSinceAnnotation]
+	delete(ns.Labels, v1alpha2.PendingDeletionLabel)
+	delete(ns.Annotations, v1alpha2.PendingDeletionSinceAnnotation)
+	return labeled || annotated
+}
+
+// markNamespacePendingDeletion labels and annotates an obsolete namespace as pending deletion
+func (r *PaasReconciler) markNamespacePendingDeletion(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	ns *corev1.Namespace,
+	now time.Time,
+) error {
+	if ns.Labels == nil {
+		ns.Labels = map[string]string{}
+	}
+	if ns.Annotations == nil {
+		ns.Annotations = map[string]string{}
+	}
+	ns.Labels[v1alpha2.PendingDeletionLabel] = "true"
+	ns.Annotations[v1alpha2.PendingDeletionSinceAnnotation] = now.UTC().Format(time.RFC3339)
+	err := r.Update(ctx, ns)
+	r.recordEvent(paas, ns, eventActionUpdate, err)
+	return err
+}
+
+// orphanNamespace removes the labels and owner reference which relate an obsolete namespace (and the resources in it)
+// to a Paas, so that the namespace is no longer managed by the operator, and is not garbage collected with the Paas
+func (r *PaasReconciler) orphanNamespace(ctx context.Context, paas *v1alpha2.Paas, ns *corev1.Namespace) error {
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if err = r.releaseNamespaceResources(ctx, paas, ns.Name, baselineResourceKinds(paas, myConfig)); err != nil {
+		return err
+	}
+	delete(ns.Labels, ManagedByLabelKey)
+	delete(ns.Labels, myConfig.Spec.QuotaLabel)
+	clearPendingDeletion(ns)
+	ns.OwnerReferences = slices.DeleteFunc(ns.OwnerReferences, paas.IsItMe)
+	err = r.Update(ctx, ns)
+	r.recordEvent(paas, ns, eventActionUpdate, err)
+	return err
+}
+
+// untilPendingNamespaceDeletion returns the duration until the first pending namespace deletion of a Paas is due,
+// and 0 when no namespace deletions are pending
+func untilPendingNamespaceDeletion(paas *v1alpha2.Paas, now time.Time) time.Duration {
+	var until time.Duration
+	for _, pending := range paas.Status.PendingNamespaceDeletions {
+		// Requeue just after the deletion is due, so that it is not a moment too early
+		pendingUntil := max(pending.DeleteAfter.Sub(now)+time.Second, time.Second)
+		if until == 0 || pendingUntil < until {
+			until = pendingUntil
+		}
+	}
+	return until
+}
+
+// newPendingNamespaceDeletion returns the status item for a namespace which is pending deletion
+func newPendingNamespaceDeletion(ns corev1.Namespace, deleteAfter time.Time) v1alpha2.PaasPendingNamespaceDeletion {
+	return v1alpha2.PaasPendingNamespaceDeletion{Name: ns.Name, DeleteAfter: metav1.NewTime(deleteAfter)}
+}
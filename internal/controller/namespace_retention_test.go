@@ -0,0 +1,96 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func pendingNamespace(since time.Time) corev1.Namespace {
+	return corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "pending",
+		Labels:      map[string]string{v1alpha2.PendingDeletionLabel: "true"},
+		Annotations: map[string]string{v1alpha2.PendingDeletionSinceAnnotation: since.Format(time.RFC3339)},
+	}}
+}
+
+func TestRetainObsoleteNamespace(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	delayed := v1alpha2.NamespaceRetention{Policy: v1alpha2.NamespaceRetentionDelayedDelete, DelayHours: 2}
+
+	for _, test := range []struct {
+		name        string
+		retention   v1alpha2.NamespaceRetention
+		ns          corev1.Namespace
+		action      obsoleteNamespaceAction
+		deleteAfter time.Time
+	}{
+		{name: "default policy deletes", action: obsoleteNamespaceDelete},
+		{
+			name:      "delete policy deletes",
+			retention: v1alpha2.NamespaceRetention{Policy: v1alpha2.NamespaceRetentionDelete},
+			ns:        pendingNamespace(now),
+			action:    obsoleteNamespaceDelete,
+		},
+		{
+			name:      "orphan policy orphans",
+			retention: v1alpha2.NamespaceRetention{Policy: v1alpha2.NamespaceRetentionOrphan},
+			action:    obsoleteNamespaceOrphan,
+		},
+		{
+			name:        "delayed delete marks a new obsolete namespace",
+			retention:   delayed,
+			action:      obsoleteNamespaceMarkPending,
+			deleteAfter: now.Add(2 * time.Hour),
+		},
+		{
+			name:        "delayed delete keeps a pending namespace within the delay",
+			retention:   delayed,
+			ns:          pendingNamespace(now.Add(-time.Hour)),
+			action:      obsoleteNamespaceKeepPending,
+			deleteAfter: now.Add(time.Hour),
+		},
+		{
+			name:      "delayed delete deletes a pending namespace after the delay",
+			retention: delayed,
+			ns:        pendingNamespace(now.Add(-3 * time.Hour)),
+			action:    obsoleteNamespaceDelete,
+		},
+		{
+			name:      "delayed delete without delay deletes",
+			retention: v1alpha2.NamespaceRetention{Policy: v1alpha2.NamespaceRetentionDelayedDelete},
+			action:    obsoleteNamespaceDelete,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			action, deleteAfter := retainObsoleteNamespace(test.retention, test.ns, now)
+			assert.Equal(t, test.action, action)
+			assert.WithinDuration(t, test.deleteAfter, deleteAfter, 0)
+		})
+	}
+}
+
+func TestUntilPendingNamespaceDeletion(t *testing.T) {
+	now := time.Now()
+	paas := &v1alpha2.Paas{}
+	assert.Zero(t, untilPendingNamespaceDeletion(paas, now), "no requeue without pending deletions")
+
+	paas.Status.PendingNamespaceDeletions = []v1alpha2.PaasPendingNamespaceDeletion{
+		{Name: "later", DeleteAfter: metav1.NewTime(now.Add(2 * time.Hour))},
+		{Name: "sooner", DeleteAfter: metav1.NewTime(now.Add(time.Hour))},
+	}
+	assert.Equal(t, time.Hour+time.Second, untilPendingNamespaceDeletion(paas, now))
+
+	paas.Status.PendingNamespaceDeletions[1].DeleteAfter = metav1.NewTime(now.Add(-time.Hour))
+	assert.Equal(t, time.Second, untilPendingNamespaceDeletion(paas, now), "overdue deletions requeue right away")
+}
@@ -7,14 +7,15 @@ See LICENSE.md for details.
 package controller
 
 import (
+	"cmp"
 	"context"
 	"fmt"
-	"maps"
+	"slices"
+	"time"
 
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v5/internal/config"
 	"github.com/belastingdienst/opr-paas/v5/internal/logging"
-	"github.com/belastingdienst/opr-paas/v5/pkg/fields"
 	"github.com/belastingdienst/opr-paas/v5/pkg/templating"
 
 	corev1 "k8s.io/api/core/v1"
@@ -34,7 +35,9 @@ func (r *PaasReconciler) ensureNamespace(
 	found := &corev1.Namespace{}
 	err := r.Get(ctx, client.ObjectKeyFromObject(ns), found)
 	if err != nil && errors.IsNotFound(err) {
-		return r.Create(ctx, ns)
+		err = r.Create(ctx, ns)
+		r.recordEvent(paas, ns, eventActionCreate, err)
+		return err
 	} else if err != nil {
 		// Error that isn't due to the namespace not existing
 		return err
@@ -43,15 +46,21 @@ func (r *PaasReconciler) ensureNamespace(
 			return err
 		}
 	}
-	var changed bool
+	// The namespace is required (again), so it should no longer be pending deletion
+	changed := clearPendingDeletion(found)
 	for key, value := range ns.Labels {
 		if orgValue, exists := found.Labels[key]; !exists || orgValue != value {
 			changed = true
 			found.Labels[key] = value
 		}
 	}
+	if ensureAnnotations(found, ns) {
+		changed = true
+	}
 	if changed {
-		return r.Update(ctx, found)
+		err = r.Update(ctx, found)
+		r.recordEvent(paas, found, eventActionUpdate, err)
+		return err
 	}
 	return nil
 }
@@ -66,26 +75,25 @@ func (r *PaasReconciler) backendNamespace(
 	_, logger := logging.GetLogComponent(ctx, logging.ControllerNamespaceComponent)
 	logger.Info().Msgf("defining %s Namespace", name)
 
-	labels := map[string]string{}
 	myConfig, err := config.GetConfigFromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
-	labelTemplater := templating.NewTemplater(*paas, myConfig)
-	for tplName, tpl := range myConfig.Spec.Templating.NamespaceLabels {
-		var result fields.ElementMap
-		result, err = labelTemplater.TemplateToMap(tplName, tpl)
-		if err != nil {
-			return nil, err
-		}
-		r := result.AsElementMap()
-		maps.Copy(labels, r.AsLabels())
+	templater := templating.NewTemplater(*paas, myConfig)
+	labels, err := templater.TemplateItemsToMap(myConfig.Spec.Templating.NamespaceLabels)
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := templater.TemplateItemsToMap(myConfig.Spec.Templating.NamespaceAnnotations)
+	if err != nil {
+		return nil, err
 	}
 
 	ns := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:   name,
-			Labels: labels,
+			Name:        name,
+			Labels:      labels,
+			Annotations: annotations,
 		},
 		Spec: corev1.NamespaceSpec{},
 	}
@@ -113,16 +121,19 @@ func (r *PaasReconciler) reconcileNamespaces(
 	for _, nsDef := range nsDefs {
 		var ns *corev1.Namespace
 		if ns, err = r.backendNamespace(ctx, paas, nsDef.nsName, nsDef.quotaName); err != nil {
-			return fmt.Errorf("failure while defining namespace %s: %s", nsDef.nsName, err.Error())
+			return newNamespaceError(nsDef.nsName,
+				fmt.Errorf("failure while defining namespace %s: %s", nsDef.nsName, err.Error()))
 		} else if err = r.ensureNamespace(ctx, paas, ns); err != nil {
-			return fmt.Errorf("failure while creating namespace %s: %s", nsDef.nsName, err.Error())
+			return newNamespaceError(nsDef.nsName,
+				fmt.Errorf("failure while creating namespace %s: %s", nsDef.nsName, err.Error()))
 		}
 		logger.Debug().Msgf("namespace %s successfully created with quotaName %s", nsDef.nsName, nsDef.quotaName)
 	}
 	return nil
 }
 
-// finalizeObsoleteNamespaces returns all groups owned by the specified Paas
+// finalizeObsoleteNamespaces handles all namespaces owned by the specified Paas which are no longer required,
+// according to the namespace retention of the Paas. Namespaces which are pending deletion are listed in the status.
 func (r *PaasReconciler) finalizeObsoleteNamespaces(
 	ctx context.Context,
 	paas *v1alpha2.Paas,
@@ -131,6 +142,11 @@ func (r *PaasReconciler) finalizeObsoleteNamespaces(
 	var nss corev1.NamespaceList
 	var i int
 	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerNamespaceComponent)
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	retention := paas.GetNamespaceRetention(myConfig)
 	listOpts := []client.ListOption{
 		client.MatchingLabels(map[string]string{ManagedByLabelKey: paas.Name}),
 	}
@@ -138,15 +154,35 @@ func (r *PaasReconciler) finalizeObsoleteNamespaces(
 	if err != nil {
 		return err
 	}
+	now := time.Now()
+	var pendingDeletions []v1alpha2.PaasPendingNamespaceDeletion
 	for _, ns := range nss.Items {
-		if _, exists := nsDefs[ns.Name]; !exists {
+		if _, exists := nsDefs[ns.Name]; exists {
+			continue
+		}
+		action, deleteAfter := retainObsoleteNamespace(retention, ns, now)
+		switch action {
+		case obsoleteNamespaceDelete:
 			err = r.Delete(ctx, &ns)
-			if err != nil {
-				return err
-			}
-			i++
+			r.recordEvent(paas, &ns, eventActionDelete, err)
+		case obsoleteNamespaceOrphan:
+			err = r.orphanNamespace(ctx, paas, &ns)
+		case obsoleteNamespaceMarkPending:
+			err = r.markNamespacePendingDeletion(ctx, paas, &ns, now)
+		}
+		if err != nil {
+			return err
+		}
+		if action == obsoleteNamespaceMarkPending || action == obsoleteNamespaceKeepPending {
+			pendingDeletions = append(pendingDeletions, newPendingNamespaceDeletion(ns, deleteAfter))
 		}
+		i++
 	}
-	logger.Debug().Msgf("found %d existing namespaces owned by Paas %s", i, paas.Name)
+	slices.SortFunc(pendingDeletions, func(a, b v1alpha2.PaasPendingNamespaceDeletion) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	paas.Status.PendingNamespaceDeletions = pendingDeletions
+	logger.Debug().Msgf("found %d obsolete namespaces owned by Paas %s, of which %d are pending deletion", i,
+		paas.Name, len(pendingDeletions))
 	return nil
 }
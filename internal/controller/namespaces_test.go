@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v5/internal/config"
@@ -10,9 +11,13 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 var _ = Describe("Namespace", Ordered, func() {
@@ -31,6 +36,9 @@ var _ = Describe("Namespace", Ordered, func() {
 		reqLbl        = "requestor-label"
 		qtaLbl        = "quota-label"
 		kubeInstLabel = "app.kubernetes.io/instance"
+
+		displayNameAnnotation = "openshift.io/display-name"
+		requesterAnnotation   = "openshift.io/requester"
 	)
 	var (
 		ctx        context.Context
@@ -86,12 +94,20 @@ var _ = Describe("Namespace", Ordered, func() {
 				ManagedBySuffix: manBySuffix,
 				RequestorLabel:  reqLbl,
 				QuotaLabel:      qtaLbl,
+				AllowedNamespaceRetentionPolicies: []v1alpha2.NamespaceRetentionPolicy{
+					v1alpha2.NamespaceRetentionOrphan,
+					v1alpha2.NamespaceRetentionDelayedDelete,
+				},
 				Templating: v1alpha2.ConfigTemplatingItems{
 					NamespaceLabels: v1alpha2.ConfigTemplatingItem{
 						//revive:disable-next-line
 						"":       "{{ range $key, $value := .Paas.Labels }}{{ if ne $key \"" + kubeInstLabel + "\" }}{{$key}}: {{$value}}\n{{end}}{{end}}",
 						manByLbl: "{{ .Paas.Spec.ManagedByPaas }}-" + manBySuffix,
 					},
+					NamespaceAnnotations: v1alpha2.ConfigTemplatingItem{
+						displayNameAnnotation: "{{ .Paas.Name }}",
+						requesterAnnotation:   "{{ .Paas.Spec.Requestor }}",
+					},
 				},
 			},
 		}
@@ -146,5 +162,101 @@ var _ = Describe("Namespace", Ordered, func() {
 				Expect(ns.ObjectMeta.Labels).NotTo(HaveKey(kubeInstLabel))
 			}
 		})
+		It("have set all expected annotations", func() {
+			for nsName := range nsDefs {
+				var ns corev1.Namespace
+				err := reconciler.Get(ctx, types.NamespacedName{Name: nsName}, &ns)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ns.Annotations).To(HaveKeyWithValue(displayNameAnnotation, paasName))
+				Expect(ns.Annotations).To(HaveKeyWithValue(requesterAnnotation, paasRequestor))
+			}
+		})
+		It("restores templated annotations, and keeps other annotations", func() {
+			var ns corev1.Namespace
+			nsName := join(paasName, ns1)
+			Expect(reconciler.Get(ctx, types.NamespacedName{Name: nsName}, &ns)).To(Succeed())
+			ns.Annotations[displayNameAnnotation] = "changed by hand"
+			ns.Annotations["openshift.io/description"] = "set by hand"
+			Expect(reconciler.Update(ctx, &ns)).To(Succeed())
+
+			Expect(reconciler.reconcileNamespaces(ctx, paas, nsDefs)).To(Succeed())
+			Expect(reconciler.Get(ctx, types.NamespacedName{Name: nsName}, &ns)).To(Succeed())
+			Expect(ns.Annotations).To(HaveKeyWithValue(displayNameAnnotation, paasName))
+			Expect(ns.Annotations).To(HaveKeyWithValue("openshift.io/description", "set by hand"))
+		})
+	})
+
+	When("finalizing obsolete namespaces", func() {
+		getNs := func(name string) corev1.Namespace {
+			var ns corev1.Namespace
+			Expect(reconciler.Get(ctx, types.NamespacedName{Name: join(paasName, name)}, &ns)).To(Succeed())
+			return ns
+		}
+		finalizeWithout := func(removed string, retention v1alpha2.NamespaceRetention) {
+			paas.Spec.NamespaceRetention = &retention
+			delete(paas.Spec.Namespaces, removed)
+			nsDefs, err := reconciler.nsDefsFromPaas(ctx, paas)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reconciler.finalizeObsoleteNamespaces(ctx, paas, nsDefs)).To(Succeed())
+		}
+		delayed := v1alpha2.NamespaceRetention{Policy: v1alpha2.NamespaceRetentionDelayedDelete, DelayHours: 1}
+
+		It("marks namespaces as pending deletion with the DelayedDelete policy", func() {
+			finalizeWithout(ns2, delayed)
+			ns := getNs(ns2)
+			Expect(ns.DeletionTimestamp).To(BeNil())
+			Expect(ns.Labels).To(HaveKeyWithValue(v1alpha2.PendingDeletionLabel, "true"))
+			Expect(ns.Annotations).To(HaveKey(v1alpha2.PendingDeletionSinceAnnotation))
+			Expect(paas.Status.PendingNamespaceDeletions).To(ConsistOf(
+				HaveField("Name", join(paasName, ns2))))
+		})
+		It("clears the pending deletion when the namespace is required again", func() {
+			nsDefs, err := reconciler.nsDefsFromPaas(ctx, paas)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reconciler.reconcileNamespaces(ctx, paas, nsDefs)).To(Succeed())
+			ns := getNs(ns2)
+			Expect(ns.Labels).NotTo(HaveKey(v1alpha2.PendingDeletionLabel))
+			Expect(ns.Annotations).NotTo(HaveKey(v1alpha2.PendingDeletionSinceAnnotation))
+		})
+		It("deletes namespaces which are pending deletion after the delay", func() {
+			finalizeWithout(ns2, delayed)
+			ns := getNs(ns2)
+			ns.Annotations[v1alpha2.PendingDeletionSinceAnnotation] = time.Now().Add(-2 * time.Hour).
+				Format(time.RFC3339)
+			Expect(reconciler.Update(ctx, &ns)).To(Succeed())
+			finalizeWithout(ns2, delayed)
+			err := reconciler.Get(ctx, types.NamespacedName{Name: join(paasName, ns2)}, &ns)
+			Expect(apierrors.IsNotFound(err) || ns.DeletionTimestamp != nil).To(BeTrue())
+			Expect(paas.Status.PendingNamespaceDeletions).To(BeEmpty())
+		})
+		It("releases namespaces and the resources in them from the paas with the Orphan policy", func() {
+			nsName := join(paasName, ns1)
+			owned := []client.Object{
+				&rbac.RoleBinding{
+					ObjectMeta: metav1.ObjectMeta{Name: "orphan-rb", Namespace: nsName},
+					RoleRef:    rbac.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "admin"},
+				},
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+					Name:      "orphan-secret",
+					Namespace: nsName,
+					Labels:    map[string]string{ManagedByLabelKey: paasName},
+				}},
+			}
+			for _, obj := range owned {
+				Expect(controllerutil.SetControllerReference(paas, obj, k8sClient.Scheme())).To(Succeed())
+				Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+			}
+			finalizeWithout(ns1, v1alpha2.NamespaceRetention{Policy: v1alpha2.NamespaceRetentionOrphan})
+			ns := getNs(ns1)
+			Expect(ns.DeletionTimestamp).To(BeNil())
+			Expect(ns.Labels).NotTo(HaveKey(ManagedByLabelKey))
+			Expect(ns.Labels).NotTo(HaveKey(qtaLbl))
+			Expect(paas.AmIOwner(ns.OwnerReferences)).To(BeFalse())
+			for _, obj := range owned {
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+				Expect(paas.AmIOwner(obj.GetOwnerReferences())).To(BeFalse())
+				Expect(obj.GetLabels()).NotTo(HaveKey(ManagedByLabelKey))
+			}
+		})
 	})
 })
@@ -0,0 +1,152 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	"github.com/belastingdienst/opr-paas/v5/internal/logging"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// isolationNetworkPolicyName is the name of the NetworkPolicy which isolates a namespace of a Paas
+const isolationNetworkPolicyName = "paas-isolation"
+
+// backendNetworkPolicy returns the NetworkPolicy which isolates a namespace of a Paas. Ingress is only allowed from
+// namespaces of the same Paas, from the namespaces which are allowed in the PaasConfig, and for capability
+// namespaces from the network peers of the capability.
+func (r *PaasReconciler) backendNetworkPolicy(
+	paas *v1alpha2.Paas,
+	myConfig v1alpha2.PaasConfig,
+	nsDef namespaceDef,
+) (*networkingv1.NetworkPolicy, error) {
+	peers := []networkingv1.NetworkPolicyPeer{{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{ManagedByLabelKey: paas.Name}},
+	}}
+	selectors := myConfig.Spec.NetworkIsolation.AllowedNamespaces
+	if nsDef.capName != "" {
+		selectors = slices.Concat(selectors, nsDef.capConfig.NetworkPeers)
+	}
+	for _, selector := range selectors {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{NamespaceSelector: selector.DeepCopy()})
+	}
+	np := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      isolationNetworkPolicyName,
+			Namespace: nsDef.nsName,
+			Labels:    map[string]string{ManagedByLabelKey: paas.Name},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     []networkingv1.NetworkPolicyIngressRule{{From: peers}},
+		},
+	}
+	if err := controllerutil.SetControllerReference(paas, np, r.Scheme); err != nil {
+		return nil, err
+	}
+	return np, nil
+}
+
+// ensureNetworkPolicy ensures the presence of a NetworkPolicy, which is created or updated as desired. An existing
+// NetworkPolicy which is not owned by the Paas is not taken over.
+func (r *PaasReconciler) ensureNetworkPolicy(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	np *networkingv1.NetworkPolicy,
+) error {
+	found := &networkingv1.NetworkPolicy{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(np), found)
+	if err != nil && errors.IsNotFound(err) {
+		err = r.Create(ctx, np)
+		r.recordEvent(paas, np, eventActionCreate, err)
+		return err
+	} else if err != nil {
+		return err
+	} else if !paas.AmIOwner(found.OwnerReferences) {
+		return fmt.Errorf("networkpolicy %s/%s already exists and is not owned by paas %s",
+			found.Namespace, found.Name, paas.Name)
+	}
+	if networkPolicyUpToDate(paas, found, np) {
+		return nil
+	}
+	found.OwnerReferences = np.OwnerReferences
+	found.Labels = np.Labels
+	found.Spec = np.Spec
+	err = r.Update(ctx, found)
+	r.recordEvent(paas, found, eventActionUpdate, err)
+	return err
+}
+
+// networkPolicyUpToDate returns true when an existing NetworkPolicy is owned by paas, and has the ManagedByLabelKey
+// label and the spec of the desired NetworkPolicy. The label is required to release the NetworkPolicy from the Paas
+// when its namespace is retained.
+func networkPolicyUpToDate(
+	paas *v1alpha2.Paas,
+	found *networkingv1.NetworkPolicy,
+	desired *networkingv1.NetworkPolicy,
+) bool {
+	return paas.AmIOwner(found.OwnerReferences) && found.Labels[ManagedByLabelKey] == paas.Name &&
+		equality.Semantic.DeepEqual(found.Spec, desired.Spec)
+}
+
+// finalizeNetworkPolicy deletes the NetworkPolicy which isolates a namespace of a Paas, if it exists
+func (r *PaasReconciler) finalizeNetworkPolicy(ctx context.Context, paas *v1alpha2.Paas, nsName string) error {
+	found := &networkingv1.NetworkPolicy{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: nsName, Name: isolationNetworkPolicyName}, found)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	} else if !paas.AmIOwner(found.OwnerReferences) {
+		// Not created by this operator, so leave it alone
+		return nil
+	}
+	err = r.Delete(ctx, found)
+	r.recordEvent(paas, found, eventActionDelete, err)
+	return client.IgnoreNotFound(err)
+}
+
+// reconcileNetworkPolicies ensures a NetworkPolicy which isolates every namespace of a Paas, when network isolation
+// is enabled in the PaasConfig, and removes these NetworkPolicies when it is not
+func (r *PaasReconciler) reconcileNetworkPolicies(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	nsDefs namespaceDefs,
+) error {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerNamespaceComponent)
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	enabled := myConfig.Spec.FeatureFlags.NetworkIsolation
+	logger.Debug().Bool("enabled", enabled).Msg("reconciling network isolation")
+	for _, nsDef := range nsDefs {
+		if !enabled {
+			if err = r.finalizeNetworkPolicy(ctx, paas, nsDef.nsName); err != nil {
+				return newNamespaceError(nsDef.nsName, err)
+			}
+			continue
+		}
+		var np *networkingv1.NetworkPolicy
+		if np, err = r.backendNetworkPolicy(paas, myConfig, nsDef); err != nil {
+			return newNamespaceError(nsDef.nsName, err)
+		}
+		if err = r.ensureNetworkPolicy(ctx, paas, np); err != nil {
+			return newNamespaceError(nsDef.nsName, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Network isolation", Ordered, func() {
+	const (
+		paasName = "isolated-paas"
+		capName  = "argocd"
+		nsName   = "app"
+	)
+	var (
+		ctx        context.Context
+		paas       *v1alpha2.Paas
+		reconciler *PaasReconciler
+		myConfig   v1alpha2.PaasConfig
+		appNs      = join(paasName, nsName)
+		capNs      = join(paasName, capName)
+		ingress    = metav1.LabelSelector{
+			MatchLabels: map[string]string{"network.openshift.io/policy-group": "ingress"},
+		}
+		gitops = metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "gitops"}}
+	)
+
+	reconcileWith := func(enabled bool) {
+		myConfig.Spec.FeatureFlags.NetworkIsolation = enabled
+		ctx = context.WithValue(context.Background(), config.ContextKeyPaasConfig, myConfig)
+		nsDefs, err := reconciler.nsDefsFromPaas(ctx, paas)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.reconcileNetworkPolicies(ctx, paas, nsDefs)).To(Succeed())
+	}
+	getNetworkPolicy := func(namespace string) (*networkingv1.NetworkPolicy, error) {
+		np := &networkingv1.NetworkPolicy{}
+		key := types.NamespacedName{Namespace: namespace, Name: isolationNetworkPolicyName}
+		return np, reconciler.Get(ctx, key, np)
+	}
+	namespaceSelectors := func(np *networkingv1.NetworkPolicy) (selectors []metav1.LabelSelector) {
+		Expect(np.Spec.Ingress).To(HaveLen(1))
+		for _, peer := range np.Spec.Ingress[0].From {
+			selectors = append(selectors, *peer.NamespaceSelector)
+		}
+		return selectors
+	}
+
+	BeforeAll(func() {
+		ctx = context.Background()
+		reconciler = &PaasReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+		myConfig = v1alpha2.PaasConfig{
+			Spec: v1alpha2.PaasConfigSpec{
+				Capabilities: v1alpha2.ConfigCapabilities{capName: v1alpha2.ConfigCapability{
+					QuotaSettings: v1alpha2.ConfigQuotaSettings{
+						DefQuota: map[corev1.ResourceName]resourcev1.Quantity{
+							corev1.ResourceLimitsCPU: resourcev1.MustParse("1"),
+						},
+					},
+					NetworkPeers: []metav1.LabelSelector{gitops},
+				}},
+				NetworkIsolation: v1alpha2.ConfigNetworkIsolation{
+					AllowedNamespaces: []metav1.LabelSelector{ingress},
+				},
+			},
+		}
+		paas = &v1alpha2.Paas{
+			ObjectMeta: metav1.ObjectMeta{Name: paasName},
+			Spec: v1alpha2.PaasSpec{
+				Requestor:    paasName,
+				Capabilities: v1alpha2.PaasCapabilities{capName: v1alpha2.PaasCapability{}},
+				Namespaces:   v1alpha2.PaasNamespaces{nsName: v1alpha2.PaasNamespace{}},
+			},
+		}
+		assurePaas(ctx, *paas)
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: paasName}, paas)).To(Succeed())
+		assureNamespace(ctx, appNs)
+		assureNamespace(ctx, capNs)
+	})
+
+	It("does not create NetworkPolicies when network isolation is disabled", func() {
+		reconcileWith(false)
+		_, err := getNetworkPolicy(appNs)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("isolates all namespaces when network isolation is enabled", func() {
+		reconcileWith(true)
+		samePaas := metav1.LabelSelector{MatchLabels: map[string]string{ManagedByLabelKey: paasName}}
+
+		np, err := getNetworkPolicy(appNs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(paas.AmIOwner(np.OwnerReferences)).To(BeTrue())
+		Expect(np.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress))
+		Expect(namespaceSelectors(np)).To(ConsistOf(samePaas, ingress))
+
+		np, err = getNetworkPolicy(capNs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(namespaceSelectors(np)).To(ConsistOf(samePaas, ingress, gitops))
+	})
+
+	It("restores NetworkPolicies which are changed by hand", func() {
+		np, err := getNetworkPolicy(appNs)
+		Expect(err).NotTo(HaveOccurred())
+		np.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{}}
+		Expect(reconciler.Update(ctx, np)).To(Succeed())
+
+		reconcileWith(true)
+		np, err = getNetworkPolicy(appNs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(namespaceSelectors(np)).To(HaveLen(2))
+	})
+
+	It("restores the managed-by label of NetworkPolicies", func() {
+		np, err := getNetworkPolicy(appNs)
+		Expect(err).NotTo(HaveOccurred())
+		delete(np.Labels, ManagedByLabelKey)
+		Expect(reconciler.Update(ctx, np)).To(Succeed())
+
+		reconcileWith(true)
+		np, err = getNetworkPolicy(appNs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(np.Labels).To(HaveKeyWithValue(ManagedByLabelKey, paasName))
+	})
+
+	It("does not take over NetworkPolicies which are not owned by the Paas", func() {
+		np, err := getNetworkPolicy(appNs)
+		Expect(err).NotTo(HaveOccurred())
+		np.OwnerReferences = nil
+		np.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{}}
+		Expect(reconciler.Update(ctx, np)).To(Succeed())
+
+		nsDefs, err := reconciler.nsDefsFromPaas(ctx, paas)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.reconcileNetworkPolicies(ctx, paas, nsDefs)).To(
+			MatchError(ContainSubstring("already exists and is not owned by paas")))
+		np, err = getNetworkPolicy(appNs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(np.OwnerReferences).To(BeEmpty())
+		Expect(namespaceSelectors(np)).To(BeEmpty())
+
+		// A NetworkPolicy which is not owned by the Paas is left alone when network isolation is disabled
+		reconcileWith(false)
+		_, err = getNetworkPolicy(appNs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.Delete(ctx, np)).To(Succeed())
+		reconcileWith(true)
+	})
+
+	It("removes NetworkPolicies when network isolation is disabled again", func() {
+		reconcileWith(false)
+		for _, namespace := range []string{appNs, capNs} {
+			_, err := getNetworkPolicy(namespace)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		}
+	})
+})
@@ -11,11 +11,13 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	paasapi "github.com/belastingdienst/opr-paas/v5/api"
 	"github.com/belastingdienst/opr-paas/v5/internal/config"
 	quotav1 "github.com/openshift/api/quota/v1"
 	userv1 "github.com/openshift/api/user/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -28,9 +30,12 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -41,11 +46,16 @@ const paasFinalizer = "paas.cpet.belastingdienst.nl/finalizer"
 // PaasReconciler reconciles a Paas object
 type PaasReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder events.EventRecorder
+	// MaxConcurrentReconciles is the maximum number of Paas'es which are reconciled concurrently (defaults to 1)
+	MaxConcurrentReconciles int
+	// crypts caches the decryption keys and crypts, which are shared between concurrent reconciliations
+	crypts cryptCache
 }
 
 // GetScheme is a simple getter for the Scheme of the Paas Controller logic
-func (r PaasReconciler) getScheme() *runtime.Scheme {
+func (r *PaasReconciler) getScheme() *runtime.Scheme {
 	return r.Scheme
 }
 
@@ -59,6 +69,9 @@ func (r PaasReconciler) getScheme() *runtime.Scheme {
 // +kubebuilder:rbac:groups=argoproj.io,resources=applicationsets,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=core,resources=secrets;namespaces,verbs=create;delete;get;list;patch;update;watch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings;clusterrolebindings,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=core,resources=limitranges;resourcequotas,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=create;delete;get;list;patch;update;watch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=create;delete;get;list;patch;update;watch
 
 // +kubebuilder:rbac:groups=cpet.belastingdienst.nl,resources=paasns,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cpet.belastingdienst.nl,resources=paasns/status,verbs=get;update;patch
@@ -84,6 +97,7 @@ func (r *PaasReconciler) getPaasFromRequest(
 	if err = r.Get(ctx, req.NamespacedName, paas); err != nil {
 		return nil, client.IgnoreNotFound(err)
 	}
+	ctx = withPaasDebug(ctx, paas)
 
 	if paas.GetDeletionTimestamp() != nil {
 		logger.Info().Msg("paas marked for deletion")
@@ -141,6 +155,15 @@ func (r *PaasReconciler) getPaasFromRequest(
 	return paas, nil
 }
 
+// withPaasDebug returns a context in which debug logging is enabled for all components, when debugging is enabled
+// for this Paas with the DebugAnnotation
+func withPaasDebug(ctx context.Context, paas *v1alpha2.Paas) context.Context {
+	if paas.GetAnnotations()[v1alpha2.DebugAnnotation] == "true" {
+		return logging.WithDebug(ctx)
+	}
+	return ctx
+}
+
 func (r *PaasReconciler) setFinalizing(
 	ctx context.Context,
 	paas *v1alpha2.Paas,
@@ -186,6 +209,7 @@ func (r *PaasReconciler) removeFinalizer(
 	if err := r.Update(ctx, paas); err != nil {
 		return err
 	}
+	setPaasHasErrors(paas.Name, false)
 	logger.Info().Msg("finalization finished")
 
 	return nil
@@ -215,12 +239,24 @@ func (r *PaasReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resul
 		// r.GetPaas handled all logic and returned a nil object
 		return ctrl.Result{}, nil
 	}
+	ctx = withPaasDebug(ctx, paas)
 
+	if isPlanMode(paas) {
+		if err = r.reconcilePlan(ctx, paas); err != nil {
+			return ctrl.Result{}, errors.Join(err, r.setErrorCondition(ctx, paas, err))
+		}
+		return ctrl.Result{}, nil
+	}
+
+	quotas, err := r.quotaBackend(ctx)
+	if err != nil {
+		return ctrl.Result{}, errors.Join(err, r.setErrorCondition(ctx, paas, err))
+	}
 	paasReconcilers := []func(context.Context, *v1alpha2.Paas) error{
-		r.reconcileQuotas,
-		r.reconcileClusterWideQuota,
-		r.reconcileNamespacedResources,
-		r.reconcileGroups,
+		withMetrics("reconcileQuotas", quotas.reconcileQuotas),
+		withMetrics("reconcileClusterWideQuota", quotas.reconcileClusterWideQuota),
+		withMetrics("reconcileNamespacedResources", r.reconcileNamespacedResources),
+		withMetrics("reconcileGroups", r.reconcileGroups),
 	}
 
 	for _, reconciler := range paasReconcilers {
@@ -228,8 +264,36 @@ func (r *PaasReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resul
 			return ctrl.Result{}, errors.Join(err, r.setErrorCondition(ctx, paas, err))
 		}
 	}
-	// Reconciling succeeded, set appropriate Condition
-	return ctrl.Result{}, r.setSuccessfulCondition(ctx, paas)
+
+	// Reconciling succeeded, update the inventory and set appropriate Condition
+	start := time.Now()
+	inventory, err := r.backendInventory(ctx, paas)
+	observeStep("backendInventory", start, err)
+	if err != nil {
+		return ctrl.Result{}, errors.Join(err, r.setErrorCondition(ctx, paas, err))
+	}
+	quotaUsage, err := quotas.quotaUsage(ctx, paas)
+	if err != nil {
+		return ctrl.Result{}, errors.Join(err, r.setErrorCondition(ctx, paas, err))
+	}
+	now := time.Now()
+	paas.Status.Inventory = inventory
+	paas.Status.Plan = nil
+	paas.Status.UpcomingExpirations = paas.Spec.Groups.UpcomingExpirations(now)
+	paas.Status.QuotaUsage = quotaUsage
+	if err = r.setQuotaPressureCondition(ctx, paas); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err = r.setSuccessfulCondition(ctx, paas); err != nil {
+		return ctrl.Result{}, err
+	}
+	// Reconcile again when a namespace which is pending deletion should be deleted, a user or role expires, or the
+	// quota usage should be refreshed
+	return ctrl.Result{RequeueAfter: earliestRequeue(
+		untilPendingNamespaceDeletion(paas, now),
+		untilGroupExpiry(paas, now),
+		paasConfig.Spec.QuotaUsage.RefreshInterval(),
+	)}, nil
 }
 
 func (r *PaasReconciler) reconcileNamespacedResources(
@@ -245,22 +309,29 @@ func (r *PaasReconciler) reconcileNamespacedResources(
 		return err
 	}
 	logger.Debug().Msgf("Need to manage resources for %d namespaces", len(nsDefs))
+	quotas, err := r.quotaBackend(ctx)
+	if err != nil {
+		return err
+	}
 
 	paasNsReconcilers := []func(context.Context, *v1alpha2.Paas, namespaceDefs) error{
-		r.reconcileNamespaces,
-		r.finalizeObsoleteNamespaces,
-		r.reconcilePaasRolebindings,
-		r.reconcilePaasSecrets,
-		r.reconcileClusterRoleBindings,
+		withNsMetrics("reconcileNamespaces", r.reconcileNamespaces),
+		withNsMetrics("finalizeObsoleteNamespaces", r.finalizeObsoleteNamespaces),
+		withNsMetrics("reconcileNamespaceQuotas", quotas.reconcileNamespaceQuotas),
+		withNsMetrics("reconcilePaasRolebindings", r.reconcilePaasRolebindings),
+		withNsMetrics("reconcilePaasSecrets", r.reconcilePaasSecrets),
+		withNsMetrics("reconcileBaselineResources", r.reconcileBaselineResources),
+		withNsMetrics("reconcileNetworkPolicies", r.reconcileNetworkPolicies),
+		withNsMetrics("reconcileClusterRoleBindings", r.reconcileClusterRoleBindings),
 	}
 	for _, reconciler := range paasNsReconcilers {
 		if err = reconciler(ctx, paas, nsDefs); err != nil {
-			return errors.Join(err, r.setErrorCondition(ctx, paas, err))
+			return errors.Join(err, r.setErrorCondition(ctx, paas, err), r.updatePaasNSStatuses(ctx, nsDefs, err))
 		}
 	}
 
-	// Reconciling succeeded, set appropriate Condition
-	return r.setSuccessfulCondition(ctx, paas)
+	// Reconciling succeeded, set appropriate Conditions
+	return errors.Join(r.updatePaasNSStatuses(ctx, nsDefs, nil), r.setSuccessfulCondition(ctx, paas))
 }
 
 func (r *PaasReconciler) setSuccessfulCondition(ctx context.Context, paas *v1alpha2.Paas) error {
@@ -274,6 +345,7 @@ func (r *PaasReconciler) setSuccessfulCondition(ctx context.Context, paas *v1alp
 		Status: metav1.ConditionFalse, Reason: "Reconciling", ObservedGeneration: paas.Generation,
 		Message: fmt.Sprintf("Reconciled (%s) successfully", paas.Name),
 	})
+	setPaasHasErrors(paas.Name, false)
 
 	return r.Status().Update(ctx, paas)
 }
@@ -289,6 +361,7 @@ func (r *PaasReconciler) setErrorCondition(ctx context.Context, resource paasapi
 		Status: metav1.ConditionTrue, Reason: "ReconcilingError", ObservedGeneration: resource.GetGeneration(),
 		Message: err.Error(),
 	})
+	setPaasHasErrors(resource.GetName(), true)
 	return r.Status().Update(ctx, resource)
 }
 
@@ -346,18 +419,49 @@ func specOrLabelsChangedPredicate() predicate.Predicate {
 	)
 }
 
+// isKindAvailable returns true when the kind of obj is served by the cluster
+func isKindAvailable(mgr ctrl.Manager, obj client.Object) (bool, error) {
+	gvk, err := apiutil.GVKForObject(obj, mgr.GetScheme())
+	if err != nil {
+		return false, err
+	}
+	if _, err = mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); meta.IsNoMatchError(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 // SetupWithManager is not unit-tested ATM. Mostly covered by e2e-tests.
 func (r *PaasReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha2.Paas{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
+	// The PaasesWithErrors gauge is kept in memory, so it is initialized from the Paas statuses on startup
+	if err := mgr.Add(paasesWithErrorsInitializer(mgr)); err != nil {
+		return err
+	}
+	bldr := ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha2.Paas{}, builder.WithPredicates(
+			predicate.Or(specOrLabelsChangedPredicate(), planAnnotationChangedPredicate()),
+		))
+	// ClusterResourceQuotas and Groups are only watched when available, so that the ResourceQuota quota backend and
+	// the Virtual group backend can be used on clusters without them
+	for _, obj := range []client.Object{&quotav1.ClusterResourceQuota{}, &userv1.Group{}} {
+		available, err := isKindAvailable(mgr, obj)
+		if err != nil {
+			return err
+		} else if available {
+			bldr = bldr.Owns(obj, builder.WithPredicates(specOrLabelsChangedPredicate()))
+		}
+	}
+	return bldr.
 		// Reconcile on owned resources changes
-		Owns(&quotav1.ClusterResourceQuota{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
-		Owns(&userv1.Group{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
+		Owns(&corev1.ResourceQuota{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
 		Owns(&corev1.Secret{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
 		Owns(&corev1.Namespace{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
 		Owns(&rbacv1.RoleBinding{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
 		Owns(&rbacv1.ClusterRoleBinding{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
+		Owns(&networkingv1.NetworkPolicy{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
 		// TODO(portly-halicore-76):We don't own PaasNS objects correctly yet
 		// Owns(&v1alpha2.PaasNS{}, builder.WithPredicates(specOrLabelsChangedPredicate())).
 		// TODO(portly-halicore-76): We don't own Rolebinding objects correctly yet
@@ -382,6 +486,7 @@ func (r *PaasReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			}),
 			builder.WithPredicates(v1alpha2.ActivePaasConfigUpdated()),
 		).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 }
 
@@ -389,10 +494,28 @@ func (r *PaasReconciler) finalizePaas(ctx context.Context, paas *v1alpha2.Paas)
 	_, logger := logging.GetLogComponent(ctx, logging.ControllerPaasComponent)
 	logger.Debug().Msg("inside Paas finalizer")
 
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	quotas, err := r.quotaBackend(ctx)
+	if err != nil {
+		return err
+	}
+
 	paasReconcilers := []func(context.Context, *v1alpha2.Paas) error{
-		r.finalizeGroups,
-		r.finalizePaasClusterRoleBindings,
-		r.finalizeClusterWideQuotas,
+		withMetrics("finalizeGroups", r.finalizeGroups),
+		withMetrics("finalizePaasClusterRoleBindings", r.finalizePaasClusterRoleBindings),
+		withMetrics("finalizeClusterWideQuotas", quotas.finalizeClusterWideQuotas),
+	}
+	if paas.GetDeletionPolicy(myConfig) == v1alpha2.PaasDeletionPolicyRetain {
+		// Groups are retained rather than deleted, and all other resources are released from the Paas,
+		// so that they are not garbage collected along with the Paas
+		paasReconcilers = []func(context.Context, *v1alpha2.Paas) error{
+			withMetrics("finalizePaasClusterRoleBindings", r.finalizePaasClusterRoleBindings),
+			withMetrics("finalizeClusterWideQuotas", quotas.finalizeClusterWideQuotas),
+			withMetrics("retainPaasResources", r.retainPaasResources),
+		}
 	}
 
 	for _, reconciler := range paasReconcilers {
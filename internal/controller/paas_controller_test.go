@@ -16,14 +16,17 @@ import (
 	"github.com/belastingdienst/opr-paas-cli/v2/pkg/crypt"
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	"github.com/belastingdienst/opr-paas/v5/internal/logging"
 	paasquota "github.com/belastingdienst/opr-paas/v5/pkg/quota"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	quotav1 "github.com/openshift/api/quota/v1"
 	userv1 "github.com/openshift/api/user/v1"
+	"github.com/rs/zerolog"
 	corev1 "k8s.io/api/core/v1"
 	rbac "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -125,6 +128,26 @@ var _ = Describe("Get paas from ns", func() {
 	})
 })
 
+var _ = Describe("Paas debug annotation", func() {
+	componentLevel := func(ctx context.Context) zerolog.Level {
+		_, logger := logging.GetLogComponent(ctx, logging.TestComponent)
+		return logger.GetLevel()
+	}
+	When("the debug annotation is set on a Paas", func() {
+		It("should enable debug logging for all components", func() {
+			paas := &v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{v1alpha2.DebugAnnotation: "true"},
+			}}
+			Expect(componentLevel(withPaasDebug(context.TODO(), paas))).To(Equal(zerolog.DebugLevel))
+		})
+	})
+	When("the debug annotation is not set on a Paas", func() {
+		It("should not enable debug logging", func() {
+			Expect(componentLevel(withPaasDebug(context.TODO(), &v1alpha2.Paas{}))).To(Equal(zerolog.InfoLevel))
+		})
+	})
+})
+
 var _ = Describe("Paas Controller", Ordered, func() {
 	const (
 		paasRequestor      = "paas-controller"
@@ -211,7 +234,7 @@ var _ = Describe("Paas Controller", Ordered, func() {
 
 		// The reconciler needs an active PaasConfig. As this is lost when creating, we must
 		// call the PaasConfig reconciler the update the status
-		pcReconciler := &PaasConfigReconciler{k8sClient, k8sClient.Scheme()}
+		pcReconciler := &PaasConfigReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
 		paasConfigReconcileReq := controllerruntime.Request{NamespacedName: types.NamespacedName{Name: myConfig.Name}}
 		_, err = pcReconciler.Reconcile(ctx, paasConfigReconcileReq)
 		Expect(err).NotTo(HaveOccurred())
@@ -636,7 +659,7 @@ var _ = Describe("Paas Reconcile", Ordered, func() {
 
 		// The reconciler needs an active PaasConfig. As this is lost when creating, we must
 		// call the PaasConfig reconciler the update the status
-		pcReconciler := &PaasConfigReconciler{k8sClient, k8sClient.Scheme()}
+		pcReconciler := &PaasConfigReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
 		paasConfigReconcileReq := controllerruntime.Request{NamespacedName: types.NamespacedName{Name: myConfig.Name}}
 		_, err = pcReconciler.Reconcile(ctx, paasConfigReconcileReq)
 		Expect(err).NotTo(HaveOccurred())
@@ -765,6 +788,43 @@ var _ = Describe("Paas Reconcile", Ordered, func() {
 			err = reconciler.Get(ctx, types.NamespacedName{Namespace: ns2Name, Name: ns1SecretHashedName}, &secret)
 			Expect(err.Error()).To(Equal("secrets \"" + ns1SecretHashedName + "\" not found"))
 		})
+		It("should have reported the namespace and groups in the paasns status", func() {
+			var pns v1alpha2.PaasNS
+			err := reconciler.Get(ctx, types.NamespacedName{Namespace: join(paasName, ns1Name), Name: paasNSName}, &pns)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pns.Status.Namespace).To(Equal(join(paasName, paasNSName)))
+			Expect(pns.Status.Groups).To(ConsistOf(paasGroupName, ldapGroupName))
+			Expect(meta.IsStatusConditionTrue(pns.Status.Conditions, v1alpha2.TypeReadyPaasNs)).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(pns.Status.Conditions, v1alpha2.TypeHasErrorsPaasNs)).To(BeTrue())
+		})
+		It("should have listed all managed resources in the paas inventory", func() {
+			inventory := map[string]v1alpha2.PaasInventoryItem{}
+			for _, item := range getPaas(ctx, paasName).Status.Inventory {
+				Expect(item.Hash).To(HaveLen(inventoryHashLength))
+				inventory[strings.Join([]string{item.Kind, item.Namespace, item.Name, item.Subject}, "/")] = item
+			}
+			Expect(inventory).To(HaveKeyWithValue("ClusterResourceQuota//"+paasName+"/",
+				HaveField("Capability", "")))
+			Expect(inventory).To(HaveKeyWithValue("ClusterResourceQuota//"+capNamespace+"/",
+				HaveField("Capability", capName)))
+			Expect(inventory).To(HaveKey("Group//" + userGroupName + "/"))
+			Expect(inventory).NotTo(HaveKey("Group//" + ldapGroupName + "/"))
+			for _, nsName := range namespaces {
+				Expect(inventory).To(HaveKey("Namespace//" + nsName + "/"))
+				Expect(inventory).To(HaveKey("Secret/" + nsName + "/" + paasSecretHashedName + "/"))
+			}
+			Expect(inventory).To(HaveKeyWithValue("Namespace//"+join(paasName, paasNSName)+"/",
+				HaveField("PaasNS", join(paasName, ns1Name)+"/"+paasNSName)))
+			Expect(inventory).To(HaveKey("RoleBinding/" + join(paasName, ns1Name) + "/paas-" + techRoleName1 + "/"))
+			Expect(inventory).NotTo(HaveKey("RoleBinding/" + join(paasName, ns1Name) + "/paas-" + techRoleName2 + "/"))
+			for crbSAName, crbRoleNames := range clusterRolebindings {
+				for _, crbRoleName := range crbRoleNames {
+					key := fmt.Sprintf("ClusterRoleBinding//paas-%s/ServiceAccount/%s/%s",
+						crbRoleName, capNamespace, crbSAName)
+					Expect(inventory).To(HaveKeyWithValue(key, HaveField("Capability", capName)))
+				}
+			}
+		})
 	})
 	When("modifying a Paas", Ordered, func() {
 		It("should reconcile successfully", func() {
@@ -881,7 +941,7 @@ func waitForDeletePaasConfig(ctx context.Context, paasConfig *v1alpha2.PaasConfi
 	Expect(err).NotTo(HaveOccurred())
 
 	// Call PaasConfig reconciler to delete finalizer
-	pcReconciler := &PaasConfigReconciler{k8sClient, k8sClient.Scheme()}
+	pcReconciler := &PaasConfigReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
 	paasConfigReconcileReq := controllerruntime.Request{NamespacedName: types.NamespacedName{Name: paasConfig.Name}}
 	_, err = pcReconciler.Reconcile(ctx, paasConfigReconcileReq)
 	Expect(err).NotTo(HaveOccurred())
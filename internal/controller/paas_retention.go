@@ -0,0 +1,179 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	"github.com/belastingdienst/opr-paas/v5/internal/logging"
+	quotav1 "github.com/openshift/api/quota/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// stripPaasReferences removes the owner references to a Paas, and the ManagedByLabelKey label for that Paas from a
+// resource, and returns true when the resource was changed
+func stripPaasReferences(paas *v1alpha2.Paas, obj client.Object) bool {
+	changed := paas.AmIOwner(obj.GetOwnerReferences())
+	if changed {
+		obj.SetOwnerReferences(paas.WithoutMe(obj.GetOwnerReferences()))
+	}
+	if labels := obj.GetLabels(); labels[ManagedByLabelKey] == paas.Name {
+		delete(labels, ManagedByLabelKey)
+		obj.SetLabels(labels)
+		changed = true
+	}
+	return changed
+}
+
+// releaseFromPaas strips all references to a Paas from a resource, and updates the resource when it was changed
+func (r *PaasReconciler) releaseFromPaas(ctx context.Context, paas *v1alpha2.Paas, obj client.Object) error {
+	if !stripPaasReferences(paas, obj) {
+		return nil
+	}
+	err := r.Update(ctx, obj)
+	r.recordEvent(paas, obj, eventActionUpdate, err)
+	return err
+}
+
+// releaseNamespaceResources releases the rolebindings, secrets, networkpolicies, resourcequotas and baseline resources
+// in a namespace from a Paas
+func (r *PaasReconciler) releaseNamespaceResources(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	namespace string,
+	baselineKinds []schema.GroupVersionKind,
+) error {
+	managedByPaas := client.MatchingLabels{ManagedByLabelKey: paas.Name}
+	// RoleBindings have no ManagedByLabelKey label, and are found by their namespace instead
+	var rbs rbac.RoleBindingList
+	if err := r.List(ctx, &rbs, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	for _, rb := range rbs.Items {
+		if err := r.releaseFromPaas(ctx, paas, &rb); err != nil {
+			return err
+		}
+	}
+	var secrets corev1.SecretList
+	if err := r.List(ctx, &secrets, client.InNamespace(namespace), managedByPaas); err != nil {
+		return err
+	}
+	for _, secret := range secrets.Items {
+		if err := r.releaseFromPaas(ctx, paas, &secret); err != nil {
+			return err
+		}
+	}
+	var networkPolicies networkingv1.NetworkPolicyList
+	if err := r.List(ctx, &networkPolicies, client.InNamespace(namespace), managedByPaas); err != nil {
+		return err
+	}
+	for _, np := range networkPolicies.Items {
+		if err := r.releaseFromPaas(ctx, paas, &np); err != nil {
+			return err
+		}
+	}
+	var resourceQuotas corev1.ResourceQuotaList
+	if err := r.List(ctx, &resourceQuotas, client.InNamespace(namespace), managedByPaas); err != nil {
+		return err
+	}
+	for _, quota := range resourceQuotas.Items {
+		if err := r.releaseFromPaas(ctx, paas, &quota); err != nil {
+			return err
+		}
+	}
+	for _, gvk := range baselineKinds {
+		objs := &unstructured.UnstructuredList{}
+		objs.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := r.List(ctx, objs, client.InNamespace(namespace), managedByPaas); meta.IsNoMatchError(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		for _, obj := range objs.Items {
+			if err := r.releaseFromPaas(ctx, paas, &obj); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// retainPaasResources releases all namespaces, quotas, groups, (cluster)rolebindings, secrets, networkpolicies and
+// baseline resources from a Paas which is deleted with the Retain deletion policy. Without owner references they are
+// not garbage collected along with the Paas, and without ManagedByLabelKey labels they are no longer managed by the
+// operator. A later Paas with the same name re-adopts them when it is reconciled.
+func (r *PaasReconciler) retainPaasResources(ctx context.Context, paas *v1alpha2.Paas) error {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerPaasComponent)
+	logger.Info().Msg("retaining resources of Paas")
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	baselineKinds := baselineResourceKinds(paas, myConfig)
+	managedByPaas := client.MatchingLabels{ManagedByLabelKey: paas.Name}
+
+	var nss corev1.NamespaceList
+	if err := r.List(ctx, &nss, managedByPaas); err != nil {
+		return err
+	}
+	for _, ns := range nss.Items {
+		if err = r.releaseNamespaceResources(ctx, paas, ns.Name, baselineKinds); err != nil {
+			return err
+		}
+		if err = r.releaseFromPaas(ctx, paas, &ns); err != nil {
+			return err
+		}
+	}
+
+	// ClusterRoleBindings in the PerPaas layout are labelled with the Paas, while shared ClusterRoleBindings are not
+	// owned by a Paas at all
+	var crbs rbac.ClusterRoleBindingList
+	if err := r.List(ctx, &crbs, client.MatchingLabels{
+		crbTypeLabelKey:   perPaasCRBType,
+		ManagedByLabelKey: paas.Name,
+	}); err != nil {
+		return err
+	}
+	for _, crb := range crbs.Items {
+		if err := r.releaseFromPaas(ctx, paas, &crb); err != nil {
+			return err
+		}
+	}
+
+	existingGroups, err := r.getExistingGroups(ctx, paas)
+	if err != nil {
+		return err
+	}
+	for _, group := range existingGroups {
+		if err = r.releaseFromPaas(ctx, paas, group); err != nil {
+			return err
+		}
+	}
+
+	// ClusterResourceQuotas are listed regardless of the quota backend, since they might have been created before the
+	// backend was changed
+	var quotas quotav1.ClusterResourceQuotaList
+	if err := r.List(ctx, &quotas, managedByPaas); meta.IsNoMatchError(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, quota := range quotas.Items {
+		if err := r.releaseFromPaas(ctx, paas, &quota); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	quotav1 "github.com/openshift/api/quota/v1"
+	userv1 "github.com/openshift/api/user/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbac "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ = Describe("Paas deletion policy", Ordered, func() {
+	const (
+		paasName       = "retain-paas"
+		nsName         = "retain-paas-ns"
+		groupName      = "retain-paas-group"
+		otherQuotaName = "retain-other-quota"
+	)
+	var (
+		ctx        context.Context
+		paas       *v1alpha2.Paas
+		reconciler *PaasReconciler
+		owned      []client.Object
+	)
+
+	BeforeAll(func() {
+		ctx = context.WithValue(context.Background(), config.ContextKeyPaasConfig, v1alpha2.PaasConfig{})
+		reconciler = &PaasReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+		paas = &v1alpha2.Paas{
+			ObjectMeta: metav1.ObjectMeta{Name: paasName},
+			Spec: v1alpha2.PaasSpec{
+				Requestor:      paasName,
+				DeletionPolicy: v1alpha2.PaasDeletionPolicyRetain,
+			},
+		}
+		assurePaas(ctx, *paas)
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: paasName}, paas)).To(Succeed())
+
+		managedBy := map[string]string{ManagedByLabelKey: paasName}
+		owned = []client.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: nsName, Labels: managedBy}},
+			&rbac.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "retain-rb", Namespace: nsName},
+				RoleRef:    rbac.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "admin"},
+			},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "retain-secret", Namespace: nsName, Labels: managedBy}},
+			&networkingv1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: isolationNetworkPolicyName, Namespace: nsName, Labels: managedBy},
+			},
+			&userv1.Group{ObjectMeta: metav1.ObjectMeta{Name: groupName, Labels: managedBy}},
+			&quotav1.ClusterResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: "retain-paas-quota", Labels: managedBy}},
+			backendPaasClusterRoleBinding(paas, "retain-role"),
+		}
+		for _, obj := range owned {
+			Expect(controllerutil.SetControllerReference(paas, obj, k8sClient.Scheme())).To(Succeed())
+			Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+		}
+		otherPaas := &v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{Name: "other-paas", UID: "other-paas-uid"}}
+		otherQuota := &quotav1.ClusterResourceQuota{ObjectMeta: metav1.ObjectMeta{
+			Name:   otherQuotaName,
+			Labels: map[string]string{ManagedByLabelKey: otherPaas.Name},
+		}}
+		Expect(controllerutil.SetControllerReference(otherPaas, otherQuota, k8sClient.Scheme())).To(Succeed())
+		Expect(k8sClient.Create(ctx, otherQuota)).To(Succeed())
+	})
+
+	When("finalizing a Paas with the Retain deletion policy", func() {
+		It("should finalize successfully", func() {
+			Expect(reconciler.finalizePaas(ctx, paas)).To(Succeed())
+		})
+		It("should have released all resources from the Paas", func() {
+			for _, obj := range owned {
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+				Expect(obj.GetDeletionTimestamp()).To(BeNil())
+				Expect(paas.AmIOwner(obj.GetOwnerReferences())).To(BeFalse())
+				Expect(obj.GetLabels()).NotTo(HaveKey(ManagedByLabelKey))
+			}
+		})
+		It("should not have touched quotas managed by another Paas", func() {
+			quota := &quotav1.ClusterResourceQuota{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: otherQuotaName}, quota)).To(Succeed())
+			Expect(quota.Labels).To(HaveKeyWithValue(ManagedByLabelKey, "other-paas"))
+			Expect(quota.OwnerReferences).To(HaveLen(1))
+		})
+		It("should let a Paas with the same name re-adopt the group", func() {
+			desired := &userv1.Group{ObjectMeta: metav1.ObjectMeta{
+				Name:   groupName,
+				Labels: map[string]string{ManagedByLabelKey: paasName},
+			}}
+			Expect(reconciler.ensureGroup(ctx, paas, desired)).To(Succeed())
+			group := &userv1.Group{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: groupName}, group)).To(Succeed())
+			Expect(paas.AmIOwner(group.OwnerReferences)).To(BeTrue())
+			Expect(group.Labels).To(HaveKeyWithValue(ManagedByLabelKey, paasName))
+		})
+		It("should let a Paas with the same name re-adopt the clusterrolebinding", func() {
+			desired := backendPaasClusterRoleBinding(paas, "retain-role")
+			Expect(controllerutil.SetControllerReference(paas, desired, k8sClient.Scheme())).To(Succeed())
+			Expect(reconciler.ensurePaasClusterRoleBinding(ctx, paas, desired)).To(Succeed())
+			crb := &rbac.ClusterRoleBinding{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: desired.Name}, crb)).To(Succeed())
+			Expect(paas.AmIOwner(crb.OwnerReferences)).To(BeTrue())
+			Expect(crb.Labels).To(HaveKeyWithValue(ManagedByLabelKey, paasName))
+		})
+	})
+})
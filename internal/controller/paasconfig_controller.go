@@ -13,9 +13,11 @@ import (
 
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v5/internal/logging"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -27,7 +29,8 @@ const paasconfigFinalizer = "paasconfig.cpet.belastingdienst.nl/finalizer"
 // PaasConfigReconciler reconciles a PaasConfig object
 type PaasConfigReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder events.EventRecorder
 }
 
 // GetScheme is a simple getter for the Scheme of the PaasConfig Controller logic
@@ -65,6 +68,8 @@ func (pcr *PaasConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	if err := pcr.Get(ctx, req.NamespacedName, cfg); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	// Activated is only emitted when this config becomes the active config
+	wasActive := meta.IsStatusConditionTrue(cfg.Status.Conditions, v1alpha2.TypeActivePaasConfig)
 
 	meta.SetStatusCondition(
 		&cfg.Status.Conditions,
@@ -92,6 +97,7 @@ func (pcr *PaasConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		if requeue, err := pcr.finalize(ctx, cfg); requeue {
 			return ctrl.Result{}, err
 		}
+		pcr.recordEvent(cfg, corev1.EventTypeNormal, "Finalized", "Finalize", "finalized PaasConfig")
 		return ctrl.Result{}, nil
 	}
 
@@ -99,11 +105,30 @@ func (pcr *PaasConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	err := pcr.setSuccessfulCondition(ctx, cfg)
 	if err != nil {
 		logger.Err(err).Msg("failed to update PaasConfig status")
+		pcr.recordEvent(cfg, corev1.EventTypeWarning, "ActivateFailed", "Activate",
+			fmt.Sprintf("failed to activate PaasConfig: %s", err.Error()))
 		return ctrl.Result{}, nil
 	}
+	if !wasActive {
+		pcr.recordEvent(cfg, corev1.EventTypeNormal, "Activated", "Activate", "PaasConfig is the active config")
+	}
 	return ctrl.Result{}, nil
 }
 
+// recordEvent emits an event on the PaasConfig. When no recorder is set (e.a. in unittests), no events are emitted.
+func (pcr *PaasConfigReconciler) recordEvent(
+	cfg *v1alpha2.PaasConfig,
+	eventType string,
+	reason string,
+	action string,
+	note string,
+) {
+	if pcr.Recorder == nil {
+		return
+	}
+	pcr.Recorder.Eventf(cfg, nil, eventType, reason, action, "%s", note)
+}
+
 func (pcr *PaasConfigReconciler) addFinalizer(ctx context.Context, cfg *v1alpha2.PaasConfig) (requeue bool, err error) {
 	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerPaasConfigComponent)
 
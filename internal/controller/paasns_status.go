@@ -0,0 +1,121 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/logging"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// namespaceError is an error which occurred while reconciling the resources in a specific namespace of a Paas, so that
+// it can be reported on the PaasNS of that namespace only
+type namespaceError struct {
+	namespace string
+	err       error
+}
+
+// newNamespaceError returns err as a namespaceError for namespace, or nil when err is nil
+func newNamespaceError(namespace string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &namespaceError{namespace: namespace, err: err}
+}
+
+func (e *namespaceError) Error() string {
+	return e.err.Error()
+}
+
+func (e *namespaceError) Unwrap() error {
+	return e.err
+}
+
+// updatePaasNSStatuses updates the status of all PaasNS'es in nsDefs. When reconcileErr is nil, all PaasNS'es are
+// marked as Ready. Otherwise only the PaasNS of the namespace which failed (see namespaceError) is marked as HasErrors
+// with reconcileErr as message, and the status of all other PaasNS'es is left as is, since their namespaces might not
+// have been reconciled yet.
+func (r *PaasReconciler) updatePaasNSStatuses(
+	ctx context.Context,
+	nsDefs namespaceDefs,
+	reconcileErr error,
+) error {
+	var nsErr *namespaceError
+	errors.As(reconcileErr, &nsErr)
+	var errs []error
+	for _, nsDef := range nsDefs {
+		if nsDef.paasns == nil {
+			continue
+		}
+		if reconcileErr == nil {
+			errs = append(errs, r.setPaasNSSuccessfulStatus(ctx, nsDef))
+		} else if nsErr != nil && nsErr.namespace == nsDef.nsName {
+			errs = append(errs, r.setPaasNSErrorStatus(ctx, nsDef.paasns, reconcileErr))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// setPaasNSSuccessfulStatus marks the PaasNS of a namespaceDef as Ready, with the resulting namespace and the
+// effective groups of the namespaceDef
+func (r *PaasReconciler) setPaasNSSuccessfulStatus(ctx context.Context, nsDef namespaceDef) error {
+	paasns := nsDef.paasns
+	return r.patchPaasNSStatus(ctx, paasns, func() {
+		paasns.Status.Namespace = nsDef.nsName
+		paasns.Status.Groups = slices.Compact(slices.Sorted(slices.Values(nsDef.groups)))
+		meta.SetStatusCondition(&paasns.Status.Conditions, metav1.Condition{
+			Type:   v1alpha2.TypeReadyPaasNs,
+			Status: metav1.ConditionTrue, Reason: "Reconciling", ObservedGeneration: paasns.Generation,
+			Message: fmt.Sprintf("Reconciled (%s) successfully", paasns.Name),
+		})
+		meta.SetStatusCondition(&paasns.Status.Conditions, metav1.Condition{
+			Type:   v1alpha2.TypeHasErrorsPaasNs,
+			Status: metav1.ConditionFalse, Reason: "Reconciling", ObservedGeneration: paasns.Generation,
+			Message: fmt.Sprintf("Reconciled (%s) successfully", paasns.Name),
+		})
+	})
+}
+
+// setPaasNSErrorStatus marks a PaasNS as not Ready, and as HasErrors with err as message
+func (r *PaasReconciler) setPaasNSErrorStatus(ctx context.Context, paasns *v1alpha2.PaasNS, err error) error {
+	return r.patchPaasNSStatus(ctx, paasns, func() {
+		meta.SetStatusCondition(&paasns.Status.Conditions, metav1.Condition{
+			Type:   v1alpha2.TypeReadyPaasNs,
+			Status: metav1.ConditionFalse, Reason: "ReconcilingError", ObservedGeneration: paasns.Generation,
+			Message: fmt.Sprintf("Reconciling (%s) failed", paasns.Name),
+		})
+		meta.SetStatusCondition(&paasns.Status.Conditions, metav1.Condition{
+			Type:   v1alpha2.TypeHasErrorsPaasNs,
+			Status: metav1.ConditionTrue, Reason: "ReconcilingError", ObservedGeneration: paasns.Generation,
+			Message: err.Error(),
+		})
+	})
+}
+
+// patchPaasNSStatus applies mutate to the status of a PaasNS, and patches the status when it was changed.
+// A PaasNS is patched rather than updated, since the PaasNS might have been changed since it was listed.
+func (r *PaasReconciler) patchPaasNSStatus(ctx context.Context, paasns *v1alpha2.PaasNS, mutate func()) error {
+	_, logger := logging.GetLogComponent(ctx, logging.ControllerPaasComponent)
+	orig := paasns.DeepCopy()
+	mutate()
+	if equality.Semantic.DeepEqual(orig.Status, paasns.Status) {
+		return nil
+	}
+	if err := r.Status().Patch(ctx, paasns, client.MergeFrom(orig)); err != nil {
+		logger.Err(err).Msgf("failed to update status of PaasNS %s/%s", paasns.Namespace, paasns.Name)
+		return client.IgnoreNotFound(err)
+	}
+	return nil
+}
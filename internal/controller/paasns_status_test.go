@@ -0,0 +1,92 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("PaasNS statuses", Ordered, func() {
+	const (
+		paasName   = "paasns-status-paas"
+		failedNs   = "paasns-status-paas-failed"
+		succeedsNs = "paasns-status-paas-succeeds"
+	)
+	var (
+		ctx        context.Context
+		reconciler *PaasReconciler
+		nsDefs     namespaceDefs
+	)
+
+	getPaasNS := func(nsName string) *v1alpha2.PaasNS {
+		pns := nsDefs[nsName].paasns
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pns), pns)).To(Succeed())
+		return pns
+	}
+
+	BeforeAll(func() {
+		ctx = context.Background()
+		reconciler = &PaasReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+		nsDefs = namespaceDefs{}
+		for _, nsName := range []string{failedNs, succeedsNs} {
+			pns := v1alpha2.PaasNS{
+				ObjectMeta: metav1.ObjectMeta{Name: nsName[len(paasName)+1:], Namespace: paasName},
+				Spec:       v1alpha2.PaasNSSpec{Paas: paasName},
+			}
+			assurePaasNS(ctx, pns)
+			nsDefs[nsName] = namespaceDef{nsName: nsName, paasns: &pns}
+		}
+	})
+
+	When("reconciling a namespace failed", func() {
+		It("should only report the error on the PaasNS of that namespace", func() {
+			reconcileErr := fmt.Errorf("reconciling secrets: %w",
+				newNamespaceError(failedNs, errors.New("secret is invalid")))
+			Expect(reconciler.updatePaasNSStatuses(ctx, nsDefs, reconcileErr)).To(Succeed())
+
+			pns := getPaasNS(failedNs)
+			Expect(meta.IsStatusConditionTrue(pns.Status.Conditions, v1alpha2.TypeHasErrorsPaasNs)).To(BeTrue())
+			Expect(meta.FindStatusCondition(pns.Status.Conditions, v1alpha2.TypeHasErrorsPaasNs).Message).
+				To(ContainSubstring("secret is invalid"))
+			Expect(getPaasNS(succeedsNs).Status.Conditions).To(BeEmpty())
+		})
+	})
+
+	When("reconciling failed outside of the namespaces", func() {
+		It("should not report the error on any PaasNS", func() {
+			Expect(reconciler.updatePaasNSStatuses(ctx, nsDefs, errors.New("groups failed"))).To(Succeed())
+
+			Expect(meta.FindStatusCondition(getPaasNS(failedNs).Status.Conditions, v1alpha2.TypeHasErrorsPaasNs).
+				Message).To(ContainSubstring("secret is invalid"))
+			Expect(getPaasNS(succeedsNs).Status.Conditions).To(BeEmpty())
+		})
+	})
+
+	When("reconciling succeeded", func() {
+		It("should mark all PaasNS'es as Ready", func() {
+			Expect(reconciler.updatePaasNSStatuses(ctx, nsDefs, nil)).To(Succeed())
+
+			for nsName := range nsDefs {
+				pns := getPaasNS(nsName)
+				Expect(meta.IsStatusConditionTrue(pns.Status.Conditions, v1alpha2.TypeReadyPaasNs)).To(BeTrue())
+				Expect(meta.IsStatusConditionFalse(pns.Status.Conditions, v1alpha2.TypeHasErrorsPaasNs)).To(BeTrue())
+			}
+		})
+	})
+})
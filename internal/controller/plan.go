@@ -0,0 +1,612 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	"github.com/belastingdienst/opr-paas/v5/internal/logging"
+	quotav1 "github.com/openshift/api/quota/v1"
+	userv1 "github.com/openshift/api/user/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// isPlanMode returns true when a Paas should be reconciled in plan mode
+func isPlanMode(paas *v1alpha2.Paas) bool {
+	return paas.GetAnnotations()[v1alpha2.PlanAnnotation] == "true"
+}
+
+// planAnnotationChangedPredicate returns a predicate which triggers a reconciliation when plan mode is enabled or
+// disabled for a Paas
+func planAnnotationChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			return e.ObjectOld.GetAnnotations()[v1alpha2.PlanAnnotation] !=
+				e.ObjectNew.GetAnnotations()[v1alpha2.PlanAnnotation]
+		},
+	}
+}
+
+func newPlanItem(kind string, obj client.Object) v1alpha2.PaasPlanItem {
+	return v1alpha2.PaasPlanItem{Kind: kind, Name: obj.GetName(), Namespace: obj.GetNamespace()}
+}
+
+// reconcilePlan computes the changes that reconciling a Paas would apply, and writes them to the status of the Paas
+// without applying them.
+func (r *PaasReconciler) reconcilePlan(ctx context.Context, paas *v1alpha2.Paas) error {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerPaasComponent)
+	logger.Info().Msg("paas is in plan mode, computing plan without applying changes")
+
+	plan, err := r.planPaas(ctx, paas)
+	if err != nil {
+		return err
+	}
+	paas.Status.Plan = plan
+	meta.SetStatusCondition(&paas.Status.Conditions, metav1.Condition{
+		Type:   v1alpha2.TypeReadyPaas,
+		Status: metav1.ConditionUnknown, Reason: "Planned", ObservedGeneration: paas.Generation,
+		Message: fmt.Sprintf("Plan mode is enabled for (%s), changes have not been applied", paas.Name),
+	})
+	meta.SetStatusCondition(&paas.Status.Conditions, metav1.Condition{
+		Type:   v1alpha2.TypeHasErrorsPaas,
+		Status: metav1.ConditionFalse, Reason: "Planned", ObservedGeneration: paas.Generation,
+		Message: fmt.Sprintf("Planned (%s) successfully", paas.Name),
+	})
+	setPaasHasErrors(paas.Name, false)
+	return r.Status().Update(ctx, paas)
+}
+
+// planPaas returns all changes that would be applied when reconciling a Paas
+func (r *PaasReconciler) planPaas(ctx context.Context, paas *v1alpha2.Paas) (*v1alpha2.PaasPlan, error) {
+	plan := &v1alpha2.PaasPlan{ObservedGeneration: paas.Generation}
+	for _, planner := range []func(context.Context, *v1alpha2.Paas, *v1alpha2.PaasPlan) error{
+		r.planQuotas,
+		r.planClusterWideQuotas,
+		r.planGroups,
+		r.planNamespacedResources,
+		r.planClusterRoleBindings,
+	} {
+		if err := planner(ctx, paas, plan); err != nil {
+			return nil, err
+		}
+	}
+	for _, items := range [][]v1alpha2.PaasPlanItem{plan.WouldCreate, plan.WouldUpdate, plan.WouldDelete} {
+		slices.SortFunc(items, func(a, b v1alpha2.PaasPlanItem) int {
+			return cmp.Or(
+				cmp.Compare(a.Kind, b.Kind),
+				cmp.Compare(a.Namespace, b.Namespace),
+				cmp.Compare(a.Name, b.Name),
+			)
+		})
+	}
+	return plan, nil
+}
+
+// getForPlan retrieves the current state of a desired resource. It returns false when the resource does not exist.
+func (r *PaasReconciler) getForPlan(ctx context.Context, desired client.Object, found client.Object) (bool, error) {
+	err := r.Get(ctx, client.ObjectKeyFromObject(desired), found)
+	if err != nil && errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// labelsChanged returns true when not all desired labels (or annotations) are set on the existing resource
+func labelsChanged(desired map[string]string, existing map[string]string) bool {
+	for key, value := range desired {
+		if orgValue, exists := existing[key]; !exists || orgValue != value {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *PaasReconciler) planQuotas(ctx context.Context, paas *v1alpha2.Paas, plan *v1alpha2.PaasPlan) error {
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if myConfig.Spec.QuotaBackend.GetType() == v1alpha2.ConfigQuotaBackendResourceQuota {
+		var obsolete []*quotav1.ClusterResourceQuota
+		if obsolete, err = r.obsoleteClusterResourceQuotas(ctx, paas); err != nil {
+			return err
+		}
+		for _, quota := range obsolete {
+			plan.WouldDelete = append(plan.WouldDelete, newPlanItem("ClusterResourceQuota", quota))
+		}
+		return r.planResourceQuotas(ctx, paas, plan)
+	}
+	quotas, err := r.backendEnabledQuotas(ctx, paas)
+	if err != nil {
+		return err
+	}
+	for _, quota := range quotas {
+		found := &quotav1.ClusterResourceQuota{}
+		var exists bool
+		if exists, err = r.getForPlan(ctx, quota, found); err != nil {
+			return err
+		} else if !exists {
+			plan.WouldCreate = append(plan.WouldCreate, newPlanItem("ClusterResourceQuota", quota))
+		} else if !equality.Semantic.DeepEqual(found.Spec, quota.Spec) ||
+			!reflect.DeepEqual(found.OwnerReferences, quota.OwnerReferences) ||
+			labelsChanged(quota.Labels, found.Labels) || labelsChanged(quota.Annotations, found.Annotations) {
+			plan.WouldUpdate = append(plan.WouldUpdate, newPlanItem("ClusterResourceQuota", quota))
+		}
+	}
+
+	unneededQuotas, err := r.backendUnneededQuotas(ctx, paas)
+	if err != nil {
+		return err
+	}
+	for _, name := range unneededQuotas {
+		quota := &quotav1.ClusterResourceQuota{}
+		if err = r.Get(ctx, types.NamespacedName{Name: name}, quota); err != nil && errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		plan.WouldDelete = append(plan.WouldDelete, newPlanItem("ClusterResourceQuota", quota))
+	}
+	return nil
+}
+
+// planClusterWideQuotas adds the changes for the cluster-wide quotas, which a Paas shares with other Paas'es, to the
+// plan. The quota resources of a cluster-wide quota are aggregated from all Paas'es which use it.
+func (r *PaasReconciler) planClusterWideQuotas(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	plan *v1alpha2.PaasPlan,
+) error {
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	// The ResourceQuota backend only removes the Paas from cluster-wide quotas which it was added to before
+	rqBackend := myConfig.Spec.QuotaBackend.GetType() == v1alpha2.ConfigQuotaBackendResourceQuota
+	for _, capName := range slices.Sorted(maps.Keys(myConfig.Spec.Capabilities)) {
+		capConfig := myConfig.Spec.Capabilities[capName]
+		_, enabled := paas.Spec.Capabilities[capName]
+		enabled = enabled && !rqBackend
+		quota := backendClusterWideQuota(
+			clusterWideQuotaName(capName), capConfig.QuotaSettings.MinQuotas, myConfig.Spec.QuotaLabel)
+		found := &quotav1.ClusterResourceQuota{}
+		var exists bool
+		if exists, err = r.getForPlan(ctx, quota, found); meta.IsNoMatchError(err) && rqBackend {
+			return nil
+		} else if err != nil {
+			return err
+		} else if rqBackend && !paas.AmIOwner(found.OwnerReferences) {
+			continue
+		}
+		wouldBe := found.DeepCopy()
+		switch {
+		case enabled && !capConfig.QuotaSettings.Clusterwide:
+			continue
+		case enabled && !exists:
+			plan.WouldCreate = append(plan.WouldCreate, newPlanItem("ClusterResourceQuota", quota))
+			continue
+		case enabled:
+			if _, err = r.needsUpdate(wouldBe, quota, paas); err != nil {
+				return err
+			}
+		case !exists:
+			continue
+		case !capConfig.QuotaSettings.Clusterwide:
+			plan.WouldDelete = append(plan.WouldDelete, newPlanItem("ClusterResourceQuota", found))
+			continue
+		default:
+			wouldBe.OwnerReferences = paas.WithoutMe(wouldBe.OwnerReferences)
+			if len(wouldBe.OwnerReferences) == 0 {
+				plan.WouldDelete = append(plan.WouldDelete, newPlanItem("ClusterResourceQuota", found))
+				continue
+			}
+		}
+		if err = r.updateClusterWideQuotaResources(ctx, wouldBe); err != nil {
+			return err
+		}
+		if !equality.Semantic.DeepEqual(wouldBe, found) {
+			plan.WouldUpdate = append(plan.WouldUpdate, newPlanItem("ClusterResourceQuota", found))
+		}
+	}
+	return nil
+}
+
+// planResourceQuotas adds the changes for the ResourceQuotas of the ResourceQuota quota backend to the plan
+func (r *PaasReconciler) planResourceQuotas(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	plan *v1alpha2.PaasPlan,
+) error {
+	nsDefs, err := r.nsDefsFromPaas(ctx, paas)
+	if err != nil {
+		return err
+	}
+	quotas, err := r.backendResourceQuotas(ctx, paas, nsDefs)
+	if err != nil {
+		return err
+	}
+	for _, quota := range quotas {
+		found := &corev1.ResourceQuota{}
+		var exists bool
+		if exists, err = r.getForPlan(ctx, quota, found); err != nil {
+			return err
+		} else if !exists {
+			plan.WouldCreate = append(plan.WouldCreate, newPlanItem("ResourceQuota", quota))
+		} else if !paas.AmIOwner(found.OwnerReferences) || !equality.Semantic.DeepEqual(found.Spec, quota.Spec) {
+			plan.WouldUpdate = append(plan.WouldUpdate, newPlanItem("ResourceQuota", quota))
+		}
+	}
+
+	var existing corev1.ResourceQuotaList
+	if err = r.List(ctx, &existing, client.MatchingLabels{ManagedByLabelKey: paas.Name}); err != nil {
+		return err
+	}
+	for _, quota := range existing.Items {
+		if !paas.AmIOwner(quota.OwnerReferences) || slices.ContainsFunc(quotas, func(d *corev1.ResourceQuota) bool {
+			return d.Namespace == quota.Namespace && d.Name == quota.Name
+		}) {
+			continue
+		}
+		plan.WouldDelete = append(plan.WouldDelete, newPlanItem("ResourceQuota", &quota))
+	}
+	return nil
+}
+
+func (r *PaasReconciler) planGroups(ctx context.Context, paas *v1alpha2.Paas, plan *v1alpha2.PaasPlan) error {
+	desiredGroups, err := r.backendGroups(ctx, paas)
+	if err != nil {
+		return err
+	}
+	for _, group := range desiredGroups {
+		found := &userv1.Group{}
+		var exists bool
+		if exists, err = r.getForPlan(ctx, group, found); err != nil {
+			return err
+		} else if !exists {
+			plan.WouldCreate = append(plan.WouldCreate, newPlanItem("Group", group))
+		} else if !paas.AmIOwner(found.OwnerReferences) || !reflect.DeepEqual(group.Users, found.Users) ||
+			!reflect.DeepEqual(group.Labels, found.Labels) {
+			plan.WouldUpdate = append(plan.WouldUpdate, newPlanItem("Group", group))
+		}
+	}
+
+	existingGroups, err := r.getExistingGroups(ctx, paas)
+	if err != nil {
+		return err
+	}
+	for _, group := range existingGroups {
+		if !isGroupInGroups(group, desiredGroups) {
+			plan.WouldDelete = append(plan.WouldDelete, newPlanItem("Group", group))
+		}
+	}
+	return nil
+}
+
+func (r *PaasReconciler) planNamespacedResources(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	plan *v1alpha2.PaasPlan,
+) error {
+	nsDefs, err := r.nsDefsFromPaas(ctx, paas)
+	if err != nil {
+		return err
+	}
+	for _, nsDef := range nsDefs {
+		if err = r.planNamespace(ctx, paas, nsDef, plan); err != nil {
+			return err
+		}
+	}
+
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	retention := paas.GetNamespaceRetention(myConfig)
+	var nss corev1.NamespaceList
+	if err = r.List(ctx, &nss, client.MatchingLabels{ManagedByLabelKey: paas.Name}); err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, ns := range nss.Items {
+		if _, exists := nsDefs[ns.Name]; exists {
+			continue
+		}
+		switch action, _ := retainObsoleteNamespace(retention, ns, now); action {
+		case obsoleteNamespaceDelete:
+			plan.WouldDelete = append(plan.WouldDelete, newPlanItem("Namespace", &ns))
+		case obsoleteNamespaceOrphan, obsoleteNamespaceMarkPending:
+			plan.WouldUpdate = append(plan.WouldUpdate, newPlanItem("Namespace", &ns))
+		}
+	}
+	return nil
+}
+
+// planNamespace adds the changes for a namespace, and the RoleBindings, Secrets, NetworkPolicy and baseline resources
+// in that namespace to the plan
+func (r *PaasReconciler) planNamespace(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	nsDef namespaceDef,
+	plan *v1alpha2.PaasPlan,
+) error {
+	ns, err := r.backendNamespace(ctx, paas, nsDef.nsName, nsDef.quotaName)
+	if err != nil {
+		return err
+	}
+	foundNs := &corev1.Namespace{}
+	nsExists, err := r.getForPlan(ctx, ns, foundNs)
+	if err != nil {
+		return err
+	} else if !nsExists {
+		plan.WouldCreate = append(plan.WouldCreate, newPlanItem("Namespace", ns))
+	} else if !paas.AmIOwner(foundNs.OwnerReferences) || labelsChanged(ns.Labels, foundNs.Labels) {
+		plan.WouldUpdate = append(plan.WouldUpdate, newPlanItem("Namespace", ns))
+	}
+
+	rbs, err := r.backendNamespaceRoleBindings(ctx, paas, nsDef)
+	if err != nil {
+		return err
+	}
+	for _, rb := range rbs {
+		found := &rbac.RoleBinding{}
+		exists := false
+		// When the namespace does not exist yet, neither do the RoleBindings in it
+		if nsExists {
+			if exists, err = r.getForPlan(ctx, rb, found); err != nil {
+				return err
+			}
+		}
+		switch {
+		case len(rb.Subjects) == 0 && exists:
+			plan.WouldDelete = append(plan.WouldDelete, newPlanItem("RoleBinding", rb))
+		case len(rb.Subjects) == 0:
+			continue
+		case !exists:
+			plan.WouldCreate = append(plan.WouldCreate, newPlanItem("RoleBinding", rb))
+		case !paas.AmIOwner(found.OwnerReferences) || !reflect.DeepEqual(found.Subjects, rb.Subjects):
+			plan.WouldUpdate = append(plan.WouldUpdate, newPlanItem("RoleBinding", rb))
+		}
+	}
+
+	if err = r.planSecrets(ctx, paas, nsDef, plan); err != nil {
+		return err
+	}
+	if err = r.planNetworkPolicy(ctx, paas, nsDef, plan); err != nil {
+		return err
+	}
+	return r.planBaselineResources(ctx, paas, nsDef, plan)
+}
+
+// planSecrets adds the changes for the Secrets in a namespace to the plan
+func (r *PaasReconciler) planSecrets(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	nsDef namespaceDef,
+	plan *v1alpha2.PaasPlan,
+) error {
+	desired, err := r.backendSecrets(ctx, paas, nsDef.paasns, nsDef.nsName, nsDef.secrets)
+	if err != nil {
+		return err
+	}
+	for _, secret := range desired.Items {
+		found := &corev1.Secret{}
+		var exists bool
+		if exists, err = r.getForPlan(ctx, &secret, found); err != nil {
+			return err
+		} else if !exists {
+			plan.WouldCreate = append(plan.WouldCreate, newPlanItem("Secret", &secret))
+		} else if !secretUpToDate(paas, found, &secret) {
+			plan.WouldUpdate = append(plan.WouldUpdate, newPlanItem("Secret", &secret))
+		}
+	}
+
+	existing, err := r.getExistingSecrets(ctx, paas, nsDef.nsName)
+	if err != nil {
+		return err
+	}
+	for _, secret := range existing.Items {
+		if !isSecretInDesiredSecrets(secret, desired) {
+			plan.WouldDelete = append(plan.WouldDelete, newPlanItem("Secret", &secret))
+		}
+	}
+	return nil
+}
+
+// planNetworkPolicy adds the changes for the NetworkPolicy which isolates a namespace to the plan
+func (r *PaasReconciler) planNetworkPolicy(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	nsDef namespaceDef,
+	plan *v1alpha2.PaasPlan,
+) error {
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	np, err := r.backendNetworkPolicy(paas, myConfig, nsDef)
+	if err != nil {
+		return err
+	}
+	found := &networkingv1.NetworkPolicy{}
+	exists, err := r.getForPlan(ctx, np, found)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !myConfig.Spec.FeatureFlags.NetworkIsolation:
+		if exists && paas.AmIOwner(found.OwnerReferences) {
+			plan.WouldDelete = append(plan.WouldDelete, newPlanItem("NetworkPolicy", np))
+		}
+	case !exists:
+		plan.WouldCreate = append(plan.WouldCreate, newPlanItem("NetworkPolicy", np))
+	case !paas.AmIOwner(found.OwnerReferences):
+		// NetworkPolicies which are not owned by the Paas are not taken over
+	case !networkPolicyUpToDate(paas, found, np):
+		plan.WouldUpdate = append(plan.WouldUpdate, newPlanItem("NetworkPolicy", np))
+	}
+	return nil
+}
+
+// planBaselineResources adds the changes for the baseline resources in a namespace to the plan
+func (r *PaasReconciler) planBaselineResources(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	nsDef namespaceDef,
+	plan *v1alpha2.PaasPlan,
+) error {
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	desired, err := r.backendBaselineResources(ctx, paas, nsDef)
+	if err != nil {
+		return err
+	}
+	for _, obj := range desired {
+		found := &unstructured.Unstructured{}
+		found.SetGroupVersionKind(obj.GroupVersionKind())
+		var exists bool
+		if exists, err = r.getForPlan(ctx, obj, found); err != nil {
+			return err
+		} else if !exists {
+			plan.WouldCreate = append(plan.WouldCreate, newPlanItem(obj.GetKind(), obj))
+		} else if !baselineResourceUpToDate(paas, found, obj) {
+			plan.WouldUpdate = append(plan.WouldUpdate, newPlanItem(obj.GetKind(), obj))
+		}
+	}
+
+	obsolete, err := r.obsoleteBaselineResources(
+		ctx, paas, nsDef.nsName, baselineResourceKinds(paas, myConfig), desired)
+	if err != nil {
+		return err
+	}
+	for _, obj := range obsolete {
+		plan.WouldDelete = append(plan.WouldDelete, newPlanItem(obj.GetKind(), &obj))
+	}
+	return nil
+}
+
+// planClusterRoleBindings adds the changes for the ClusterRoleBindings of the capabilities of a Paas to the plan. In
+// the Shared layout, a ClusterRoleBinding would be updated when service accounts of the Paas are added to or removed
+// from it, and deleted when no subjects would be left.
+func (r *PaasReconciler) planClusterRoleBindings(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	plan *v1alpha2.PaasPlan,
+) error {
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	nsDefs, err := r.nsDefsFromPaas(ctx, paas)
+	if err != nil {
+		return err
+	}
+	perPaas := myConfig.Spec.ClusterRoleBindingLayout == v1alpha2.ConfigClusterRoleBindingLayoutPerPaas
+	desired := renderClusterRoleBindings(ctx, myConfig, paas, nsDefs)
+	changed := map[string]bool{}
+	for _, crb := range desired {
+		found := &rbac.ClusterRoleBinding{}
+		var exists bool
+		if exists, err = r.getForPlan(ctx, crb, found); err != nil {
+			return err
+		} else if !exists {
+			plan.WouldCreate = append(plan.WouldCreate, newPlanItem("ClusterRoleBinding", crb))
+			continue
+		}
+		var upToDate bool
+		if perPaas {
+			upToDate = paas.AmIOwner(found.OwnerReferences) && reflect.DeepEqual(found.Subjects, crb.Subjects) &&
+				maps.Equal(found.Labels, crb.Labels)
+		} else {
+			// A shared ClusterRoleBinding also holds the service accounts of other Paas'es
+			upToDate = !slices.ContainsFunc(crb.Subjects, func(s rbac.Subject) bool {
+				return !slices.ContainsFunc(found.Subjects, sameSubject(s))
+			})
+		}
+		if !upToDate {
+			plan.WouldUpdate = append(plan.WouldUpdate, newPlanItem("ClusterRoleBinding", crb))
+			changed[crb.Name] = true
+		}
+	}
+
+	// Service accounts of the Paas which are no longer desired are removed from the shared ClusterRoleBindings
+	nsRE, err := r.paasNamespacesRE(ctx, myConfig, paas)
+	if err != nil {
+		return err
+	}
+	shared, err := r.getClusterRoleBindingsWithLabel(ctx, defaultCRBLabels)
+	if err != nil {
+		return err
+	}
+	for _, crb := range shared.Items {
+		var keep []rbac.Subject
+		if i := slices.IndexFunc(desired, func(d *rbac.ClusterRoleBinding) bool { return d.Name == crb.Name }); i >= 0 {
+			keep = desired[i].Subjects
+		}
+		remaining := slices.DeleteFunc(slices.Clone(crb.Subjects), func(s rbac.Subject) bool {
+			return s.Kind == "ServiceAccount" && nsRE.MatchString(s.Namespace) &&
+				!slices.ContainsFunc(keep, sameSubject(s))
+		})
+		switch {
+		case len(remaining) == len(crb.Subjects) || changed[crb.Name]:
+			continue
+		case len(remaining) == 0:
+			plan.WouldDelete = append(plan.WouldDelete, newPlanItem("ClusterRoleBinding", &crb))
+		default:
+			plan.WouldUpdate = append(plan.WouldUpdate, newPlanItem("ClusterRoleBinding", &crb))
+		}
+	}
+
+	// ClusterRoleBindings of the Paas in the PerPaas layout which are no longer desired are deleted
+	owned, err := r.getClusterRoleBindingsWithLabel(ctx, client.MatchingLabels{
+		crbTypeLabelKey:   perPaasCRBType,
+		ManagedByLabelKey: paas.Name,
+	})
+	if err != nil {
+		return err
+	}
+	for _, crb := range owned.Items {
+		if !paas.AmIOwner(crb.OwnerReferences) || (perPaas &&
+			slices.ContainsFunc(desired, func(d *rbac.ClusterRoleBinding) bool { return d.Name == crb.Name })) {
+			continue
+		}
+		plan.WouldDelete = append(plan.WouldDelete, newPlanItem("ClusterRoleBinding", &crb))
+	}
+	return nil
+}
+
+// sameSubject returns a function which returns true for subjects with the kind, namespace and name of subject
+func sameSubject(subject rbac.Subject) func(rbac.Subject) bool {
+	return func(s rbac.Subject) bool {
+		return s.Kind == subject.Kind && s.Namespace == subject.Namespace && s.Name == subject.Name
+	}
+}
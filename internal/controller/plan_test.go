@@ -0,0 +1,257 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	paasquota "github.com/belastingdienst/opr-paas/v5/pkg/quota"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	userv1 "github.com/openshift/api/user/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+var _ = Describe("Paas plan mode", Ordered, func() {
+	const (
+		paasName      = "paas-plan"
+		ns1Name       = "ns1"
+		ns2Name       = "ns2"
+		paasGroupName = "plan-group"
+		funcRoleName  = "plan-func-role"
+		techRoleName  = "plan-tech-role"
+		capName       = "plancap"
+		capRoleName   = "plan-cap-role"
+		keysNamespace = "plan-system"
+		keysSecret    = "plan-keys"
+		secretURL     = "ssh://git@scm/some-repo.git"
+		limits        = "plan-limits"
+	)
+	var (
+		reconciler *PaasReconciler
+		request    controllerruntime.Request
+		myConfig   *v1alpha2.PaasConfig
+		ns1        = join(paasName, ns1Name)
+		ns2        = join(paasName, ns2Name)
+		capNs      = join(paasName, capName)
+		groupName  = join(paasName, paasGroupName)
+		secretName = secretNamespacedName(ns1, secretURL).Name
+	)
+	ctx := context.Background()
+
+	setPlanMode := func(enabled bool) {
+		paas := getPaas(ctx, paasName)
+		if enabled {
+			paas.Annotations = map[string]string{v1alpha2.PlanAnnotation: "true"}
+		} else {
+			delete(paas.Annotations, v1alpha2.PlanAnnotation)
+		}
+		Expect(k8sClient.Update(ctx, paas)).To(Succeed())
+	}
+
+	BeforeAll(func() {
+		assureNamespace(ctx, keysNamespace)
+		mycrypt, privateKey, err := newGeneratedCrypt(paasName)
+		Expect(err).NotTo(HaveOccurred())
+		createPaasPrivateKeySecret(ctx, keysNamespace, keysSecret, privateKey)
+		encryptedSecret, err := mycrypt.Encrypt([]byte("planSecret"))
+		Expect(err).NotTo(HaveOccurred())
+
+		myConfig = &v1alpha2.PaasConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "paas-config"},
+			Spec: v1alpha2.PaasConfigSpec{
+				DecryptKeysSecret: v1alpha2.NamespacedName{Name: keysSecret, Namespace: keysNamespace},
+				QuotaLabel:        "q.lbl",
+				RoleMappings: v1alpha2.ConfigRoleMappings{
+					funcRoleName: []string{techRoleName},
+				},
+				Capabilities: v1alpha2.ConfigCapabilities{capName: v1alpha2.ConfigCapability{
+					QuotaSettings: v1alpha2.ConfigQuotaSettings{
+						Clusterwide: true,
+						Ratio:       1,
+						DefQuota:    paasquota.Quota{"cpu": resourcev1.MustParse("1")},
+					},
+					DefaultPermissions: v1alpha2.ConfigCapPerm{"plan-sa": []string{capRoleName}},
+				}},
+				FeatureFlags: v1alpha2.ConfigFeatureFlags{NetworkIsolation: true},
+				BaselineResources: map[string]v1alpha2.ConfigBaselineResource{limits: {Template: `apiVersion: v1
+kind: LimitRange
+metadata:
+  name: ` + limits + `
+spec:
+  limits:
+    - type: Container
+      default:
+        cpu: 500m`}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, myConfig)).To(Succeed())
+		pcReconciler := &PaasConfigReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		_, err = pcReconciler.Reconcile(ctx, controllerruntime.Request{
+			NamespacedName: types.NamespacedName{Name: myConfig.Name},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		assurePaas(ctx, v1alpha2.Paas{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        paasName,
+				Annotations: map[string]string{v1alpha2.PlanAnnotation: "true"},
+			},
+			Spec: v1alpha2.PaasSpec{
+				Quota:        paasquota.Quota{"cpu": resourcev1.MustParse("1")},
+				Capabilities: v1alpha2.PaasCapabilities{capName: v1alpha2.PaasCapability{}},
+				Namespaces: v1alpha2.PaasNamespaces{
+					ns1Name: v1alpha2.PaasNamespace{},
+					ns2Name: v1alpha2.PaasNamespace{},
+				},
+				Secrets: map[string]string{secretURL: encryptedSecret},
+				Groups: v1alpha2.PaasGroups{
+					paasGroupName: v1alpha2.PaasGroup{Users: []string{"user1"}, Roles: []string{funcRoleName}},
+				},
+			},
+		})
+		request.Name = paasName
+		reconciler = &PaasReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+	})
+
+	AfterAll(func() {
+		waitForDeletePaasConfig(ctx, myConfig)
+	})
+
+	When("reconciling a new Paas in plan mode", func() {
+		It("should reconcile successfully", func() {
+			_, err := reconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+		It("should list all resources that would be created", func() {
+			paas := getPaas(ctx, paasName)
+			Expect(paas.Status.Plan).NotTo(BeNil())
+			expected := []v1alpha2.PaasPlanItem{
+				{Kind: "ClusterResourceQuota", Name: paasName},
+				{Kind: "ClusterResourceQuota", Name: clusterWideQuotaName(capName)},
+				{Kind: "ClusterRoleBinding", Name: join("paas", capRoleName)},
+				{Kind: "Group", Name: groupName},
+			}
+			for _, nsName := range []string{ns1, ns2, capNs} {
+				expected = append(expected,
+					v1alpha2.PaasPlanItem{Kind: "Namespace", Name: nsName},
+					v1alpha2.PaasPlanItem{Kind: "RoleBinding", Name: join("paas", techRoleName), Namespace: nsName},
+					v1alpha2.PaasPlanItem{Kind: "Secret", Name: secretName, Namespace: nsName},
+					v1alpha2.PaasPlanItem{Kind: "NetworkPolicy", Name: isolationNetworkPolicyName, Namespace: nsName},
+					v1alpha2.PaasPlanItem{Kind: "LimitRange", Name: limits, Namespace: nsName},
+				)
+			}
+			Expect(paas.Status.Plan.WouldCreate).To(ConsistOf(expected))
+			Expect(paas.Status.Plan.WouldUpdate).To(BeEmpty())
+			Expect(paas.Status.Plan.WouldDelete).To(BeEmpty())
+			ready := meta.FindStatusCondition(paas.Status.Conditions, v1alpha2.TypeReadyPaas)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Reason).To(Equal("Planned"))
+		})
+		It("should not have applied anything", func() {
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: ns1}, &corev1.Namespace{})).
+				To(MatchError(ContainSubstring("not found")))
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: groupName}, &userv1.Group{})).
+				To(MatchError(ContainSubstring("not found")))
+		})
+	})
+
+	When("disabling plan mode", func() {
+		It("should apply all changes and clear the plan", func() {
+			setPlanMode(false)
+			_, err := reconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: ns1}, &corev1.Namespace{})).To(Succeed())
+			Expect(getPaas(ctx, paasName).Status.Plan).To(BeNil())
+		})
+	})
+
+	When("removing a namespace from a Paas in plan mode", func() {
+		It("should list the namespace as would be deleted, without deleting it", func() {
+			setPlanMode(true)
+			paas := getPaas(ctx, paasName)
+			delete(paas.Spec.Namespaces, ns2Name)
+			Expect(k8sClient.Update(ctx, paas)).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+
+			plan := getPaas(ctx, paasName).Status.Plan
+			Expect(plan).NotTo(BeNil())
+			Expect(plan.WouldCreate).To(BeEmpty())
+			Expect(plan.WouldUpdate).To(BeEmpty())
+			Expect(plan.WouldDelete).To(ConsistOf(v1alpha2.PaasPlanItem{Kind: "Namespace", Name: ns2}))
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: ns2}, &corev1.Namespace{})).To(Succeed())
+		})
+	})
+
+	When("removing a capability from a Paas in plan mode", func() {
+		It("should list the cluster scoped resources of the capability as would be deleted", func() {
+			paas := getPaas(ctx, paasName)
+			delete(paas.Spec.Capabilities, capName)
+			Expect(k8sClient.Update(ctx, paas)).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+
+			plan := getPaas(ctx, paasName).Status.Plan
+			Expect(plan).NotTo(BeNil())
+			Expect(plan.WouldCreate).To(BeEmpty())
+			Expect(plan.WouldUpdate).To(BeEmpty())
+			Expect(plan.WouldDelete).To(ConsistOf(
+				v1alpha2.PaasPlanItem{Kind: "ClusterResourceQuota", Name: clusterWideQuotaName(capName)},
+				v1alpha2.PaasPlanItem{Kind: "ClusterRoleBinding", Name: join("paas", capRoleName)},
+				v1alpha2.PaasPlanItem{Kind: "Namespace", Name: capNs},
+				v1alpha2.PaasPlanItem{Kind: "Namespace", Name: ns2},
+			))
+		})
+	})
+
+	When("a PaasNS of a Paas in plan mode exceeds the PaasNS limits", func() {
+		It("should not update the status of the PaasNS", func() {
+			pns := &v1alpha2.PaasNS{
+				ObjectMeta: metav1.ObjectMeta{Name: "plan-nested", Namespace: ns1},
+				Spec:       v1alpha2.PaasNSSpec{Paas: paasName},
+			}
+			assurePaasNS(ctx, *pns)
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: myConfig.Name}, myConfig)).To(Succeed())
+			myConfig.Spec.PaasNSLimits.MaxNamespaces = 1
+			Expect(k8sClient.Update(ctx, myConfig)).To(Succeed())
+			pcReconciler := &PaasConfigReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := pcReconciler.Reconcile(ctx, controllerruntime.Request{
+				NamespacedName: types.NamespacedName{Name: myConfig.Name},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = reconciler.Reconcile(ctx, request)
+			Expect(err).To(MatchError(ContainSubstring("exceeds the maximum number of 1 namespaces")))
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: pns.Name, Namespace: ns1}, pns)).To(Succeed())
+			Expect(pns.Status.Conditions).To(BeEmpty())
+			Expect(k8sClient.Delete(ctx, pns)).To(Succeed())
+		})
+	})
+
+	When("the plan annotation changes", func() {
+		It("should trigger a reconciliation", func() {
+			oldPaas := &v1alpha2.Paas{}
+			newPaas := &v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{v1alpha2.PlanAnnotation: "true"},
+			}}
+			pred := planAnnotationChangedPredicate()
+			Expect(pred.Update(event.UpdateEvent{ObjectOld: oldPaas, ObjectNew: newPaas})).To(BeTrue())
+			Expect(pred.Update(event.UpdateEvent{ObjectOld: newPaas, ObjectNew: newPaas})).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,305 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	"github.com/belastingdienst/opr-paas/v5/internal/logging"
+	paasquota "github.com/belastingdienst/opr-paas/v5/pkg/quota"
+	quotav1 "github.com/openshift/api/quota/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// quotaBackend enforces the quotas of a Paas, with the backend which is selected in the PaasConfig
+type quotaBackend interface {
+	// reconcileQuotas ensures the cluster scoped quotas of a Paas
+	reconcileQuotas(ctx context.Context, paas *v1alpha2.Paas) error
+	// reconcileClusterWideQuota ensures the contribution of a Paas to the quotas it shares with other Paas'es
+	reconcileClusterWideQuota(ctx context.Context, paas *v1alpha2.Paas) error
+	// reconcileNamespaceQuotas ensures the quotas in the namespaces of a Paas
+	reconcileNamespaceQuotas(ctx context.Context, paas *v1alpha2.Paas, nsDefs namespaceDefs) error
+	// finalizeClusterWideQuotas removes a Paas from the quotas it shares with other Paas'es
+	finalizeClusterWideQuotas(ctx context.Context, paas *v1alpha2.Paas) error
+	// quotaUsage returns the usage of all quotas which apply to a Paas
+	quotaUsage(ctx context.Context, paas *v1alpha2.Paas) ([]v1alpha2.PaasQuotaUsage, error)
+}
+
+// clusterResourceQuotaBackend enforces every quota of a Paas with an OpenShift ClusterResourceQuota
+type clusterResourceQuotaBackend struct {
+	*PaasReconciler
+}
+
+// reconcileNamespaceQuotas removes the ResourceQuotas which were created by the ResourceQuota backend, since
+// ClusterResourceQuotas select the namespaces of a Paas by label instead
+func (b clusterResourceQuotaBackend) reconcileNamespaceQuotas(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	_ namespaceDefs,
+) error {
+	return b.deleteObsoleteResourceQuotas(ctx, paas, nil)
+}
+
+// resourceQuotaBackend enforces every quota of a Paas with a ResourceQuota in each namespace that uses the quota
+type resourceQuotaBackend struct {
+	r *PaasReconciler
+}
+
+// reconcileQuotas deletes the ClusterResourceQuotas which were created for a Paas by the ClusterResourceQuota
+// backend, since the ResourceQuota backend has no cluster scoped quotas. Otherwise these would keep enforcing the
+// quotas on the namespaces of the Paas after switching quota backends.
+func (b resourceQuotaBackend) reconcileQuotas(ctx context.Context, paas *v1alpha2.Paas) error {
+	quotas, err := b.r.obsoleteClusterResourceQuotas(ctx, paas)
+	if err != nil {
+		return err
+	}
+	for _, quota := range quotas {
+		err = b.r.Delete(ctx, quota)
+		b.r.recordEvent(paas, quota, eventActionDelete, err)
+		if err = client.IgnoreNotFound(err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileClusterWideQuota removes a Paas from the cluster-wide quotas which it was added to by the
+// ClusterResourceQuota backend, since ResourceQuotas cannot be shared between Paas'es
+func (b resourceQuotaBackend) reconcileClusterWideQuota(ctx context.Context, paas *v1alpha2.Paas) error {
+	quotas, err := b.r.obsoleteClusterWideQuotas(ctx, paas)
+	if err != nil {
+		return err
+	}
+	for _, quota := range quotas {
+		var capName string
+		if capName, err = clusterWideCapabilityName(quota.Name); err != nil {
+			return err
+		}
+		if err = b.r.removeFromClusterWideQuota(ctx, paas, capName); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finalizeClusterWideQuotas has nothing to do, since ResourceQuotas cannot be shared between Paas'es
+func (resourceQuotaBackend) finalizeClusterWideQuotas(context.Context, *v1alpha2.Paas) error {
+	return nil
+}
+
+// reconcileNamespaceQuotas ensures the ResourceQuotas in the namespaces of a Paas, and removes ResourceQuotas which
+// are no longer required
+func (b resourceQuotaBackend) reconcileNamespaceQuotas(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	nsDefs namespaceDefs,
+) error {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerClusterQuotaComponent)
+	quotas, err := b.r.backendResourceQuotas(ctx, paas, nsDefs)
+	if err != nil {
+		return err
+	}
+	if err = b.r.deleteObsoleteResourceQuotas(ctx, paas, quotas); err != nil {
+		return err
+	}
+	for _, quota := range quotas {
+		if err = b.r.ensureResourceQuota(ctx, paas, quota); err != nil {
+			logger.Err(err).Msgf("failure while reconciling quota %s/%s", quota.Namespace, quota.Name)
+			return newNamespaceError(quota.Namespace, err)
+		}
+	}
+	return nil
+}
+
+// obsoleteClusterResourceQuotas returns the ClusterResourceQuotas which the ClusterResourceQuota backend created for
+// a Paas and its capabilities, when the ResourceQuota backend is used instead
+func (r *PaasReconciler) obsoleteClusterResourceQuotas(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+) ([]*quotav1.ClusterResourceQuota, error) {
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{paas.Name}
+	for capName := range myConfig.Spec.Capabilities {
+		names = append(names, join(paas.Name, capName))
+	}
+	return r.clusterResourceQuotasOwnedBy(ctx, paas, names)
+}
+
+// obsoleteClusterWideQuotas returns the cluster-wide quotas which the ClusterResourceQuota backend added a Paas to,
+// when the ResourceQuota backend is used instead
+func (r *PaasReconciler) obsoleteClusterWideQuotas(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+) ([]*quotav1.ClusterResourceQuota, error) {
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for capName := range myConfig.Spec.Capabilities {
+		names = append(names, clusterWideQuotaName(capName))
+	}
+	return r.clusterResourceQuotasOwnedBy(ctx, paas, names)
+}
+
+// clusterResourceQuotasOwnedBy returns the ClusterResourceQuotas with one of names, which exist and are owned by paas.
+// ClusterResourceQuotas are looked up by name, since ClusterResourceQuotas which were created by older versions of
+// the operator have no ManagedByLabelKey label. On clusters without ClusterResourceQuotas, none are returned.
+func (r *PaasReconciler) clusterResourceQuotasOwnedBy(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	names []string,
+) (owned []*quotav1.ClusterResourceQuota, err error) {
+	slices.Sort(names)
+	for _, name := range names {
+		quota := &quotav1.ClusterResourceQuota{}
+		if err = r.Get(ctx, types.NamespacedName{Name: name}, quota); meta.IsNoMatchError(err) {
+			return nil, nil
+		} else if k8serrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if paas.AmIOwner(quota.OwnerReferences) {
+			owned = append(owned, quota)
+		}
+	}
+	return owned, nil
+}
+
+// quotaBackend returns the quota backend which is selected in the PaasConfig
+func (r *PaasReconciler) quotaBackend(ctx context.Context) (quotaBackend, error) {
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if myConfig.Spec.QuotaBackend.GetType() == v1alpha2.ConfigQuotaBackendResourceQuota {
+		return resourceQuotaBackend{r: r}, nil
+	}
+	return clusterResourceQuotaBackend{PaasReconciler: r}, nil
+}
+
+// backendResourceQuotas returns the ResourceQuotas for the namespaces of a Paas. Every quota of the Paas is
+// distributed over the namespaces that use it, as configured in the PaasConfig.
+func (r *PaasReconciler) backendResourceQuotas(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	nsDefs namespaceDefs,
+) (quotas []*corev1.ResourceQuota, err error) {
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	clusterQuotas, err := r.backendEnabledQuotas(ctx, paas)
+	if err != nil {
+		return nil, err
+	}
+	for _, clusterQuota := range clusterQuotas {
+		var nsNames []string
+		for _, nsName := range slices.Sorted(maps.Keys(nsDefs)) {
+			if nsDefs[nsName].quotaName == clusterQuota.Name {
+				nsNames = append(nsNames, nsName)
+			}
+		}
+		hard := paasquota.Quota(clusterQuota.Spec.Quota.Hard)
+		if myConfig.Spec.QuotaBackend.GetDistribution() == v1alpha2.ConfigQuotaDistributionEven {
+			hard = hard.Divided(len(nsNames))
+		}
+		for _, nsName := range nsNames {
+			labels := maps.Clone(clusterQuota.Labels)
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[ManagedByLabelKey] = paas.Name
+			quota := &corev1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        clusterQuota.Name,
+					Namespace:   nsName,
+					Labels:      labels,
+					Annotations: maps.Clone(clusterQuota.Annotations),
+				},
+				Spec: corev1.ResourceQuotaSpec{
+					Hard: corev1.ResourceList(hard.DeepCopy()),
+				},
+			}
+			if err = controllerutil.SetControllerReference(paas, quota, r.Scheme); err != nil {
+				return nil, err
+			}
+			quotas = append(quotas, quota)
+		}
+	}
+	return quotas, nil
+}
+
+// ensureResourceQuota ensures the presence of a ResourceQuota, which is created or updated as desired
+func (r *PaasReconciler) ensureResourceQuota(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	quota *corev1.ResourceQuota,
+) error {
+	found := &corev1.ResourceQuota{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(quota), found)
+	if err != nil && k8serrors.IsNotFound(err) {
+		err = r.Create(ctx, quota)
+		r.recordEvent(paas, quota, eventActionCreate, err)
+		return err
+	} else if err != nil {
+		return err
+	}
+	annotationsChanged := ensureAnnotations(found, quota)
+	if !annotationsChanged && paas.AmIOwner(found.OwnerReferences) &&
+		equality.Semantic.DeepEqual(found.Spec, quota.Spec) && equality.Semantic.DeepEqual(found.Labels, quota.Labels) {
+		return nil
+	}
+	found.OwnerReferences = quota.OwnerReferences
+	found.Labels = quota.Labels
+	found.Spec = quota.Spec
+	err = r.Update(ctx, found)
+	r.recordEvent(paas, found, eventActionUpdate, err)
+	return err
+}
+
+// deleteObsoleteResourceQuotas deletes all ResourceQuotas of a Paas which are not desired
+func (r *PaasReconciler) deleteObsoleteResourceQuotas(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	desired []*corev1.ResourceQuota,
+) error {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerClusterQuotaComponent)
+	var existing corev1.ResourceQuotaList
+	if err := r.List(ctx, &existing, client.MatchingLabels{ManagedByLabelKey: paas.Name}); err != nil {
+		return err
+	}
+	for _, quota := range existing.Items {
+		if !paas.AmIOwner(quota.OwnerReferences) || slices.ContainsFunc(desired, func(d *corev1.ResourceQuota) bool {
+			return d.Namespace == quota.Namespace && d.Name == quota.Name
+		}) {
+			continue
+		}
+		err := r.Delete(ctx, &quota)
+		r.recordEvent(paas, &quota, eventActionDelete, err)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+		logger.Info().Msgf("deleted obsolete quota %s/%s", quota.Namespace, quota.Name)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	quotav1 "github.com/openshift/api/quota/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("ResourceQuota quota backend", Ordered, func() {
+	const (
+		paasName = "rq-paas"
+		capName  = "argocd"
+	)
+	var (
+		ctx        context.Context
+		paas       *v1alpha2.Paas
+		reconciler *PaasReconciler
+		myConfig   v1alpha2.PaasConfig
+		appNs      = join(paasName, "app")
+		dbNs       = join(paasName, "db")
+		capNs      = join(paasName, capName)
+		capQuota   = join(paasName, capName)
+	)
+
+	reconcileWith := func(backend v1alpha2.ConfigQuotaBackend) {
+		myConfig.Spec.QuotaBackend = backend
+		ctx = context.WithValue(context.Background(), config.ContextKeyPaasConfig, myConfig)
+		nsDefs, err := reconciler.nsDefsFromPaas(ctx, paas)
+		Expect(err).NotTo(HaveOccurred())
+		quotas, err := reconciler.quotaBackend(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quotas.reconcileNamespaceQuotas(ctx, paas, nsDefs)).To(Succeed())
+	}
+	reconcileClusterQuotasWith := func(backend v1alpha2.ConfigQuotaBackend) {
+		myConfig.Spec.QuotaBackend = backend
+		ctx = context.WithValue(context.Background(), config.ContextKeyPaasConfig, myConfig)
+		quotas, err := reconciler.quotaBackend(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quotas.reconcileQuotas(ctx, paas)).To(Succeed())
+		Expect(quotas.reconcileClusterWideQuota(ctx, paas)).To(Succeed())
+	}
+	getClusterResourceQuota := func(name string) (*quotav1.ClusterResourceQuota, error) {
+		quota := &quotav1.ClusterResourceQuota{}
+		return quota, reconciler.Get(ctx, types.NamespacedName{Name: name}, quota)
+	}
+	getResourceQuota := func(namespace string, name string) (*corev1.ResourceQuota, error) {
+		quota := &corev1.ResourceQuota{}
+		return quota, reconciler.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, quota)
+	}
+	expectHard := func(namespace string, name string, cpu string) {
+		quota, err := getResourceQuota(namespace, name)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(paas.AmIOwner(quota.OwnerReferences)).To(BeTrue())
+		Expect(quota.Labels).To(HaveKeyWithValue(ManagedByLabelKey, paasName))
+		Expect(quota.Spec.Hard).To(HaveKey(corev1.ResourceLimitsCPU))
+		Expect(quota.Spec.Hard.Name(corev1.ResourceLimitsCPU, resourcev1.DecimalSI).Cmp(
+			resourcev1.MustParse(cpu))).To(BeZero())
+	}
+
+	BeforeAll(func() {
+		ctx = context.Background()
+		reconciler = &PaasReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+		myConfig = v1alpha2.PaasConfig{
+			Spec: v1alpha2.PaasConfigSpec{
+				Capabilities: v1alpha2.ConfigCapabilities{capName: v1alpha2.ConfigCapability{
+					QuotaSettings: v1alpha2.ConfigQuotaSettings{
+						DefQuota: map[corev1.ResourceName]resourcev1.Quantity{
+							corev1.ResourceLimitsCPU: resourcev1.MustParse("1"),
+						},
+					},
+				}},
+			},
+		}
+		paas = &v1alpha2.Paas{
+			ObjectMeta: metav1.ObjectMeta{Name: paasName},
+			Spec: v1alpha2.PaasSpec{
+				Requestor:    paasName,
+				Capabilities: v1alpha2.PaasCapabilities{capName: v1alpha2.PaasCapability{}},
+				Namespaces:   v1alpha2.PaasNamespaces{"app": v1alpha2.PaasNamespace{}, "db": v1alpha2.PaasNamespace{}},
+				Quota: map[corev1.ResourceName]resourcev1.Quantity{
+					corev1.ResourceLimitsCPU: resourcev1.MustParse("3"),
+				},
+			},
+		}
+		assurePaas(ctx, *paas)
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: paasName}, paas)).To(Succeed())
+		for _, ns := range []string{appNs, dbNs, capNs} {
+			assureNamespace(ctx, ns)
+		}
+	})
+
+	It("divides quotas evenly over the namespaces that use them", func() {
+		reconcileWith(v1alpha2.ConfigQuotaBackend{Type: v1alpha2.ConfigQuotaBackendResourceQuota})
+		expectHard(appNs, paasName, "1500m")
+		expectHard(dbNs, paasName, "1500m")
+		expectHard(capNs, capQuota, "1")
+		_, err := getResourceQuota(capNs, paasName)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("replicates quotas to the namespaces that use them", func() {
+		reconcileWith(v1alpha2.ConfigQuotaBackend{
+			Type:         v1alpha2.ConfigQuotaBackendResourceQuota,
+			Distribution: v1alpha2.ConfigQuotaDistributionReplicate,
+		})
+		expectHard(appNs, paasName, "3")
+		expectHard(dbNs, paasName, "3")
+		expectHard(capNs, capQuota, "1")
+	})
+
+	It("deletes ResourceQuotas of namespaces which no longer use the quota", func() {
+		delete(paas.Spec.Namespaces, "db")
+		reconcileWith(v1alpha2.ConfigQuotaBackend{Type: v1alpha2.ConfigQuotaBackendResourceQuota})
+		expectHard(appNs, paasName, "3")
+		_, err := getResourceQuota(dbNs, paasName)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("deletes all ResourceQuotas when switching to the ClusterResourceQuota backend", func() {
+		reconcileWith(v1alpha2.ConfigQuotaBackend{})
+		for namespace, name := range map[string]string{appNs: paasName, capNs: capQuota} {
+			_, err := getResourceQuota(namespace, name)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		}
+	})
+
+	It("deletes the ClusterResourceQuotas when switching to the ResourceQuota backend", func() {
+		capConfig := myConfig.Spec.Capabilities[capName]
+		capConfig.QuotaSettings.Clusterwide = true
+		myConfig.Spec.Capabilities[capName] = capConfig
+		reconcileClusterQuotasWith(v1alpha2.ConfigQuotaBackend{})
+		for _, name := range []string{paasName, clusterWideQuotaName(capName)} {
+			quota, err := getClusterResourceQuota(name)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(paas.AmIOwner(quota.OwnerReferences)).To(BeTrue())
+		}
+
+		notOwned := &quotav1.ClusterResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: capQuota}}
+		Expect(k8sClient.Create(ctx, notOwned)).To(Succeed())
+		reconcileClusterQuotasWith(v1alpha2.ConfigQuotaBackend{Type: v1alpha2.ConfigQuotaBackendResourceQuota})
+		for _, name := range []string{paasName, clusterWideQuotaName(capName)} {
+			_, err := getClusterResourceQuota(name)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		}
+		_, err := getClusterResourceQuota(capQuota)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
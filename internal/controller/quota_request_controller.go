@@ -0,0 +1,283 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/logging"
+	paasquota "github.com/belastingdienst/opr-paas/v5/pkg/quota"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// QuotaRequestReconciler applies approved QuotaRequests to the quota of their Paas
+type QuotaRequestReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder events.EventRecorder
+	// WebhooksEnabled should only be set when the validating webhook for QuotaRequests is enabled. Only the webhook
+	// verifies that a decision is made by an approver, so decisions are not processed without it.
+	WebhooksEnabled bool
+}
+
+// errDecisionNotVerified is reported on QuotaRequests with a decision, when the decision cannot be verified
+var errDecisionNotVerified = errors.New(
+	"decisions on quota requests are only processed when the validating webhooks are enabled",
+)
+
+//revive:disable:line-length-limit
+// +kubebuilder:rbac:groups=cpet.belastingdienst.nl,resources=quotarequests,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cpet.belastingdienst.nl,resources=quotarequests/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cpet.belastingdienst.nl,resources=paas,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=cpet.belastingdienst.nl,resources=paas/status,verbs=get;update;patch
+//revive:enable:line-length-limit
+
+// SetupWithManager sets up the controller with the Manager.
+func (qrr *QuotaRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("quotarequest").
+		For(&v1alpha2.QuotaRequest{}, builder.WithPredicates(
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}),
+		)).
+		Complete(qrr)
+}
+
+// Reconcile processes the decision of an approver on a QuotaRequest
+func (qrr *QuotaRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerQuotaRequestComponent)
+	quotaRequest := &v1alpha2.QuotaRequest{}
+	if err := qrr.Get(ctx, req.NamespacedName, quotaRequest); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if err := qrr.reconcileQuotaRequest(ctx, quotaRequest); err != nil {
+		logger.Err(err).Msg("failed to reconcile quota request")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileQuotaRequest sets the phase of a QuotaRequest from the decision of the approver, and applies the
+// QuotaRequest to the quota of the Paas when it is approved. Decided QuotaRequests are left alone.
+func (qrr *QuotaRequestReconciler) reconcileQuotaRequest(
+	ctx context.Context,
+	quotaRequest *v1alpha2.QuotaRequest,
+) (err error) {
+	if quotaRequest.IsDecided() {
+		return nil
+	}
+	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerQuotaRequestComponent)
+	var ns corev1.Namespace
+	if err = qrr.Get(ctx, types.NamespacedName{Name: quotaRequest.Namespace}, &ns); err != nil {
+		return err
+	}
+	paasName, err := paasFromNs(ns)
+	if err != nil {
+		return qrr.setQuotaRequestCondition(ctx, quotaRequest, err)
+	}
+	quotaRequest.Status.Paas = paasName
+	if quotaRequest.Decision() != "" && !qrr.WebhooksEnabled {
+		// Requeueing does not help, so the error is only reported in the status of the QuotaRequest
+		logger.Warn().Msg(errDecisionNotVerified.Error())
+		quotaRequest.Status.Phase = v1alpha2.QuotaRequestPending
+		err = qrr.setQuotaRequestCondition(ctx, quotaRequest, errDecisionNotVerified)
+		if errors.Is(err, errDecisionNotVerified) {
+			return nil
+		}
+		return err
+	}
+
+	switch quotaRequest.Decision() {
+	case v1alpha2.QuotaRequestApproved:
+		if err = qrr.applyQuotaRequest(ctx, paasName, quotaRequest); err != nil {
+			return qrr.setQuotaRequestCondition(ctx, quotaRequest, err)
+		}
+		logger.Info().Msgf("applied quota request to paas %s", paasName)
+		quotaRequest.Status.Phase = v1alpha2.QuotaRequestApplied
+	case v1alpha2.QuotaRequestRejected:
+		quotaRequest.Status.Phase = v1alpha2.QuotaRequestRejectedPhase
+	default:
+		quotaRequest.Status.Phase = v1alpha2.QuotaRequestPending
+		return qrr.setQuotaRequestCondition(ctx, quotaRequest, nil)
+	}
+	now := metav1.Now()
+	quotaRequest.Status.DecidedBy = quotaRequest.DecidedBy()
+	quotaRequest.Status.DecidedAt = &now
+	return qrr.setQuotaRequestCondition(ctx, quotaRequest, nil)
+}
+
+// applyQuotaRequest merges the requested quota into the quota of the Paas (or capability), and records the approval
+// in the status of the Paas. Since the spec and the status of the Paas are updated separately, the approval is also
+// stored in an annotation on the Paas, in the same update which changes the quota. When recording the approval in the
+// status fails, the approval is recorded from the annotation on the next attempt, instead of applying it again.
+func (qrr *QuotaRequestReconciler) applyQuotaRequest(
+	ctx context.Context,
+	paasName string,
+	quotaRequest *v1alpha2.QuotaRequest,
+) error {
+	paas := &v1alpha2.Paas{}
+	if err := qrr.Get(ctx, types.NamespacedName{Name: paasName}, paas); err != nil {
+		return err
+	}
+	annotation := v1alpha2.QuotaApprovalAnnotationPrefix + string(quotaRequest.UID)
+	idx := slices.IndexFunc(paas.Status.QuotaApprovals, func(approval v1alpha2.PaasQuotaApproval) bool {
+		return approval.UID == quotaRequest.UID
+	})
+	var approval v1alpha2.PaasQuotaApproval
+	if idx >= 0 {
+		approval = paas.Status.QuotaApprovals[idx]
+	} else if value, exists := paas.Annotations[annotation]; exists {
+		if err := json.Unmarshal([]byte(value), &approval); err != nil {
+			return fmt.Errorf("invalid annotation %s on paas %s: %w", annotation, paasName, err)
+		}
+	} else {
+		var err error
+		if approval, err = qrr.updatePaasQuota(ctx, paas, annotation, quotaRequest); err != nil {
+			return err
+		}
+	}
+	if err := qrr.recordQuotaApproval(ctx, paasName, annotation, approval); err != nil {
+		return err
+	}
+	quotaRequest.Status.PreviousQuota = approval.Previous
+	return nil
+}
+
+// updatePaasQuota updates the quota of the Paas (or capability) with the requested quota, and stores the approval in
+// annotation in the same update
+func (qrr *QuotaRequestReconciler) updatePaasQuota(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	annotation string,
+	quotaRequest *v1alpha2.QuotaRequest,
+) (v1alpha2.PaasQuotaApproval, error) {
+	capName := quotaRequest.Spec.Capability
+	current := paas.Spec.Quota
+	if capName != "" {
+		capability, exists := paas.Spec.Capabilities[capName]
+		if !exists {
+			return v1alpha2.PaasQuotaApproval{}, fmt.Errorf("capability %s is not enabled in paas %s", capName,
+				paas.Name)
+		}
+		current = capability.Quota
+	}
+	previous := make(paasquota.Quota)
+	for resource := range quotaRequest.Spec.Quota {
+		if value, exists := current[resource]; exists {
+			previous[resource] = value
+		}
+	}
+	// MergeWith keeps the values of the receiver, so the requested quota takes precedence
+	approved := quotaRequest.Spec.Quota.MergeWith(current)
+	if capName == "" {
+		paas.Spec.Quota = approved
+	} else {
+		capability := paas.Spec.Capabilities[capName]
+		capability.Quota = approved
+		paas.Spec.Capabilities[capName] = capability
+	}
+	approval := v1alpha2.PaasQuotaApproval{
+		QuotaRequest: v1alpha2.NamespacedName{Namespace: quotaRequest.Namespace, Name: quotaRequest.Name},
+		UID:          quotaRequest.UID,
+		Capability:   capName,
+		Previous:     previous,
+		Approved:     quotaRequest.Spec.Quota,
+		ApprovedBy:   quotaRequest.DecidedBy(),
+		ApprovedAt:   metav1.NewTime(time.Now()),
+	}
+	value, err := json.Marshal(approval)
+	if err != nil {
+		return approval, err
+	}
+	if paas.Annotations == nil {
+		paas.Annotations = map[string]string{}
+	}
+	paas.Annotations[annotation] = string(value)
+	err = qrr.Update(ctx, paas)
+	recordEvent(qrr.Recorder, qrr.Scheme, quotaRequest, paas, eventActionUpdate, err)
+	return approval, err
+}
+
+// recordQuotaApproval records an approval in the status of the Paas (when it is not yet recorded), after which the
+// annotation holding the approval is removed from the Paas
+func (qrr *QuotaRequestReconciler) recordQuotaApproval(
+	ctx context.Context,
+	paasName string,
+	annotation string,
+	approval v1alpha2.PaasQuotaApproval,
+) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		paas := &v1alpha2.Paas{}
+		if err := qrr.Get(ctx, types.NamespacedName{Name: paasName}, paas); err != nil {
+			return err
+		}
+		if paas.Status.IsQuotaRequestApplied(approval.UID) {
+			return nil
+		}
+		paas.Status.QuotaApprovals = append(paas.Status.QuotaApprovals, approval)
+		return qrr.Status().Update(ctx, paas)
+	})
+	if err != nil {
+		return err
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		paas := &v1alpha2.Paas{}
+		if err = qrr.Get(ctx, types.NamespacedName{Name: paasName}, paas); err != nil {
+			return err
+		}
+		if _, exists := paas.Annotations[annotation]; !exists {
+			return nil
+		}
+		delete(paas.Annotations, annotation)
+		return qrr.Update(ctx, paas)
+	})
+}
+
+// setQuotaRequestCondition sets the Ready condition of a QuotaRequest, which is False when err is set, and updates
+// the status of the QuotaRequest
+func (qrr *QuotaRequestReconciler) setQuotaRequestCondition(
+	ctx context.Context,
+	quotaRequest *v1alpha2.QuotaRequest,
+	err error,
+) error {
+	condition := metav1.Condition{
+		Type:   v1alpha2.TypeReadyQuotaRequest,
+		Status: metav1.ConditionTrue, Reason: string(quotaRequest.Status.Phase),
+		ObservedGeneration: quotaRequest.Generation,
+		Message:            fmt.Sprintf("QuotaRequest is %s", quotaRequest.Status.Phase),
+	}
+	if quotaRequest.Status.Phase == v1alpha2.QuotaRequestPending {
+		condition.Status = metav1.ConditionFalse
+		condition.Message = "QuotaRequest awaits a decision of an approver"
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ReconcilingError"
+		condition.Message = err.Error()
+	}
+	meta.SetStatusCondition(&quotaRequest.Status.Conditions, condition)
+	if updateErr := qrr.Status().Update(ctx, quotaRequest); updateErr != nil && !k8serrors.IsNotFound(updateErr) {
+		return updateErr
+	}
+	return err
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	paasquota "github.com/belastingdienst/opr-paas/v5/pkg/quota"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("QuotaRequest controller", Ordered, func() {
+	const (
+		paasName = "quota-request-paas"
+		capName  = "argocd"
+		approver = "jane"
+	)
+	var (
+		ctx        = context.Background()
+		reconciler *QuotaRequestReconciler
+		appNs      = join(paasName, "app")
+	)
+	cpu := func(quantity string) paasquota.Quota {
+		return paasquota.Quota{corev1.ResourceLimitsCPU: resourcev1.MustParse(quantity)}
+	}
+	expectCPU := func(quota paasquota.Quota, quantity string) {
+		Expect(quota).To(HaveKey(corev1.ResourceLimitsCPU))
+		value := quota[corev1.ResourceLimitsCPU]
+		Expect(value.Cmp(resourcev1.MustParse(quantity))).To(BeZero())
+	}
+	createQuotaRequest := func(name string, capability string, quota paasquota.Quota) *v1alpha2.QuotaRequest {
+		quotaRequest := &v1alpha2.QuotaRequest{
+			// The UID is set by the API server, but not by every test client
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: appNs, UID: types.UID(name + "-uid")},
+			Spec:       v1alpha2.QuotaRequestSpec{Capability: capability, Quota: quota},
+		}
+		Expect(k8sClient.Create(ctx, quotaRequest)).To(Succeed())
+		return quotaRequest
+	}
+	decide := func(quotaRequest *v1alpha2.QuotaRequest, decision v1alpha2.QuotaRequestDecision) {
+		quotaRequest.Annotations = map[string]string{
+			v1alpha2.QuotaRequestDecisionAnnotation:  string(decision),
+			v1alpha2.QuotaRequestDecidedByAnnotation: approver,
+		}
+		Expect(k8sClient.Update(ctx, quotaRequest)).To(Succeed())
+	}
+	reconcile := func(quotaRequest *v1alpha2.QuotaRequest) *v1alpha2.QuotaRequest {
+		request := ctrl.Request{NamespacedName: types.NamespacedName{
+			Namespace: quotaRequest.Namespace,
+			Name:      quotaRequest.Name,
+		}}
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		reconciled := &v1alpha2.QuotaRequest{}
+		Expect(k8sClient.Get(ctx, request.NamespacedName, reconciled)).To(Succeed())
+		return reconciled
+	}
+
+	BeforeAll(func() {
+		reconciler = &QuotaRequestReconciler{
+			Client:          k8sClient,
+			Scheme:          k8sClient.Scheme(),
+			WebhooksEnabled: true,
+		}
+		assurePaas(ctx, v1alpha2.Paas{
+			ObjectMeta: metav1.ObjectMeta{Name: paasName},
+			Spec: v1alpha2.PaasSpec{
+				Requestor: paasName,
+				Capabilities: v1alpha2.PaasCapabilities{
+					capName: v1alpha2.PaasCapability{Quota: cpu("1")},
+				},
+				Quota: cpu("1"),
+			},
+		})
+		assureNamespaceWithPaasReference(ctx, appNs, paasName)
+	})
+
+	It("keeps a QuotaRequest pending until it is decided", func() {
+		quotaRequest := reconcile(createQuotaRequest("pending", "", cpu("2")))
+		Expect(quotaRequest.Status.Phase).To(Equal(v1alpha2.QuotaRequestPending))
+		Expect(quotaRequest.Status.Paas).To(Equal(paasName))
+		Expect(meta.IsStatusConditionFalse(quotaRequest.Status.Conditions, v1alpha2.TypeReadyQuotaRequest)).
+			To(BeTrue())
+		expectCPU(getPaas(ctx, paasName).Spec.Quota, "1")
+	})
+
+	It("applies an approved QuotaRequest to the quota of the Paas", func() {
+		quotaRequest := createQuotaRequest("approved", "", cpu("4"))
+		decide(quotaRequest, v1alpha2.QuotaRequestApproved)
+		quotaRequest = reconcile(quotaRequest)
+		Expect(quotaRequest.Status.Phase).To(Equal(v1alpha2.QuotaRequestApplied))
+		Expect(quotaRequest.Status.DecidedBy).To(Equal(approver))
+		Expect(quotaRequest.Status.DecidedAt).NotTo(BeNil())
+		expectCPU(quotaRequest.Status.PreviousQuota, "1")
+		Expect(meta.IsStatusConditionTrue(quotaRequest.Status.Conditions, v1alpha2.TypeReadyQuotaRequest)).
+			To(BeTrue())
+
+		paas := getPaas(ctx, paasName)
+		expectCPU(paas.Spec.Quota, "4")
+		Expect(paas.Status.QuotaApprovals).To(HaveLen(1))
+		approval := paas.Status.QuotaApprovals[0]
+		Expect(approval.QuotaRequest).To(Equal(v1alpha2.NamespacedName{Namespace: appNs, Name: "approved"}))
+		Expect(approval.UID).To(Equal(quotaRequest.UID))
+		Expect(approval.ApprovedBy).To(Equal(approver))
+		expectCPU(approval.Previous, "1")
+		expectCPU(approval.Approved, "4")
+		Expect(paas.Annotations).NotTo(HaveKey(v1alpha2.QuotaApprovalAnnotationPrefix + string(quotaRequest.UID)))
+	})
+
+	It("does not apply an approved QuotaRequest twice", func() {
+		quotaRequest := &v1alpha2.QuotaRequest{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: appNs, Name: "approved"}, quotaRequest)).
+			To(Succeed())
+		paas := getPaas(ctx, paasName)
+		paas.Spec.Quota = cpu("3")
+		Expect(k8sClient.Update(ctx, paas)).To(Succeed())
+		Expect(reconciler.applyQuotaRequest(ctx, paasName, quotaRequest)).To(Succeed())
+		paas = getPaas(ctx, paasName)
+		expectCPU(paas.Spec.Quota, "3")
+		Expect(paas.Status.QuotaApprovals).To(HaveLen(1))
+	})
+
+	It("applies an approved QuotaRequest to the quota of a capability", func() {
+		quotaRequest := createQuotaRequest("capability", capName, cpu("2"))
+		decide(quotaRequest, v1alpha2.QuotaRequestApproved)
+		quotaRequest = reconcile(quotaRequest)
+		Expect(quotaRequest.Status.Phase).To(Equal(v1alpha2.QuotaRequestApplied))
+		paas := getPaas(ctx, paasName)
+		expectCPU(paas.Spec.Capabilities[capName].Quota, "2")
+		Expect(paas.Status.QuotaApprovals).To(HaveLen(2))
+		Expect(paas.Status.QuotaApprovals[1].Capability).To(Equal(capName))
+	})
+
+	It("does not apply a rejected QuotaRequest", func() {
+		quotaRequest := createQuotaRequest("rejected", "", cpu("8"))
+		decide(quotaRequest, v1alpha2.QuotaRequestRejected)
+		quotaRequest = reconcile(quotaRequest)
+		Expect(quotaRequest.Status.Phase).To(Equal(v1alpha2.QuotaRequestRejectedPhase))
+		Expect(quotaRequest.Status.DecidedBy).To(Equal(approver))
+		expectCPU(getPaas(ctx, paasName).Spec.Quota, "3")
+	})
+
+	It("records an approval which was applied, but not yet recorded, without applying it again", func() {
+		quotaRequest := createQuotaRequest("partially-applied", "", cpu("5"))
+		decide(quotaRequest, v1alpha2.QuotaRequestApproved)
+		annotation := v1alpha2.QuotaApprovalAnnotationPrefix + string(quotaRequest.UID)
+		approval, err := json.Marshal(v1alpha2.PaasQuotaApproval{
+			QuotaRequest: v1alpha2.NamespacedName{Namespace: appNs, Name: quotaRequest.Name},
+			UID:          quotaRequest.UID,
+			Previous:     cpu("3"),
+			Approved:     cpu("5"),
+			ApprovedBy:   approver,
+			ApprovedAt:   metav1.Now(),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		// The quota was updated along with the annotation, after which updating the status failed
+		paas := getPaas(ctx, paasName)
+		paas.Spec.Quota = cpu("5")
+		paas.Annotations = map[string]string{annotation: string(approval)}
+		Expect(k8sClient.Update(ctx, paas)).To(Succeed())
+		paas = getPaas(ctx, paasName)
+		paas.Spec.Quota = cpu("6")
+		Expect(k8sClient.Update(ctx, paas)).To(Succeed())
+
+		quotaRequest = reconcile(quotaRequest)
+		Expect(quotaRequest.Status.Phase).To(Equal(v1alpha2.QuotaRequestApplied))
+		expectCPU(quotaRequest.Status.PreviousQuota, "3")
+		paas = getPaas(ctx, paasName)
+		expectCPU(paas.Spec.Quota, "6")
+		Expect(paas.Annotations).NotTo(HaveKey(annotation))
+		Expect(paas.Status.IsQuotaRequestApplied(quotaRequest.UID)).To(BeTrue())
+	})
+
+	It("does not process decisions when the webhooks are disabled", func() {
+		reconciler.WebhooksEnabled = false
+		DeferCleanup(func() { reconciler.WebhooksEnabled = true })
+		quotaRequest := createQuotaRequest("unverified", "", cpu("8"))
+		decide(quotaRequest, v1alpha2.QuotaRequestApproved)
+		quotaRequest = reconcile(quotaRequest)
+		Expect(quotaRequest.Status.Phase).To(Equal(v1alpha2.QuotaRequestPending))
+		Expect(quotaRequest.Status.DecidedBy).To(BeEmpty())
+		condition := meta.FindStatusCondition(quotaRequest.Status.Conditions, v1alpha2.TypeReadyQuotaRequest)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(Equal(errDecisionNotVerified.Error()))
+		expectCPU(getPaas(ctx, paasName).Spec.Quota, "6")
+	})
+
+	It("reports an error for a capability which is not enabled", func() {
+		quotaRequest := createQuotaRequest("unknown-capability", "tekton", cpu("2"))
+		decide(quotaRequest, v1alpha2.QuotaRequestApproved)
+		request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: appNs, Name: quotaRequest.Name}}
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).To(MatchError("capability tekton is not enabled in paas " + paasName))
+		Expect(k8sClient.Get(ctx, request.NamespacedName, quotaRequest)).To(Succeed())
+		condition := meta.FindStatusCondition(quotaRequest.Status.Conditions, v1alpha2.TypeReadyQuotaRequest)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("ReconcilingError"))
+	})
+})
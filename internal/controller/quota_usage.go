@@ -0,0 +1,188 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	paasquota "github.com/belastingdienst/opr-paas/v5/pkg/quota"
+	quotav1 "github.com/openshift/api/quota/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	quotaPressureReason   = "QuotaPressure"
+	noQuotaPressureReason = "QuotaAvailable"
+)
+
+// quotaUsage returns the usage of the ClusterResourceQuotas of a Paas, including the cluster-wide quotas it
+// contributes to. Quotas which do not exist (yet) are skipped.
+func (r *PaasReconciler) quotaUsage(ctx context.Context, paas *v1alpha2.Paas) ([]v1alpha2.PaasQuotaUsage, error) {
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nsDefs, err := r.nsDefsFromPaas(ctx, paas)
+	if err != nil {
+		return nil, err
+	}
+	var desired []v1alpha2.PaasQuotaUsage
+	if len(paas.Spec.Quota) > 0 {
+		desired = append(desired, v1alpha2.PaasQuotaUsage{Name: paas.Name})
+	}
+	for _, capName := range slices.Sorted(maps.Keys(paas.Spec.Capabilities)) {
+		capConfig, exists := myConfig.Spec.Capabilities[capName]
+		switch {
+		case !exists:
+			continue
+		case capConfig.QuotaSettings.Clusterwide:
+			contribution := paas.Spec.Capabilities[capName].Quotas().MergeWith(capConfig.QuotaSettings.DefQuota)
+			desired = append(desired, v1alpha2.PaasQuotaUsage{
+				Name:         clusterWideQuotaName(capName),
+				Capability:   capName,
+				ClusterWide:  true,
+				Contribution: corev1.ResourceList(contribution),
+			})
+		case !capConfig.QuotaSettings.External():
+			desired = append(desired, v1alpha2.PaasQuotaUsage{Name: join(paas.Name, capName), Capability: capName})
+		}
+	}
+
+	var usage []v1alpha2.PaasQuotaUsage
+	for _, quotaUsage := range desired {
+		quota := &quotav1.ClusterResourceQuota{}
+		if err = r.Get(ctx, types.NamespacedName{Name: quotaUsage.Name}, quota); k8serrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		usage = append(usage, clusterQuotaUsage(quotaUsage, quota, nsDefs))
+	}
+	return usage, nil
+}
+
+// clusterQuotaUsage completes the usage of a quota of a Paas from the status of its ClusterResourceQuota. For a
+// cluster-wide quota, the usage in the namespaces of the Paas is summed as well.
+func clusterQuotaUsage(
+	quotaUsage v1alpha2.PaasQuotaUsage,
+	quota *quotav1.ClusterResourceQuota,
+	nsDefs namespaceDefs,
+) v1alpha2.PaasQuotaUsage {
+	quotaUsage.Hard = quota.Spec.Quota.Hard
+	quotaUsage.Used = quota.Status.Total.Used
+	if !quotaUsage.ClusterWide {
+		return quotaUsage
+	}
+	usedByPaas := paasquota.NewQuotas()
+	for _, nsStatus := range quota.Status.Namespaces {
+		if _, exists := nsDefs[nsStatus.Namespace]; exists {
+			usedByPaas.Append(paasquota.Quota(nsStatus.Status.Used))
+		}
+	}
+	if sum := usedByPaas.Sum(); len(sum) > 0 {
+		quotaUsage.UsedByPaas = corev1.ResourceList(sum)
+	}
+	return quotaUsage
+}
+
+// quotaUsage returns the usage of the ResourceQuotas in the namespaces of a Paas
+func (b resourceQuotaBackend) quotaUsage(ctx context.Context, paas *v1alpha2.Paas) ([]v1alpha2.PaasQuotaUsage, error) {
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var quotas corev1.ResourceQuotaList
+	if err = b.r.List(ctx, &quotas, client.MatchingLabels{ManagedByLabelKey: paas.Name}); err != nil {
+		return nil, err
+	}
+	var usage []v1alpha2.PaasQuotaUsage
+	for _, quota := range quotas.Items {
+		if !paas.AmIOwner(quota.OwnerReferences) {
+			continue
+		}
+		var capName string
+		if suffix, found := strings.CutPrefix(quota.Name, paas.Name+"-"); found {
+			if _, exists := myConfig.Spec.Capabilities[suffix]; exists {
+				capName = suffix
+			}
+		}
+		usage = append(usage, v1alpha2.PaasQuotaUsage{
+			Name:       quota.Name,
+			Namespace:  quota.Namespace,
+			Capability: capName,
+			Hard:       quota.Spec.Hard,
+			Used:       quota.Status.Used,
+		})
+	}
+	slices.SortFunc(usage, func(a, b v1alpha2.PaasQuotaUsage) int {
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+	return usage, nil
+}
+
+// setQuotaPressureCondition sets the QuotaPressure condition of a Paas from its quota usage, and emits a Warning
+// event when quota pressure is raised or changes. The condition is removed when no pressure threshold is configured.
+func (r *PaasReconciler) setQuotaPressureCondition(ctx context.Context, paas *v1alpha2.Paas) error {
+	myConfig, err := config.GetConfigFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	threshold := myConfig.Spec.QuotaUsage.PressureThreshold
+	if threshold == 0 {
+		meta.RemoveStatusCondition(&paas.Status.Conditions, v1alpha2.TypeQuotaPressurePaas)
+		return nil
+	}
+	var pressures []string
+	for _, quotaUsage := range paas.Status.QuotaUsage {
+		resources := quotaUsage.Pressure(threshold)
+		if len(resources) == 0 {
+			continue
+		}
+		name := quotaUsage.Name
+		if quotaUsage.Namespace != "" {
+			name = quotaUsage.Namespace + "/" + name
+		}
+		pressures = append(pressures, fmt.Sprintf("%s (%s)", name, joinResourceNames(resources)))
+	}
+	if len(pressures) == 0 {
+		meta.SetStatusCondition(&paas.Status.Conditions, metav1.Condition{
+			Type:   v1alpha2.TypeQuotaPressurePaas,
+			Status: metav1.ConditionFalse, Reason: noQuotaPressureReason, ObservedGeneration: paas.Generation,
+			Message: fmt.Sprintf("less than %d%% of all quotas is used", threshold),
+		})
+		return nil
+	}
+	message := fmt.Sprintf("%d%% or more is used of quotas %s", threshold, strings.Join(pressures, ", "))
+	changed := meta.SetStatusCondition(&paas.Status.Conditions, metav1.Condition{
+		Type:   v1alpha2.TypeQuotaPressurePaas,
+		Status: metav1.ConditionTrue, Reason: quotaPressureReason, ObservedGeneration: paas.Generation,
+		Message: message,
+	})
+	if changed && r.Recorder != nil {
+		r.Recorder.Eventf(paas, nil, corev1.EventTypeWarning, quotaPressureReason, "Reconcile", "%s", message)
+	}
+	return nil
+}
+
+func joinResourceNames(resources []corev1.ResourceName) string {
+	names := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		names = append(names, string(resource))
+	}
+	return strings.Join(names, ", ")
+}
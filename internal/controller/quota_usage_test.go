@@ -0,0 +1,147 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	paasquota "github.com/belastingdienst/opr-paas/v5/pkg/quota"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	quotav1 "github.com/openshift/api/quota/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Quota usage", Ordered, func() {
+	const (
+		paasName = "quota-usage-paas"
+		capName  = "argocd"
+		cwqCap   = "tekton"
+	)
+	var (
+		ctx        context.Context
+		paas       *v1alpha2.Paas
+		reconciler *PaasReconciler
+		myConfig   v1alpha2.PaasConfig
+		appNs      = join(paasName, "app")
+	)
+	cpu := func(quantity string) corev1.ResourceList {
+		return corev1.ResourceList{corev1.ResourceLimitsCPU: resourcev1.MustParse(quantity)}
+	}
+	expectCPU := func(resources corev1.ResourceList, quantity string) {
+		Expect(resources).To(HaveKey(corev1.ResourceLimitsCPU))
+		Expect(resources.Name(corev1.ResourceLimitsCPU, resourcev1.DecimalSI).Cmp(
+			resourcev1.MustParse(quantity))).To(BeZero())
+	}
+
+	BeforeAll(func() {
+		reconciler = &PaasReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+		myConfig = v1alpha2.PaasConfig{
+			Spec: v1alpha2.PaasConfigSpec{
+				Capabilities: v1alpha2.ConfigCapabilities{
+					capName: v1alpha2.ConfigCapability{
+						QuotaSettings: v1alpha2.ConfigQuotaSettings{DefQuota: paasquota.Quota(cpu("1"))},
+					},
+					cwqCap: v1alpha2.ConfigCapability{
+						QuotaSettings: v1alpha2.ConfigQuotaSettings{
+							Clusterwide: true,
+							DefQuota:    paasquota.Quota(cpu("2")),
+							MinQuotas:   paasquota.Quota(cpu("4")),
+						},
+					},
+				},
+				QuotaUsage: v1alpha2.ConfigQuotaUsage{PressureThreshold: 80},
+			},
+		}
+		ctx = context.WithValue(context.Background(), config.ContextKeyPaasConfig, myConfig)
+		paas = &v1alpha2.Paas{
+			ObjectMeta: metav1.ObjectMeta{Name: paasName},
+			Spec: v1alpha2.PaasSpec{
+				Requestor: paasName,
+				Capabilities: v1alpha2.PaasCapabilities{
+					capName: v1alpha2.PaasCapability{},
+					cwqCap:  v1alpha2.PaasCapability{},
+				},
+				Namespaces: v1alpha2.PaasNamespaces{"app": v1alpha2.PaasNamespace{}},
+				Quota:      paasquota.Quota(cpu("3")),
+			},
+		}
+		assurePaas(ctx, *paas)
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: paasName}, paas)).To(Succeed())
+		assureNamespace(ctx, appNs)
+	})
+
+	It("reports the quotas of a Paas, including the cluster-wide quotas it contributes to", func() {
+		Expect(reconciler.reconcileQuotas(ctx, paas)).To(Succeed())
+		Expect(reconciler.reconcileClusterWideQuota(ctx, paas)).To(Succeed())
+		usage, err := reconciler.quotaUsage(ctx, paas)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(usage).To(HaveLen(3))
+		Expect(usage[0].Name).To(Equal(paasName))
+		expectCPU(usage[0].Hard, "3")
+		Expect(usage[1].Name).To(Equal(join(paasName, capName)))
+		Expect(usage[1].Capability).To(Equal(capName))
+		expectCPU(usage[1].Hard, "1")
+		Expect(usage[2].Name).To(Equal(clusterWideQuotaName(cwqCap)))
+		Expect(usage[2].ClusterWide).To(BeTrue())
+		expectCPU(usage[2].Contribution, "2")
+	})
+
+	It("sums the usage of a cluster-wide quota in the namespaces of the Paas", func() {
+		quota := &quotav1.ClusterResourceQuota{
+			Spec: quotav1.ClusterResourceQuotaSpec{Quota: corev1.ResourceQuotaSpec{Hard: cpu("4")}},
+			Status: quotav1.ClusterResourceQuotaStatus{
+				Total: corev1.ResourceQuotaStatus{Used: cpu("3")},
+				Namespaces: quotav1.ResourceQuotasStatusByNamespace{
+					{Namespace: appNs, Status: corev1.ResourceQuotaStatus{Used: cpu("1")}},
+					{Namespace: join(paasName, cwqCap), Status: corev1.ResourceQuotaStatus{Used: cpu("500m")}},
+					{Namespace: "other-paas-tekton", Status: corev1.ResourceQuotaStatus{Used: cpu("1500m")}},
+				},
+			},
+		}
+		nsDefs, err := reconciler.nsDefsFromPaas(ctx, paas)
+		Expect(err).NotTo(HaveOccurred())
+		usage := clusterQuotaUsage(v1alpha2.PaasQuotaUsage{ClusterWide: true}, quota, nsDefs)
+		expectCPU(usage.Hard, "4")
+		expectCPU(usage.Used, "3")
+		expectCPU(usage.UsedByPaas, "1500m")
+	})
+
+	It("sets the QuotaPressure condition when usage reaches the threshold", func() {
+		paas.Status.QuotaUsage = []v1alpha2.PaasQuotaUsage{
+			{Name: paasName, Hard: cpu("10"), Used: cpu("5")},
+			{Name: join(paasName, capName), Hard: cpu("1"), Used: cpu("900m")},
+		}
+		Expect(reconciler.setQuotaPressureCondition(ctx, paas)).To(Succeed())
+		condition := meta.FindStatusCondition(paas.Status.Conditions, v1alpha2.TypeQuotaPressurePaas)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(Equal("80% or more is used of quotas quota-usage-paas-argocd (limits.cpu)"))
+	})
+
+	It("clears the QuotaPressure condition when usage drops below the threshold", func() {
+		paas.Status.QuotaUsage[1].Used = cpu("500m")
+		Expect(reconciler.setQuotaPressureCondition(ctx, paas)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(paas.Status.Conditions, v1alpha2.TypeQuotaPressurePaas)).To(BeTrue())
+	})
+
+	It("removes the QuotaPressure condition when no threshold is configured", func() {
+		myConfig.Spec.QuotaUsage = v1alpha2.ConfigQuotaUsage{}
+		noPressureCtx := context.WithValue(context.Background(), config.ContextKeyPaasConfig, myConfig)
+		Expect(reconciler.setQuotaPressureCondition(noPressureCtx, paas)).To(Succeed())
+		Expect(meta.FindStatusCondition(paas.Status.Conditions, v1alpha2.TypeQuotaPressurePaas)).To(BeNil())
+	})
+})
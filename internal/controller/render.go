@@ -0,0 +1,263 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	quotav1 "github.com/openshift/api/quota/v1"
+	userv1 "github.com/openshift/api/user/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// RenderedPaas holds all resources which the Paas controller would create for a Paas.
+// ClusterRoleBindings only hold the subjects which are added for this Paas.
+type RenderedPaas struct {
+	Namespaces            []*corev1.Namespace             `json:"namespaces"`
+	ClusterResourceQuotas []*quotav1.ClusterResourceQuota `json:"clusterResourceQuotas"`
+	ResourceQuotas        []*corev1.ResourceQuota         `json:"resourceQuotas"`
+	Groups                []*userv1.Group                 `json:"groups"`
+	RoleBindings          []*rbac.RoleBinding             `json:"roleBindings"`
+	ClusterRoleBindings   []*rbac.ClusterRoleBinding      `json:"clusterRoleBindings"`
+	NetworkPolicies       []*networkingv1.NetworkPolicy   `json:"networkPolicies"`
+	BaselineResources     []*unstructured.Unstructured    `json:"baselineResources"`
+}
+
+// Render returns all resources which the Paas controller would create for a Paas, without a connection to a cluster.
+// The backend functions of the controller are run against an in-memory fake client, which holds only the Paas, the
+// PaasNS's and the namespaces which are rendered for them. Render can therefore be used to test a PaasConfig
+// (e.a. templating and capability definitions) without a cluster.
+func Render(
+	ctx context.Context,
+	scheme *runtime.Scheme,
+	paas *v1alpha2.Paas,
+	paasConfig v1alpha2.PaasConfig,
+	paasNSs []v1alpha2.PaasNS,
+) (*RenderedPaas, error) {
+	objs := []client.Object{paas.DeepCopy()}
+	for _, paasns := range paasNSs {
+		objs = append(objs, paasns.DeepCopy())
+	}
+	r := &PaasReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Scheme: scheme,
+	}
+	ctx = context.WithValue(ctx, config.ContextKeyPaasConfig, paasConfig)
+
+	nsDefs, namespaces, err := r.renderNamespaces(ctx, paas)
+	if err != nil {
+		return nil, err
+	}
+	rendered := &RenderedPaas{Namespaces: namespaces}
+	if paasConfig.Spec.QuotaBackend.GetType() == v1alpha2.ConfigQuotaBackendResourceQuota {
+		if rendered.ResourceQuotas, err = r.backendResourceQuotas(ctx, paas, nsDefs); err != nil {
+			return nil, err
+		}
+	} else if rendered.ClusterResourceQuotas, err = r.backendEnabledQuotas(ctx, paas); err != nil {
+		return nil, err
+	}
+	if rendered.Groups, err = r.backendGroups(ctx, paas); err != nil {
+		return nil, err
+	}
+	if rendered.RoleBindings, err = r.renderRoleBindings(ctx, paas, nsDefs); err != nil {
+		return nil, err
+	}
+	rendered.ClusterRoleBindings = renderClusterRoleBindings(ctx, paasConfig, paas, nsDefs)
+	if paasConfig.Spec.FeatureFlags.NetworkIsolation {
+		for _, nsDef := range nsDefs {
+			var np *networkingv1.NetworkPolicy
+			if np, err = r.backendNetworkPolicy(paas, paasConfig, nsDef); err != nil {
+				return nil, err
+			}
+			rendered.NetworkPolicies = append(rendered.NetworkPolicies, np)
+		}
+		slices.SortFunc(rendered.NetworkPolicies, func(a, b *networkingv1.NetworkPolicy) int {
+			return cmp.Compare(a.Namespace, b.Namespace)
+		})
+	}
+	if rendered.BaselineResources, err = r.renderBaselineResources(ctx, paas, nsDefs); err != nil {
+		return nil, err
+	}
+
+	for _, objList := range [][]client.Object{
+		asObjects(rendered.Namespaces),
+		asObjects(rendered.ClusterResourceQuotas),
+		asObjects(rendered.ResourceQuotas),
+		asObjects(rendered.Groups),
+		asObjects(rendered.RoleBindings),
+		asObjects(rendered.ClusterRoleBindings),
+		asObjects(rendered.NetworkPolicies),
+	} {
+		for _, obj := range objList {
+			if err = setTypeMeta(scheme, obj); err != nil {
+				return nil, err
+			}
+		}
+	}
+	sortByName(rendered.ClusterResourceQuotas)
+	slices.SortFunc(rendered.ResourceQuotas, func(a, b *corev1.ResourceQuota) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	sortByName(rendered.Groups)
+	return rendered, nil
+}
+
+// renderNamespaces returns the namespaceDefs and Namespaces for a Paas. Namespaces are created in the fake client,
+// so that PaasNS's in these namespaces are found too. This is repeated until no new namespaces are found.
+func (r *PaasReconciler) renderNamespaces(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+) (nsDefs namespaceDefs, namespaces []*corev1.Namespace, err error) {
+	created := map[string]bool{}
+	for {
+		if nsDefs, err = r.nsDefsFromPaas(ctx, paas); err != nil {
+			return nil, nil, err
+		}
+		newNamespaces := false
+		for _, nsDef := range nsDefs {
+			if created[nsDef.nsName] {
+				continue
+			}
+			var ns *corev1.Namespace
+			if ns, err = r.backendNamespace(ctx, paas, nsDef.nsName, nsDef.quotaName); err != nil {
+				return nil, nil, err
+			}
+			if err = r.Create(ctx, ns.DeepCopy()); err != nil {
+				return nil, nil, fmt.Errorf("failed to render namespace %s: %w", ns.Name, err)
+			}
+			created[ns.Name] = true
+			namespaces = append(namespaces, ns)
+			newNamespaces = true
+		}
+		if !newNamespaces {
+			break
+		}
+	}
+	sortByName(namespaces)
+	return nsDefs, namespaces, nil
+}
+
+// renderRoleBindings returns all RoleBindings for all namespaces of a Paas, leaving out RoleBindings without subjects
+func (r *PaasReconciler) renderRoleBindings(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	nsDefs namespaceDefs,
+) (rbs []*rbac.RoleBinding, err error) {
+	for _, nsDef := range nsDefs {
+		var nsRbs []*rbac.RoleBinding
+		if nsRbs, err = r.backendNamespaceRoleBindings(ctx, paas, nsDef); err != nil {
+			return nil, err
+		}
+		for _, rb := range nsRbs {
+			if len(rb.Subjects) > 0 {
+				rbs = append(rbs, rb)
+			}
+		}
+	}
+	slices.SortFunc(rbs, func(a, b *rbac.RoleBinding) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+	return rbs, nil
+}
+
+// renderBaselineResources returns all baseline resources for all namespaces of a Paas
+func (r *PaasReconciler) renderBaselineResources(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	nsDefs namespaceDefs,
+) (objs []*unstructured.Unstructured, err error) {
+	for _, nsDef := range nsDefs {
+		var nsObjs []*unstructured.Unstructured
+		if nsObjs, err = r.backendBaselineResources(ctx, paas, nsDef); err != nil {
+			return nil, err
+		}
+		objs = append(objs, nsObjs...)
+	}
+	slices.SortFunc(objs, func(a, b *unstructured.Unstructured) int {
+		return cmp.Or(
+			cmp.Compare(a.GetNamespace(), b.GetNamespace()),
+			cmp.Compare(a.GetKind(), b.GetKind()),
+			cmp.Compare(a.GetName(), b.GetName()),
+		)
+	})
+	return objs, nil
+}
+
+// renderClusterRoleBindings returns the ClusterRoleBindings for all capability namespaces of a Paas, holding only
+// the service accounts of these namespaces as subjects, in the layout which is configured in paasConfig
+func renderClusterRoleBindings(
+	ctx context.Context,
+	paasConfig v1alpha2.PaasConfig,
+	paas *v1alpha2.Paas,
+	nsDefs namespaceDefs,
+) (crbs []*rbac.ClusterRoleBinding) {
+	crbsByRole := map[string]*rbac.ClusterRoleBinding{}
+	for _, nsDef := range nsDefs {
+		if nsDef.capName == "" {
+			continue
+		}
+		permissions := capabilityPermissions(
+			nsDef.capConfig,
+			paas.Spec.Capabilities[nsDef.capName],
+			v1alpha2.ConfigPermissionScopeCluster,
+		)
+		for role, sas := range permissions {
+			crb, exists := crbsByRole[role]
+			if !exists {
+				crb = backendLayoutClusterRoleBinding(paasConfig, paas, role)
+				crbsByRole[role] = crb
+			}
+			addOrUpdateCrb(ctx, crb, nsDef.nsName, sas)
+		}
+	}
+	for _, crb := range crbsByRole {
+		if len(crb.Subjects) == 0 {
+			continue
+		}
+		slices.SortFunc(crb.Subjects, func(a, b rbac.Subject) int {
+			return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+		})
+		crbs = append(crbs, crb)
+	}
+	sortByName(crbs)
+	return crbs
+}
+
+// setTypeMeta sets apiVersion and kind of an object, so that they are part of the rendered output
+func setTypeMeta(scheme *runtime.Scheme, obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	return nil
+}
+
+func asObjects[T client.Object](objs []T) (result []client.Object) {
+	for _, obj := range objs {
+		result = append(result, obj)
+	}
+	return result
+}
+
+func sortByName[T client.Object](objs []T) {
+	slices.SortFunc(objs, func(a, b T) int {
+		return cmp.Compare(a.GetName(), b.GetName())
+	})
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	paasquota "github.com/belastingdienst/opr-paas/v5/pkg/quota"
+	quotav1 "github.com/openshift/api/quota/v1"
+	userv1 "github.com/openshift/api/user/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+func TestRender(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(quotav1.AddToScheme(scheme))
+	utilruntime.Must(userv1.AddToScheme(scheme))
+	utilruntime.Must(v1alpha2.AddToScheme(scheme))
+
+	paas := &v1alpha2.Paas{
+		ObjectMeta: metav1.ObjectMeta{Name: "render-paas"},
+		Spec: v1alpha2.PaasSpec{
+			Quota:      paasquota.Quota{"cpu": resourcev1.MustParse("1")},
+			Namespaces: v1alpha2.PaasNamespaces{"ns1": v1alpha2.PaasNamespace{}},
+			Groups: v1alpha2.PaasGroups{
+				"devs": v1alpha2.PaasGroup{Users: []string{"dev1"}, Roles: []string{"admin"}},
+			},
+			Capabilities: v1alpha2.PaasCapabilities{"tekton": v1alpha2.PaasCapability{}},
+		},
+	}
+	paasConfig := v1alpha2.PaasConfig{
+		Spec: v1alpha2.PaasConfigSpec{
+			QuotaLabel:   "q.lbl",
+			RoleMappings: v1alpha2.ConfigRoleMappings{"admin": []string{"admin"}},
+			Capabilities: v1alpha2.ConfigCapabilities{
+				"tekton": v1alpha2.ConfigCapability{
+					DefaultPermissions: v1alpha2.ConfigCapPerm{"pipeline": []string{"view"}},
+					QuotaSettings: v1alpha2.ConfigQuotaSettings{
+						DefQuota: map[corev1.ResourceName]resourcev1.Quantity{"cpu": resourcev1.MustParse("1")},
+					},
+				},
+			},
+		},
+	}
+	// PaasNS's in namespaces of PaasNS's should be rendered too
+	paasNSs := []v1alpha2.PaasNS{
+		{ObjectMeta: metav1.ObjectMeta{Name: "sub", Namespace: "render-paas-ns1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "subsub", Namespace: "render-paas-sub"}},
+	}
+
+	rendered, err := Render(context.TODO(), scheme, paas, paasConfig, paasNSs)
+	require.NoError(t, err)
+
+	var nsNames []string
+	for _, ns := range rendered.Namespaces {
+		nsNames = append(nsNames, ns.Name)
+		assert.Equal(t, "Namespace", ns.Kind)
+	}
+	assert.Equal(t, []string{"render-paas-ns1", "render-paas-sub", "render-paas-subsub", "render-paas-tekton"},
+		nsNames)
+	require.Len(t, rendered.ClusterResourceQuotas, 2)
+	assert.Equal(t, "render-paas", rendered.ClusterResourceQuotas[0].Name)
+	assert.Equal(t, "render-paas-tekton", rendered.ClusterResourceQuotas[1].Name)
+	require.Len(t, rendered.Groups, 1)
+	assert.Equal(t, []string{"dev1"}, []string(rendered.Groups[0].Users))
+	assert.Len(t, rendered.RoleBindings, len(nsNames))
+	require.Len(t, rendered.ClusterRoleBindings, 1)
+	assert.Equal(t, "paas-view", rendered.ClusterRoleBindings[0].Name)
+	assert.Equal(t, []rbac.Subject{{Kind: "ServiceAccount", Name: "pipeline", Namespace: "render-paas-tekton"}},
+		rendered.ClusterRoleBindings[0].Subjects)
+}
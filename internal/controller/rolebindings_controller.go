@@ -7,13 +7,15 @@ See LICENSE.md for details.
 package controller
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"maps"
 	"reflect"
+	"slices"
+	"time"
 
 	"github.com/belastingdienst/opr-paas/v5/internal/config"
-	"github.com/belastingdienst/opr-paas/v5/pkg/fields"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
@@ -35,13 +37,13 @@ func (r *PaasReconciler) ensureRoleBinding(
 ) error {
 	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerRoleBindingComponent)
 	if len(rb.Subjects) < 1 {
-		return r.finalizeRoleBinding(ctx, rb)
+		return r.finalizeRoleBinding(ctx, paas, rb)
 	}
 	// See if rolebinding exists and create if it doesn't
 	found := &rbac.RoleBinding{}
 	err := r.Get(ctx, client.ObjectKeyFromObject(rb), found)
 	if err != nil && errors.IsNotFound(err) {
-		return r.createRoleBinding(ctx, rb)
+		return r.createRoleBinding(ctx, paas, rb)
 	} else if err != nil {
 		// Error that isn't due to the rolebinding not existing
 		logger.Err(err).Msg("error getting rolebinding")
@@ -59,6 +61,9 @@ func (r *PaasReconciler) ensureRoleBinding(
 		found.Subjects = rb.Subjects
 		changed = true
 	}
+	if ensureAnnotations(found, rb) {
+		changed = true
+	}
 	if changed {
 		logger.Info().
 			Str("Namespace", rb.Namespace).
@@ -66,7 +71,9 @@ func (r *PaasReconciler) ensureRoleBinding(
 			Str("roleRef", rb.RoleRef.Name).
 			Any("subject", rb.Subjects).
 			Msg("updating RoleBinding")
-		if err = r.Update(ctx, found); err != nil {
+		err = r.Update(ctx, found)
+		r.recordEvent(paas, found, eventActionUpdate, err)
+		if err != nil {
 			logger.Err(err).Msg("error updating rolebinding")
 			return err
 		}
@@ -76,6 +83,7 @@ func (r *PaasReconciler) ensureRoleBinding(
 
 func (r *PaasReconciler) createRoleBinding(
 	ctx context.Context,
+	paas *v1alpha2.Paas,
 	rb *rbac.RoleBinding,
 ) error {
 	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerRoleBindingComponent)
@@ -87,6 +95,7 @@ func (r *PaasReconciler) createRoleBinding(
 		Any("subject", rb.Subjects).
 		Msg("creating RoleBinding")
 	err := r.Create(ctx, rb)
+	r.recordEvent(paas, rb, eventActionCreate, err)
 	if err != nil {
 		// Creating the rolebinding failed
 		logger.Err(err).Msg("error creating rolebinding")
@@ -104,35 +113,24 @@ func (r *PaasReconciler) backendRoleBinding(
 	paas *v1alpha2.Paas,
 	name types.NamespacedName,
 	role string,
-	groupNames []string,
+	subjects []rbac.Subject,
 ) (*rbac.RoleBinding, error) {
 	_, logger := logging.GetLogComponent(ctx, logging.ControllerRoleBindingComponent)
 	logger.Info().Msgf("defining %s RoleBinding", name)
-	var subjects []rbac.Subject
-	for _, groupName := range groupNames {
-		subjects = append(subjects,
-			rbac.Subject{
-				Kind:     "Group",
-				APIGroup: "rbac.authorization.k8s.io",
-				Name:     groupName,
-			})
-	}
 
-	labels := map[string]string{}
 	myConfig, err := config.GetConfigFromContext(ctx)
 	if err != nil {
 		logger.Err(err).Msg("error getting config")
 		return nil, err
 	}
-	labelTemplater := templating.NewTemplater(*paas, myConfig)
-	for n, tpl := range myConfig.Spec.Templating.RoleBindingLabels {
-		var result fields.ElementMap
-		result, err = labelTemplater.TemplateToMap(n, tpl)
-		if err != nil {
-			return nil, err
-		}
-		r := result.AsElementMap()
-		maps.Copy(labels, r.AsLabels())
+	templater := templating.NewTemplater(*paas, myConfig)
+	labels, err := templater.TemplateItemsToMap(myConfig.Spec.Templating.RoleBindingLabels)
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := templater.TemplateItemsToMap(myConfig.Spec.Templating.RoleBindingAnnotations)
+	if err != nil {
+		return nil, err
 	}
 	rb := &rbac.RoleBinding{
 		TypeMeta: metav1.TypeMeta{
@@ -140,9 +138,10 @@ func (r *PaasReconciler) backendRoleBinding(
 			APIVersion: "rbac.authorization.k8s.io/v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      name.Name,
-			Namespace: name.Namespace,
-			Labels:    labels,
+			Name:        name.Name,
+			Namespace:   name.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
 		},
 		Subjects: subjects,
 		RoleRef: rbac.RoleRef{
@@ -162,6 +161,7 @@ func (r *PaasReconciler) backendRoleBinding(
 // finalizeRoleBinding ensures RoleBinding presence in given rolebinding.
 func (r *PaasReconciler) finalizeRoleBinding(
 	ctx context.Context,
+	paas *v1alpha2.Paas,
 	rb *rbac.RoleBinding,
 ) error {
 	namespacedName := types.NamespacedName{
@@ -177,83 +177,158 @@ func (r *PaasReconciler) finalizeRoleBinding(
 		// Error that isn't due to the rolebinding not existing
 		return err
 	}
-	return r.Delete(ctx, rb)
-}
-
-// reconcileRolebindings is used by the Paas reconciler to reconcile RB's
-func (r *PaasReconciler) reconcileNamespaceRolebinding(
-	ctx context.Context,
-	paas *v1alpha2.Paas,
-	nsName string,
-	roleName string,
-	groupNames []string,
-) error {
-	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerRoleBindingComponent)
-	rbName := types.NamespacedName{Namespace: nsName, Name: fmt.Sprintf("paas-%s", roleName)}
-	logger.Debug().
-		Str("role", roleName).
-		Strs("groups", groupNames).
-		Msg("creating Rolebinding")
-	rb, err := r.backendRoleBinding(ctx, paas, rbName, roleName, groupNames)
-	if err != nil {
-		return err
-	}
-	if err = r.ensureRoleBinding(ctx, paas, rb); err != nil {
-		err = fmt.Errorf(
-			"failure while creating/updating rolebinding %s/%s: %s",
-			rb.Namespace,
-			rb.Name,
-			err.Error(),
-		)
-		return err
-	}
-	return nil
+	err = r.Delete(ctx, rb)
+	r.recordEvent(paas, rb, eventActionDelete, err)
+	return err
 }
 
-// reconcileRolebindings is used by the Paas reconciler to reconcile RB's
-func (r *PaasReconciler) reconcileNamespaceRolebindings(
+// backendNamespaceRoleBindings returns all RoleBindings which are desired in a namespace, based on the groups
+// that should have access to this namespace and the (scoped) role mappings in the PaasConfig which apply to it.
+// RoleBindings without subjects are returned too, so that they can be cleaned.
+func (r *PaasReconciler) backendNamespaceRoleBindings(
 	ctx context.Context,
 	paas *v1alpha2.Paas,
-	nsName string,
-	groupKeys []string,
-) error {
+	nsDef namespaceDef,
+) (rbs []*rbac.RoleBinding, err error) {
 	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerRoleBindingComponent)
 	// Use a map of sets to avoid duplicates
-	roleGroups := map[string]map[string]struct{}{}
+	roleSubjects := map[string]map[rbac.Subject]struct{}{}
 
 	myConfig, err := config.GetConfigFromContext(ctx)
 	if err != nil {
 		logger.Err(err).Msg("error getting config")
-		return err
+		return nil, err
 	}
 	for _, roleList := range myConfig.Spec.RoleMappings {
 		for _, role := range roleList {
-			roleGroups[role] = map[string]struct{}{}
+			roleSubjects[role] = map[rbac.Subject]struct{}{}
+		}
+	}
+	for _, role := range myConfig.Spec.ScopedRoleMappings.ClusterRoles() {
+		roleSubjects[role] = map[rbac.Subject]struct{}{}
+	}
+	for _, capConfig := range myConfig.Spec.Capabilities {
+		for role, scope := range capConfig.PermissionScopes {
+			if scope != v1alpha2.ConfigPermissionScopeCluster {
+				roleSubjects[role] = map[rbac.Subject]struct{}{}
+			}
 		}
 	}
 
-	logger.Info().Any("Rolebindings map", roleGroups).Msg("all roles")
-	paasGroups := paas.Spec.Groups.Filtered(groupKeys)
-	for groupKey, groupRoles := range paasGroups.Roles() {
+	logger.Info().Any("Rolebindings map", slices.Collect(maps.Keys(roleSubjects))).Msg("all roles")
+	paasGroups := paas.Spec.Groups.Filtered(nsDef.groups)
+	// Roles which have expired are no longer bound
+	now := time.Now()
+	for groupKey, groupRoles := range paasGroups.ActiveRoles(now) {
 		logger.Info().Msgf("defining Rolebindings for Group %s", groupKey)
-		// Convert the groupKey to a groupName to map the rolebinding subjects to a group
-		groupName := paas.GroupKey2GroupName(groupKey)
-		for _, mappedRole := range myConfig.Spec.RoleMappings.Roles(groupRoles) {
-			if _, exists := roleGroups[mappedRole]; !exists {
-				roleGroups[mappedRole] = map[string]struct{}{}
+		// Convert the groupKey to the subjects of the group, as defined by the group backend, and add the service
+		// accounts and users which are bound directly
+		var subjects []rbac.Subject
+		if subjects, err = groupSubjects(paas, myConfig, groupKey, now); err != nil {
+			return nil, err
+		}
+		subjects = append(subjects, directSubjects(paasGroups[groupKey], nsDef.nsName)...)
+		mappedRoles := myConfig.Spec.ScopedRoleMappings.Roles(
+			myConfig.Spec.RoleMappings,
+			groupRoles,
+			nsDef.kind(),
+			nsDef.capName,
+		)
+		for _, mappedRole := range mappedRoles {
+			if _, exists := roleSubjects[mappedRole]; !exists {
+				roleSubjects[mappedRole] = map[rbac.Subject]struct{}{}
+			}
+			for _, subject := range subjects {
+				roleSubjects[mappedRole][subject] = struct{}{}
 			}
-			roleGroups[mappedRole][groupName] = struct{}{}
 		}
 	}
 
-	for roleName, groupSet := range roleGroups {
-		groupNames := make([]string, 0, len(groupSet))
-		for groupName := range groupSet {
-			groupNames = append(groupNames, groupName)
+	for role, subjects := range capabilityRoleSubjects(paas, myConfig, nsDef) {
+		if _, exists := roleSubjects[role]; !exists {
+			roleSubjects[role] = map[rbac.Subject]struct{}{}
 		}
-		err = r.reconcileNamespaceRolebinding(ctx, paas, nsName, roleName, groupNames)
-		if err != nil {
-			return err
+		for _, subject := range subjects {
+			roleSubjects[role][subject] = struct{}{}
+		}
+	}
+
+	for roleName, subjectSet := range roleSubjects {
+		// Sort, so that subjects are in a stable order and RoleBindings are not updated needlessly
+		subjects := slices.SortedFunc(maps.Keys(subjectSet), func(a, b rbac.Subject) int {
+			return cmp.Or(
+				cmp.Compare(a.Kind, b.Kind),
+				cmp.Compare(a.Namespace, b.Namespace),
+				cmp.Compare(a.Name, b.Name),
+			)
+		})
+		rbName := types.NamespacedName{Namespace: nsDef.nsName, Name: fmt.Sprintf("paas-%s", roleName)}
+		logger.Debug().
+			Str("role", roleName).
+			Any("subjects", subjects).
+			Msg("defining Rolebinding")
+		var rb *rbac.RoleBinding
+		if rb, err = r.backendRoleBinding(ctx, paas, rbName, roleName, subjects); err != nil {
+			return nil, err
+		}
+		rbs = append(rbs, rb)
+	}
+	return rbs, nil
+}
+
+// capabilityRoleSubjects returns the service accounts of the capabilities of a Paas which are bound with RoleBindings
+// in a namespace, by role. These are the permissions with the paas scope in all namespaces of the Paas, and the
+// permissions with the namespace scope in the namespace of the capability itself.
+func capabilityRoleSubjects(
+	paas *v1alpha2.Paas,
+	myConfig v1alpha2.PaasConfig,
+	nsDef namespaceDef,
+) map[string][]rbac.Subject {
+	roleSubjects := map[string][]rbac.Subject{}
+	for capName, capability := range paas.Spec.Capabilities {
+		capConfig, exists := myConfig.Spec.Capabilities[capName]
+		if !exists || capConfig.QuotaSettings.External() {
+			continue
+		}
+		scopes := []v1alpha2.ConfigPermissionScope{v1alpha2.ConfigPermissionScopePaas}
+		if nsDef.capName == capName {
+			scopes = append(scopes, v1alpha2.ConfigPermissionScopeNamespace)
+		}
+		for _, scope := range scopes {
+			for role, sas := range capabilityPermissions(capConfig, capability, scope) {
+				for sa, add := range sas {
+					if add {
+						roleSubjects[role] = append(roleSubjects[role], rbac.Subject{
+							Kind:      "ServiceAccount",
+							Namespace: join(paas.Name, capName),
+							Name:      sa,
+						})
+					}
+				}
+			}
+		}
+	}
+	return roleSubjects
+}
+
+// reconcileRolebindings is used by the Paas reconciler to reconcile RB's
+func (r *PaasReconciler) reconcileNamespaceRolebindings(
+	ctx context.Context,
+	paas *v1alpha2.Paas,
+	nsDef namespaceDef,
+) error {
+	rbs, err := r.backendNamespaceRoleBindings(ctx, paas, nsDef)
+	if err != nil {
+		return err
+	}
+	for _, rb := range rbs {
+		if err = r.ensureRoleBinding(ctx, paas, rb); err != nil {
+			return fmt.Errorf(
+				"failure while creating/updating rolebinding %s/%s: %s",
+				rb.Namespace,
+				rb.Name,
+				err.Error(),
+			)
 		}
 	}
 	return nil
@@ -266,9 +341,9 @@ func (r *PaasReconciler) reconcilePaasRolebindings(
 	nsDefs namespaceDefs,
 ) error {
 	for _, nsDef := range nsDefs {
-		err := r.reconcileNamespaceRolebindings(ctx, paas, nsDef.nsName, nsDef.groups)
+		err := r.reconcileNamespaceRolebindings(ctx, paas, nsDef)
 		if err != nil {
-			return err
+			return newNamespaceError(nsDef.nsName, err)
 		}
 	}
 	return nil
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v5/internal/config"
@@ -114,7 +115,8 @@ var _ = Describe("Rolebinding", Ordered, func() {
 		expectedTecRoles := []string{tecRole1, tecRole2}
 
 		It("reconciles successfully", func() {
-			err := reconciler.reconcileNamespaceRolebindings(ctx, paas, ns1, paas.Spec.Groups.Keys())
+			nsDef := newNamespaceDef(ns1, paasName, paas.Spec.Groups.Keys(), nil)
+			err := reconciler.reconcileNamespaceRolebindings(ctx, paas, nsDef)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -202,4 +204,173 @@ var _ = Describe("Rolebinding", Ordered, func() {
 			}, &rbac.RoleBinding{})).To(MatchError(ContainSubstring("not found")))
 		})
 	})
+
+	When("roles of a group have expired", func() {
+		It("no longer binds the expired roles", func() {
+			const (
+				expiredFuncRole = "expired-role"
+				expiredTecRole  = "edit"
+			)
+			myConfig.Spec.RoleMappings[expiredFuncRole] = []string{expiredTecRole}
+			ctx = context.WithValue(ctx, config.ContextKeyPaasConfig, myConfig)
+			paas.Spec.Groups[groupName] = v1alpha2.PaasGroup{
+				Users:        []string{"u1"},
+				Roles:        []string{funcRoleName, expiredFuncRole},
+				RoleExpiries: map[string]metav1.Time{expiredFuncRole: metav1.NewTime(time.Now().Add(-time.Minute))},
+			}
+
+			nsDef := newNamespaceDef(ns1, paasName, paas.Spec.Groups.Keys(), nil)
+			rbs, err := reconciler.backendNamespaceRoleBindings(ctx, paas, nsDef)
+			Expect(err).NotTo(HaveOccurred())
+			subjects := map[string][]rbac.Subject{}
+			for _, rb := range rbs {
+				subjects[rb.RoleRef.Name] = rb.Subjects
+			}
+			Expect(subjects).To(HaveKeyWithValue(tecRole1, HaveLen(1)))
+			Expect(subjects).To(HaveKeyWithValue(expiredTecRole, BeEmpty()))
+		})
+		It("removes all access when every role of a group has expired", func() {
+			originalCtx, originalGroups := ctx, paas.Spec.Groups
+			DeferCleanup(func() {
+				ctx, paas.Spec.Groups = originalCtx, originalGroups
+				nsDef := newNamespaceDef(ns1, paasName, paas.Spec.Groups.Keys(), nil)
+				Expect(reconciler.reconcileNamespaceRolebindings(ctx, paas, nsDef)).To(Succeed())
+			})
+			expired := metav1.NewTime(time.Now().Add(-time.Minute))
+			paas.Spec.Groups = v1alpha2.PaasGroups{
+				groupName: v1alpha2.PaasGroup{
+					Users:           []string{"u1"},
+					Roles:           []string{funcRoleName},
+					RoleExpiries:    map[string]metav1.Time{funcRoleName: expired},
+					ServiceAccounts: []v1alpha2.PaasServiceAccount{{Name: "pipeline"}},
+				},
+			}
+			// Without roles, a group would be bound to the default roles
+			myConfig.Spec.RoleMappings = v1alpha2.ConfigRoleMappings{
+				"default":    []string{tecRole1},
+				funcRoleName: []string{tecRole1, tecRole2},
+			}
+			ctx = context.WithValue(ctx, config.ContextKeyPaasConfig, myConfig)
+
+			nsDef := newNamespaceDef(ns1, paasName, paas.Spec.Groups.Keys(), nil)
+			Expect(reconciler.reconcileNamespaceRolebindings(ctx, paas, nsDef)).To(Succeed())
+			for _, tecRole := range []string{tecRole1, tecRole2} {
+				var rb rbac.RoleBinding
+				err := reconciler.Get(ctx, types.NamespacedName{Name: join("paas", tecRole), Namespace: ns1}, &rb)
+				if err == nil {
+					Expect(rb.Subjects).To(BeEmpty())
+				} else {
+					Expect(err).To(MatchError(ContainSubstring("not found")))
+				}
+			}
+		})
+	})
+
+	When("role mappings are scoped to capability namespaces", func() {
+		It("binds the scoped roles in selected namespaces only", func() {
+			const scopedTecRole = "edit"
+			myConfig.Spec.ScopedRoleMappings = v1alpha2.ConfigScopedRoleMappings{
+				{
+					Role: funcRoleName,
+					Namespaces: v1alpha2.ConfigNamespaceSelector{
+						Kinds:        []v1alpha2.ConfigNamespaceKind{v1alpha2.ConfigNamespaceKindCapability},
+						Capabilities: []string{capName},
+					},
+					ClusterRoles: []string{scopedTecRole},
+				},
+			}
+			ctx = context.WithValue(ctx, config.ContextKeyPaasConfig, myConfig)
+			boundRoles := func(nsDef namespaceDef) (roles []string) {
+				rbs, err := reconciler.backendNamespaceRoleBindings(ctx, paas, nsDef)
+				Expect(err).NotTo(HaveOccurred())
+				for _, rb := range rbs {
+					if len(rb.Subjects) > 0 {
+						roles = append(roles, rb.RoleRef.Name)
+					}
+				}
+				return roles
+			}
+
+			paasNsDef := newNamespaceDef(ns1, paasName, paas.Spec.Groups.Keys(), nil)
+			Expect(boundRoles(paasNsDef)).To(ConsistOf(tecRole1, tecRole2))
+
+			capNsDef := newNamespaceDef(join(paasName, capName), paasName, paas.Spec.Groups.Keys(), nil)
+			capNsDef.capName = capName
+			Expect(boundRoles(capNsDef)).To(ConsistOf(scopedTecRole))
+		})
+	})
+
+	When("capability permissions are scoped to the Paas or the capability namespace", func() {
+		It("binds the service accounts of the capability with RoleBindings", func() {
+			const (
+				capSA          = "argocd-sa"
+				paasScopedRole = "paas-scoped"
+				nsScopedRole   = "ns-scoped"
+				clusterRole    = "cluster-scoped"
+			)
+			capConfig := myConfig.Spec.Capabilities[capName]
+			capConfig.DefaultPermissions = v1alpha2.ConfigCapPerm{
+				capSA: {paasScopedRole, nsScopedRole, clusterRole},
+			}
+			capConfig.PermissionScopes = map[string]v1alpha2.ConfigPermissionScope{
+				paasScopedRole: v1alpha2.ConfigPermissionScopePaas,
+				nsScopedRole:   v1alpha2.ConfigPermissionScopeNamespace,
+			}
+			myConfig.Spec.Capabilities[capName] = capConfig
+			ctx = context.WithValue(ctx, config.ContextKeyPaasConfig, myConfig)
+			capNs := join(paasName, capName)
+			saSubject := rbac.Subject{Kind: "ServiceAccount", Namespace: capNs, Name: capSA}
+			boundSubjects := func(nsDef namespaceDef) map[string][]rbac.Subject {
+				rbs, err := reconciler.backendNamespaceRoleBindings(ctx, paas, nsDef)
+				Expect(err).NotTo(HaveOccurred())
+				subjects := map[string][]rbac.Subject{}
+				for _, rb := range rbs {
+					subjects[rb.RoleRef.Name] = rb.Subjects
+				}
+				return subjects
+			}
+
+			paasNsSubjects := boundSubjects(newNamespaceDef(ns1, paasName, paas.Spec.Groups.Keys(), nil))
+			Expect(paasNsSubjects).To(HaveKeyWithValue(paasScopedRole, ConsistOf(saSubject)))
+			Expect(paasNsSubjects).To(HaveKeyWithValue(nsScopedRole, BeEmpty()))
+			Expect(paasNsSubjects).NotTo(HaveKey(clusterRole))
+
+			capNsDef := newNamespaceDef(capNs, paasName, paas.Spec.Groups.Keys(), nil)
+			capNsDef.capName = capName
+			capNsSubjects := boundSubjects(capNsDef)
+			Expect(capNsSubjects).To(HaveKeyWithValue(paasScopedRole, ConsistOf(saSubject)))
+			Expect(capNsSubjects).To(HaveKeyWithValue(nsScopedRole, ConsistOf(saSubject)))
+			Expect(capNsSubjects).NotTo(HaveKey(clusterRole))
+		})
+	})
+
+	When("groups have service accounts and users", func() {
+		It("binds them directly next to the group", func() {
+			paas.Spec.Groups[groupName] = v1alpha2.PaasGroup{
+				Roles: []string{funcRoleName},
+				ServiceAccounts: []v1alpha2.PaasServiceAccount{
+					{Name: "pipeline"},
+					{Name: "deployer", Namespace: join(paasName, capName)},
+				},
+				UserSubjects: []string{"robot"},
+			}
+			nsDef := newNamespaceDef(ns1, paasName, paas.Spec.Groups.Keys(), nil)
+			rbs, err := reconciler.backendNamespaceRoleBindings(ctx, paas, nsDef)
+			Expect(err).NotTo(HaveOccurred())
+			subjects := map[string][]rbac.Subject{}
+			for _, rb := range rbs {
+				subjects[rb.RoleRef.Name] = rb.Subjects
+			}
+			Expect(subjects).To(HaveKeyWithValue(tecRole1, ConsistOf(
+				rbac.Subject{
+					Kind:     "Group",
+					APIGroup: "rbac.authorization.k8s.io",
+					Name:     paas.GroupKey2GroupName(groupName),
+				},
+				rbac.Subject{Kind: "ServiceAccount", Namespace: ns1, Name: "pipeline"},
+				rbac.Subject{Kind: "ServiceAccount", Namespace: join(paasName, capName), Name: "deployer"},
+				rbac.Subject{Kind: "User", APIGroup: "rbac.authorization.k8s.io", Name: "robot"},
+			)))
+		})
+	})
 })
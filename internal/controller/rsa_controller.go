@@ -1,7 +1,10 @@
 package controller
 
 import (
+	"bytes"
 	"context"
+	"maps"
+	"sync"
 
 	"github.com/belastingdienst/opr-paas-cli/v2/pkg/crypt"
 	"github.com/belastingdienst/opr-paas/v5/internal/config"
@@ -10,23 +13,57 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 )
 
-var (
+// cryptCache caches the decryption private keys, and a crypt per Paas which is created with these keys.
+// When the keys change, all crypts are reset. A cryptCache is safe for concurrent use, and the zero value is ready
+// to be used.
+type cryptCache struct {
+	mutex sync.Mutex
+	keys  *crypt.PrivateKeys
 	// crypts contains a maps of crypt against a Paas name
-	crypts             map[string]*crypt.Crypt
-	decryptPrivateKeys *crypt.PrivateKeys
-)
+	crypts map[string]*crypt.Crypt
+}
 
-// resetCrypts removes all crypts and resets decryptSecretPrivateKeys
-func resetCrypts() {
-	crypts = map[string]*crypt.Crypt{}
-	decryptPrivateKeys = nil
+// setKeys compares keys to the cached private keys, and resets all crypts when they differ.
+// Keys are compared by their PEM data, since crypt.PrivateKeys.Compare never reports keys as the same.
+func (c *cryptCache) setKeys(ctx context.Context, keys crypt.PrivateKeys) {
+	_, logger := logging.GetLogComponent(ctx, logging.ControllerSecretComponent)
+	if c.keys != nil && maps.EqualFunc(keys.AsSecretData(), c.keys.AsSecretData(), bytes.Equal) {
+		// It already was the same secret
+		logger.Debug().Msg("reusing decrypt keys")
+		return
+	}
+	logger.Debug().Msgf("setting (%d) new keys", len(keys))
+	c.keys = &keys
+	c.crypts = map[string]*crypt.Crypt{}
 }
 
-// getRsaPrivateKeys fetches secret, compares to cached private keys, resets crypts if needed, and returns keys
+// getCrypt returns the crypt for a Paas, which is created with keys. When keys differ from the cached keys, all
+// cached crypts are reset first.
+func (c *cryptCache) getCrypt(
+	ctx context.Context,
+	keys crypt.PrivateKeys,
+	paasName string,
+) (*crypt.Crypt, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.setKeys(ctx, keys)
+	if rsa, exists := c.crypts[paasName]; exists {
+		return rsa, nil
+	}
+	rsa, err := crypt.NewCryptFromKeys(*c.keys, "", paasName)
+	if err != nil {
+		return nil, err
+	}
+	_, logger := logging.GetLogComponent(ctx, logging.ControllerSecretComponent)
+	logger.Debug().Msgf("creating new crypt for %s", paasName)
+	c.crypts[paasName] = rsa
+	return rsa, nil
+}
+
+// getRsaPrivateKeys fetches the secret with the decryption keys, and returns the keys
 func (r *PaasReconciler) getRsaPrivateKeys(
 	ctx context.Context,
-) (*crypt.PrivateKeys, error) {
-	ctx, logger := logging.GetLogComponent(ctx, logging.ControllerSecretComponent)
+) (crypt.PrivateKeys, error) {
 	rsaSecret := &corev1.Secret{}
 	cfg, err := config.GetConfigFromContext(ctx)
 	if err != nil {
@@ -42,37 +79,14 @@ func (r *PaasReconciler) getRsaPrivateKeys(
 		return nil, err
 	}
 	// Create new set of keys from data in secret
-	keys, err := crypt.NewPrivateKeysFromSecretData(rsaSecret.Data)
-	if err != nil {
-		return nil, err
-	}
-
-	if decryptPrivateKeys != nil {
-		if keys.Compare(*decryptPrivateKeys) {
-			// It already was the same secret
-			logger.Debug().Msg("reusing decrypt keys")
-			return decryptPrivateKeys, nil
-		}
-	}
-
-	logger.Debug().Msgf("setting (%d) new keys", len(keys))
-	resetCrypts()
-	decryptPrivateKeys = &keys
-	return decryptPrivateKeys, nil
+	return crypt.NewPrivateKeysFromSecretData(rsaSecret.Data)
 }
 
 // getRsa returns a crypt.Crypt for a specified paasName
 func (r *PaasReconciler) getRsa(ctx context.Context, paasName string) (*crypt.Crypt, error) {
-	var c *crypt.Crypt
-	if keys, err := r.getRsaPrivateKeys(ctx); err != nil {
-		return nil, err
-	} else if rsa, exists := crypts[paasName]; exists {
-		return rsa, nil
-	} else if c, err = crypt.NewCryptFromKeys(*keys, "", paasName); err != nil {
+	keys, err := r.getRsaPrivateKeys(ctx)
+	if err != nil {
 		return nil, err
 	}
-	_, logger := logging.GetLogComponent(ctx, logging.ControllerSecretComponent)
-	logger.Debug().Msgf("creating new crypt for %s", paasName)
-	crypts[paasName] = c
-	return c, nil
+	return r.crypts.getCrypt(ctx, keys, paasName)
 }
@@ -0,0 +1,63 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/belastingdienst/opr-paas-cli/v2/pkg/crypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPrivateKeys(t *testing.T) crypt.PrivateKeys {
+	_, privateKey, err := newGeneratedCrypt("")
+	require.NoError(t, err)
+	keys, err := crypt.NewPrivateKeysFromSecretData(map[string][]byte{"privateKey0": privateKey})
+	require.NoError(t, err)
+	return keys
+}
+
+func TestCryptCache_GetCrypt(t *testing.T) {
+	ctx := context.TODO()
+	cache := cryptCache{}
+	keys := newTestPrivateKeys(t)
+
+	paas1Crypt, err := cache.getCrypt(ctx, keys, "paas1")
+	require.NoError(t, err)
+	reused, err := cache.getCrypt(ctx, keys, "paas1")
+	require.NoError(t, err)
+	assert.Same(t, paas1Crypt, reused, "crypt should be reused for the same keys and paas")
+
+	paas2Crypt, err := cache.getCrypt(ctx, keys, "paas2")
+	require.NoError(t, err)
+	assert.NotSame(t, paas1Crypt, paas2Crypt, "every paas should have its own crypt")
+
+	rotated, err := cache.getCrypt(ctx, newTestPrivateKeys(t), "paas1")
+	require.NoError(t, err)
+	assert.NotSame(t, paas1Crypt, rotated, "crypts should be reset when keys change")
+	assert.Len(t, cache.crypts, 1)
+}
+
+func TestCryptCache_GetCryptConcurrently(t *testing.T) {
+	ctx := context.TODO()
+	cache := cryptCache{}
+	keySets := []crypt.PrivateKeys{newTestPrivateKeys(t), newTestPrivateKeys(t)}
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Go(func() {
+			rsa, err := cache.getCrypt(ctx, keySets[i%2], fmt.Sprintf("paas%d", i%5))
+			assert.NoError(t, err)
+			assert.NotNil(t, rsa)
+		})
+	}
+	wg.Wait()
+}
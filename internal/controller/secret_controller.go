@@ -7,10 +7,12 @@ See LICENSE.md for details.
 package controller
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha512"
 	"encoding/hex"
 	"fmt"
+	"maps"
 	"strings"
 
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
@@ -27,6 +29,7 @@ import (
 // ensureSecret ensures Secret presence in given secret.
 func (r *PaasReconciler) ensureSecret(
 	ctx context.Context,
+	paas *v1alpha2.Paas,
 	secret *corev1.Secret,
 ) error {
 	// See if secret exists and create if it doesn't
@@ -34,13 +37,28 @@ func (r *PaasReconciler) ensureSecret(
 	err := r.Get(ctx, client.ObjectKeyFromObject(secret), found)
 	if err != nil && errors.IsNotFound(err) {
 		// Create the secret
-		return r.Create(ctx, secret)
+		err = r.Create(ctx, secret)
+		r.recordEvent(paas, secret, eventActionCreate, err)
+		return err
 	} else if err != nil {
 		// Error that isn't due to the secret not existing
 		return err
 	}
+	if secretUpToDate(paas, found, secret) {
+		return nil
+	}
 
-	return r.Update(ctx, secret)
+	secret.ResourceVersion = found.ResourceVersion
+	err = r.Update(ctx, secret)
+	r.recordEvent(paas, secret, eventActionUpdate, err)
+	return err
+}
+
+// secretUpToDate returns true when an existing Secret is owned by paas, and has the labels and data of the desired
+// Secret
+func secretUpToDate(paas *v1alpha2.Paas, found *corev1.Secret, desired *corev1.Secret) bool {
+	return paas.AmIOwner(found.OwnerReferences) && maps.Equal(found.Labels, desired.Labels) &&
+		maps.EqualFunc(found.Data, desired.Data, bytes.Equal)
 }
 
 func hashData(original string) string {
@@ -101,6 +119,14 @@ func (r *PaasReconciler) backendSecret(
 	return s, nil
 }
 
+// secretNamespacedName returns the name of the Secret which is managed for a url in a namespace
+func secretNamespacedName(namespace string, url string) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: namespace,
+		Name:      join("paas-ssh", strings.ToLower(hashData(url)[:8])),
+	}
+}
+
 // getSecrets returns a list of Secrets which are desired based on the Paas(Ns) spec
 func (r *PaasReconciler) backendSecrets(
 	ctx context.Context,
@@ -121,18 +147,15 @@ func (r *PaasReconciler) backendSecrets(
 
 	secrets := &corev1.SecretList{}
 	for url, encryptedSecretData := range encryptedSecrets {
-		namespacedName := types.NamespacedName{
-			Namespace: namespace,
-			Name:      join("paas-ssh", strings.ToLower(hashData(url)[:8])),
-		}
 		var secret *corev1.Secret
-		secret, err = r.backendSecret(ctx, paas, paasns, namespacedName, url)
+		secret, err = r.backendSecret(ctx, paas, paasns, secretNamespacedName(namespace, url), url)
 		if err != nil {
 			return nil, err
 		}
 		var decryptedSecretData []byte
 		decryptedSecretData, err = rsa.Decrypt(encryptedSecretData)
 		if err != nil {
+			PaasSecretDecryptFailuresTotal.Inc()
 			return nil, fmt.Errorf("failed to decrypt secret %s: %s", secret.Name, err.Error())
 		}
 		secret.Data["sshPrivateKey"] = decryptedSecretData
@@ -144,6 +167,7 @@ func (r *PaasReconciler) backendSecrets(
 // deleteObsoleteSecrets deletes any secrets from the existingSecrets which is not listed in the desired secrets.
 func (r *PaasReconciler) deleteObsoleteSecrets(
 	ctx context.Context,
+	paas *v1alpha2.Paas,
 	existingSecrets *corev1.SecretList,
 	desiredSecrets *corev1.SecretList,
 ) error {
@@ -154,7 +178,9 @@ func (r *PaasReconciler) deleteObsoleteSecrets(
 	for _, existingSecret := range existingSecrets.Items {
 		if !isSecretInDesiredSecrets(existingSecret, desiredSecrets) {
 			// Secret is not in the desired state, delete it
-			if err := r.Delete(ctx, &existingSecret); err != nil {
+			err := r.Delete(ctx, &existingSecret)
+			r.recordEvent(paas, &existingSecret, eventActionDelete, err)
+			if err != nil {
 				logger.Err(err).Str("Secret", existingSecret.Name).Msg("failed to delete Secret")
 				return err
 			}
@@ -221,13 +247,13 @@ func (r *PaasReconciler) reconcileNamespaceSecrets(
 	}
 	if existingSecrets != nil {
 		logger.Debug().Int("count", len(existingSecrets.Items)).Msg("existing secrets count")
-		if err = r.deleteObsoleteSecrets(ctx, existingSecrets, desiredSecrets); err != nil {
+		if err = r.deleteObsoleteSecrets(ctx, paas, existingSecrets, desiredSecrets); err != nil {
 			return err
 		}
 	}
 
 	for _, secret := range desiredSecrets.Items {
-		if err = r.ensureSecret(ctx, &secret); err != nil {
+		if err = r.ensureSecret(ctx, paas, &secret); err != nil {
 			logger.Err(err).Str("secret", secret.Name).Msg("failure while reconciling secret")
 			return err
 		}
@@ -246,7 +272,7 @@ func (r *PaasReconciler) reconcilePaasSecrets(
 	for _, nsDef := range nsDefs {
 		err := r.reconcileNamespaceSecrets(ctx, paas, nsDef.paasns, nsDef.nsName, nsDef.secrets)
 		if err != nil {
-			return err
+			return newNamespaceError(nsDef.nsName, err)
 		}
 	}
 	return nil
@@ -1,6 +1,9 @@
 package logging
 
-import "strings"
+import (
+	"slices"
+	"strings"
+)
 
 // Components is a map that holds components and their Debug state (false is InfoLevel, True is DebugLevel)
 type Components map[Component]bool
@@ -22,6 +25,8 @@ const (
 	WebhookPaasComponentV2 Component = iota
 	// WebhookPaasNSComponentV2 represents a logging component for the v1alpha2 code for the PaasNS webhook
 	WebhookPaasNSComponentV2 Component = iota
+	// WebhookQuotaRequestComponentV2 represents a logging component for the v1alpha2 code for the QuotaRequest webhook
+	WebhookQuotaRequestComponentV2 Component = iota
 	// WebhookUtilsComponentV2 represents a logging component for the v1alpha2 utils code
 	WebhookUtilsComponentV2 Component = iota
 
@@ -39,12 +44,16 @@ const (
 	ControllerClusterRoleBindingsComponent Component = iota
 	// ControllerGroupComponent represents a logging component used by the group controller
 	ControllerGroupComponent Component = iota
+	// ControllerGroupSyncComponent represents a logging component used by the group sync controller
+	ControllerGroupSyncComponent Component = iota
 	// ControllerNamespaceComponent represents a logging component used by the namespace controller
 	ControllerNamespaceComponent Component = iota
 	// ControllerPaasComponent represents a logging component used by the paas controller
 	ControllerPaasComponent Component = iota
 	// ControllerPaasConfigComponent represents a logging component used by the paasConfig controller
 	ControllerPaasConfigComponent Component = iota
+	// ControllerQuotaRequestComponent represents a logging component used by the quota request controller
+	ControllerQuotaRequestComponent Component = iota
 	// ControllerRoleBindingComponent represents a logging component used by the role binding controller
 	ControllerRoleBindingComponent Component = iota
 	// ControllerSecretComponent represents a logging component used by the secret controller
@@ -61,18 +70,21 @@ var (
 		"runtime": RuntimeComponent,
 		"api":     ApiComponent,
 
-		"paasconfig_webhook_v2": WebhookPaasConfigComponentV2,
-		"paas_webhook_v2":       WebhookPaasComponentV2,
-		"paasns_webhook_v2":     WebhookPaasNSComponentV2,
-		"utils_webhook_v2":      WebhookUtilsComponentV2,
+		"paasconfig_webhook_v2":   WebhookPaasConfigComponentV2,
+		"paas_webhook_v2":         WebhookPaasComponentV2,
+		"paasns_webhook_v2":       WebhookPaasNSComponentV2,
+		"quotarequest_webhook_v2": WebhookQuotaRequestComponentV2,
+		"utils_webhook_v2":        WebhookUtilsComponentV2,
 
 		"capabilities_controller":         ControllerCapabilitiesComponent,
 		"cluster_quota_controller":        ControllerClusterQuotaComponent,
 		"cluster_role_binding_controller": ControllerClusterRoleBindingsComponent,
 		"group_controller":                ControllerGroupComponent,
+		"group_sync_controller":           ControllerGroupSyncComponent,
 		"namespace_controller":            ControllerNamespaceComponent,
 		"paas_controller":                 ControllerPaasComponent,
 		"paas_config_controller":          ControllerPaasConfigComponent,
+		"quota_request_controller":        ControllerQuotaRequestComponent,
 		"rolebinding_controller":          ControllerRoleBindingComponent,
 		"secret_controller":               ControllerSecretComponent,
 
@@ -124,3 +136,20 @@ func NewComponentsFromStringMap(enabledComponents map[string]bool) Components {
 	}
 	return components
 }
+
+// IsComponentName returns true when name is the name of a known component (as used in PaasConfig and command
+// arguments)
+func IsComponentName(name string) bool {
+	_, exists := componentConverter[name]
+	return exists
+}
+
+// ComponentNames returns the sorted names of all known components
+func ComponentNames() []string {
+	names := make([]string, 0, len(componentConverter))
+	for name := range componentConverter {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
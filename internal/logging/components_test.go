@@ -1,6 +1,7 @@
 package logging
 
 import (
+	"slices"
 	"strings"
 	"testing"
 
@@ -67,3 +68,17 @@ func TestNewComponentsFromStringMap(t *testing.T) {
 		require.False(t, enabled)
 	}
 }
+
+func TestIsComponentName(t *testing.T) {
+	assert.True(t, IsComponentName("paas_controller"))
+	assert.True(t, IsComponentName("paasconfig_webhook_v2"))
+	assert.False(t, IsComponentName("paas_controllers"))
+	assert.False(t, IsComponentName(""))
+}
+
+func TestComponentNames(t *testing.T) {
+	names := ComponentNames()
+	assert.Len(t, names, len(componentConverter))
+	assert.True(t, slices.IsSorted(names))
+	assert.Contains(t, names, "secret_controller")
+}
@@ -9,6 +9,7 @@ package logging
 import (
 	"context"
 	"net/http"
+	"sync"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
@@ -19,14 +20,23 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
+type contextKey int
+
+const (
+	// contextKeyDebug is the contextKey to enable debug logging for all components in a context
+	contextKeyDebug contextKey = iota
+)
+
 var (
 	// Commandline args will use this to enable all debug logging
 	staticDebug bool
 	// Commandline args can use this to enable logging for a component
 	staticComponents Components
-	// Commandline args will use this to enable all debug logging
+	// dynamicMutex guards dynamicDebug and dynamicComponents, which are changed while the operator is running
+	dynamicMutex sync.RWMutex
+	// PaasConfig will use this to enable all debug logging
 	dynamicDebug bool
-	// Commandline args can use this to enable logging for a component
+	// PaasConfig can use this to enable logging for a component
 	dynamicComponents Components
 )
 
@@ -96,11 +106,21 @@ func SetStaticLoggingConfig(debug bool, components Components) {
 
 // SetDynamicLoggingConfig configures global debugging and component debugging from Paas perspective
 func SetDynamicLoggingConfig(debug bool, components map[Component]bool) {
+	dynamicMutex.Lock()
+	defer dynamicMutex.Unlock()
 	dynamicDebug = debug
 	dynamicComponents = components
 }
 
+// WithDebug derives a context in which debug logging is enabled for all components, e.a. to debug the reconciliation
+// of a single Paas.
+func WithDebug(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKeyDebug, true)
+}
+
 func getComponentDebugLevel(name Component) zerolog.Level {
+	dynamicMutex.RLock()
+	defer dynamicMutex.RUnlock()
 	if enabled, exists := dynamicComponents[name]; exists {
 		if enabled {
 			return zerolog.DebugLevel
@@ -120,6 +140,9 @@ func getComponentDebugLevel(name Component) zerolog.Level {
 func GetLogComponent(ctx context.Context, name Component) (context.Context, *zerolog.Logger) {
 	logger := log.Ctx(ctx)
 	level := getComponentDebugLevel(name)
+	if debug, ok := ctx.Value(contextKeyDebug).(bool); ok && debug {
+		level = zerolog.DebugLevel
+	}
 
 	if logger.GetLevel() != level {
 		ll := logger.Level(level).With().Str("component", componentToString(name)).Logger()
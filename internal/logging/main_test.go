@@ -148,3 +148,17 @@ func TestDebuggingConfig(t *testing.T) {
 	_, componentNoDebugLogger := GetLogComponent(ctx, comp1)
 	assert.Equal(t, zerolog.InfoLevel, componentNoDebugLogger.GetLevel())
 }
+
+func TestDebuggingContext(t *testing.T) {
+	const comp1 = TestComponent
+	SetStaticLoggingConfig(false, nil)
+	SetDynamicLoggingConfig(false, nil)
+	ctx := context.TODO()
+	_, noDebugLogger := GetLogComponent(ctx, comp1)
+	assert.Equal(t, zerolog.InfoLevel, noDebugLogger.GetLevel())
+	// debug in context overrides all other config
+	SetDynamicLoggingConfig(false, map[Component]bool{comp1: false})
+	_, contextDebugLogger := GetLogComponent(WithDebug(ctx), comp1)
+	assert.Equal(t, zerolog.DebugLevel, contextDebugLogger.GetLevel())
+	SetDynamicLoggingConfig(false, nil)
+}
@@ -14,6 +14,7 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/belastingdienst/opr-paas-cli/v2/pkg/crypt"
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
@@ -24,6 +25,7 @@ import (
 	"github.com/belastingdienst/opr-paas/v5/pkg/templating"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -129,10 +131,14 @@ func (v *PaasCustomValidator) validate(ctx context.Context, paas *v1alpha2.Paas)
 		validatePaasSecrets,
 		validateCustomFields,
 		validateGroupNames,
+		validateGroupUsers,
+		validateGroupExpiries,
+		validateGroupSubjects,
 		validatePaasNamespaceNames,
 		validatePaasNamespaceGroups,
 		validateAppNamespaceQuota,
 		validateSubmittedQuotaAgainstMaxAllowed,
+		validatePaasNamespaceRetention,
 	} {
 		if errs, validationErr := val(ctx, v.client, conf, paas); validationErr != nil {
 			return nil, apierrors.NewInternalError(validationErr)
@@ -365,6 +371,29 @@ func validatePaasNamespaceGroups(
 	return ferrs, nil
 }
 
+// validatePaasNamespaceRetention returns an error when a Paas overrides the namespace retention with a policy which
+// is not allowed by the PaasConfig, or with an invalid namespace retention
+func validatePaasNamespaceRetention(
+	_ context.Context,
+	_ client.Client,
+	conf v1alpha2.PaasConfig,
+	paas *v1alpha2.Paas,
+) ([]*field.Error, error) {
+	retention := paas.Spec.NamespaceRetention
+	if retention == nil {
+		return nil, nil
+	}
+	retentionPath := field.NewPath(pathSpec).Child("namespaceRetention")
+	if !slices.Contains(conf.Spec.AllowedNamespaceRetentionPolicies, retention.GetPolicy()) {
+		return []*field.Error{field.Forbidden(
+			retentionPath.Child("policy"),
+			fmt.Sprintf("namespace retention policy %s is not allowed by the PaasConfig (allowed: %v)",
+				retention.GetPolicy(), conf.Spec.AllowedNamespaceRetentionPolicies),
+		)}, nil
+	}
+	return validateNamespaceRetention(*retention, retentionPath), nil
+}
+
 // validatePaasRequestor returns an error if The requestor field in a Paas does not meet with validation RE
 func validatePaasRequestor(
 	_ context.Context,
@@ -416,6 +445,161 @@ func validateGroupNames(
 	return errs, nil
 }
 
+// validateGroupUsers returns an error for every group with users (and without a query), when the Virtual group
+// backend is configured to reject them, since there is no group which the users can be added to
+func validateGroupUsers(
+	_ context.Context,
+	_ client.Client,
+	conf v1alpha2.PaasConfig,
+	paas *v1alpha2.Paas,
+) ([]*field.Error, error) {
+	backend := conf.Spec.GroupBackend
+	if backend.GetType() != v1alpha2.ConfigGroupBackendVirtual ||
+		backend.GetUsers() != v1alpha2.ConfigGroupUsersReject {
+		return nil, nil
+	}
+	var errs []*field.Error
+	for key, grp := range paas.Spec.Groups {
+		if len(grp.Users) > 0 && len(grp.Query) == 0 {
+			errs = append(errs, field.Invalid(
+				field.NewPath(pathSpec).Child("groups").Key(key).Child("users"),
+				grp.Users,
+				"groups with users are not supported by the Virtual group backend",
+			))
+		}
+	}
+	return errs, nil
+}
+
+// validateGroupExpiries returns an error for every expiry of a user or role which is not in the group, and for every
+// expiry which lies beyond the maximum window of the PaasConfig
+func validateGroupExpiries(
+	_ context.Context,
+	_ client.Client,
+	conf v1alpha2.PaasConfig,
+	paas *v1alpha2.Paas,
+) ([]*field.Error, error) {
+	var errs []*field.Error
+	for key, grp := range paas.Spec.Groups {
+		groupPath := field.NewPath(pathSpec).Child("groups").Key(key)
+		errs = append(errs, validateExpiries(conf, groupPath.Child("userExpiries"), grp.Users, grp.UserExpiries)...)
+		errs = append(errs, validateExpiries(conf, groupPath.Child("roleExpiries"), grp.Roles, grp.RoleExpiries)...)
+	}
+	return errs, nil
+}
+
+// validateGroupSubjects returns an error for every service account and user of a group which is not allowed by the
+// validations in the PaasConfig, and for every service account in a namespace of another Paas. Service accounts and
+// users are only allowed when a validation is configured for them.
+func validateGroupSubjects(
+	ctx context.Context,
+	c client.Client,
+	conf v1alpha2.PaasConfig,
+	paas *v1alpha2.Paas,
+) ([]*field.Error, error) {
+	var errs []*field.Error
+	saValidationRE := conf.GetValidationRE("paas", "serviceAccountSubject")
+	userValidationRE := conf.GetValidationRE("paas", "userSubject")
+	for key, grp := range paas.Spec.Groups {
+		groupPath := field.NewPath(pathSpec).Child("groups").Key(key)
+		for i, sa := range grp.ServiceAccounts {
+			saPath := groupPath.Child("serviceAccounts").Index(i)
+			if sa.Namespace != "" {
+				ofPaas, err := isNamespaceOfPaas(ctx, c, conf, paas, sa.Namespace)
+				if err != nil {
+					return nil, err
+				}
+				if !ofPaas {
+					errs = append(errs, field.Invalid(
+						saPath.Child("namespace"),
+						sa.Namespace,
+						"service account namespace is not a namespace of this Paas",
+					))
+				}
+			}
+			errs = append(errs, validateSubject(saValidationRE, saPath.Child("name"), sa.Name)...)
+		}
+		for i, user := range grp.UserSubjects {
+			errs = append(errs, validateSubject(userValidationRE, groupPath.Child("userSubjects").Index(i), user)...)
+		}
+	}
+	return errs, nil
+}
+
+// isNamespaceOfPaas returns true when a namespace is defined by the spec of a Paas (in spec.namespaces, or for one of
+// its capabilities), or when the namespace exists and is controlled by the Paas (e.g. the namespace of a PaasNS)
+func isNamespaceOfPaas(
+	ctx context.Context,
+	c client.Client,
+	conf v1alpha2.PaasConfig,
+	paas *v1alpha2.Paas,
+	namespace string,
+) (bool, error) {
+	for nsName := range paas.Spec.Namespaces {
+		if namespace == paas.Name+"-"+nsName {
+			return true, nil
+		}
+	}
+	for capName := range paas.Spec.Capabilities {
+		capConfig, exists := conf.Spec.Capabilities[capName]
+		if exists && !capConfig.QuotaSettings.External() && namespace == paas.Name+"-"+capName {
+			return true, nil
+		}
+	}
+	var ns corev1.Namespace
+	if err := c.Get(ctx, types.NamespacedName{Name: namespace}, &ns); apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	for _, ref := range ns.OwnerReferences {
+		if ref.Kind == "Paas" && ref.Controller != nil && *ref.Controller && ref.Name == paas.Name &&
+			(paas.UID == "" || ref.UID == paas.UID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// validateSubject returns an error when name is not allowed by validationRE, or when no validationRE is configured
+func validateSubject(validationRE *regexp.Regexp, path *field.Path, name string) []*field.Error {
+	if validationRE == nil {
+		return []*field.Error{field.Forbidden(path, "subjects of this kind are not allowed by the PaasConfig")}
+	}
+	if !validationRE.MatchString(name) {
+		return []*field.Error{field.Invalid(
+			path,
+			name,
+			fmt.Sprintf("subject does not match configured validation regex `%s`", validationRE.String()),
+		)}
+	}
+	return nil
+}
+
+// validateExpiries returns an error for every expiry of an item which is not in items, and for every expiry which
+// lies beyond the maximum window of the PaasConfig
+func validateExpiries(
+	conf v1alpha2.PaasConfig,
+	path *field.Path,
+	items []string,
+	expiries map[string]metav1.Time,
+) (errs []*field.Error) {
+	maxWindow := conf.Spec.GroupExpiry.MaxWindow()
+	latest := time.Now().Add(maxWindow)
+	for item, expiry := range expiries {
+		if !slices.Contains(items, item) {
+			errs = append(errs, field.Invalid(path.Key(item), item, "expiry is not in the group"))
+		} else if maxWindow > 0 && expiry.After(latest) {
+			errs = append(errs, field.Invalid(
+				path.Key(item),
+				expiry.Format(time.RFC3339),
+				fmt.Sprintf("expiry lies more than %d hours in the future", conf.Spec.GroupExpiry.MaxHours),
+			))
+		}
+	}
+	return errs
+}
+
 func validatePaasSecrets(
 	ctx context.Context,
 	k8sClient client.Client,
@@ -488,7 +672,7 @@ func (*PaasCustomValidator) validateGroups(
 			}
 		}
 		for i, role := range grp.Roles {
-			if _, exists := conf.Spec.RoleMappings[role]; !exists {
+			if _, exists := conf.Spec.RoleMappings[role]; !exists && !conf.Spec.ScopedRoleMappings.Defines(role) {
 				errs = append(errs, field.Invalid(
 					field.NewPath(pathSpec).Child("groups").Key(key).Child("roles").Index(i),
 					role,
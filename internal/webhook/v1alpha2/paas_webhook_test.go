@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/belastingdienst/opr-paas-cli/v2/pkg/crypt"
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
@@ -652,6 +653,25 @@ var _ = Describe("Paas Webhook", Ordered, func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("Should allow roles which are only defined in scoped role mappings", func() {
+			latestConf := &v1alpha2.PaasConfig{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: conf.Name}, latestConf)
+			Expect(err).To(Not(HaveOccurred()))
+			latestConf.Spec.RoleMappings = v1alpha2.ConfigRoleMappings{
+				"existing": []string{"admin"},
+			}
+			latestConf.Spec.ScopedRoleMappings = v1alpha2.ConfigScopedRoleMappings{
+				{Role: "scoped", ClusterRoles: []string{"view"}},
+			}
+			err = k8sClient.Update(ctx, latestConf)
+			Expect(err).To(Not(HaveOccurred()))
+			obj = &v1alpha2.Paas{Spec: v1alpha2.PaasSpec{Groups: map[string]v1alpha2.PaasGroup{
+				"foo": {Roles: []string{"scoped"}},
+			}}}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
 		It("Should warn when quota limits are set higher than requests", func() {
 			// Update PaasConfig
 			latestConf := &v1alpha2.PaasConfig{}
@@ -771,6 +791,173 @@ var _ = Describe("Paas Webhook", Ordered, func() {
 				}
 			}
 		})
+		It("Should handle groups with users for the Virtual group backend", func() {
+			for users, expectErr := range map[v1alpha2.ConfigGroupUsersPolicy]bool{
+				"":                                  true,
+				v1alpha2.ConfigGroupUsersReject:     true,
+				v1alpha2.ConfigGroupUsersMapToUsers: false,
+			} {
+				latestConf := &v1alpha2.PaasConfig{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: conf.Name}, latestConf)
+				Expect(err).To(Not(HaveOccurred()))
+				latestConf.Spec.GroupBackend = v1alpha2.ConfigGroupBackend{
+					Type:  v1alpha2.ConfigGroupBackendVirtual,
+					Users: users,
+				}
+				err = k8sClient.Update(ctx, latestConf)
+				Expect(err).To(Not(HaveOccurred()))
+				obj = &v1alpha2.Paas{
+					Spec: v1alpha2.PaasSpec{
+						Groups: map[string]v1alpha2.PaasGroup{
+							"foo": {Users: []string{"bar"}},
+							"baz": {Query: "CN=baz,OU=org_unit,DC=example,DC=org", Users: []string{"bar"}},
+						},
+					},
+				}
+				_, err = validator.ValidateCreate(ctx, obj)
+				if expectErr {
+					Expect(err).To(MatchError(SatisfyAll(
+						ContainSubstring("spec.groups[foo].users: Invalid value"),
+						ContainSubstring("groups with users are not supported by the Virtual group backend"),
+					)))
+					Expect(err).NotTo(MatchError(ContainSubstring("spec.groups[baz]")))
+				} else {
+					Expect(err).NotTo(HaveOccurred())
+				}
+			}
+		})
+		It("Should deny expiries of unknown users and expiries beyond the maximum window", func() {
+			latestConf := &v1alpha2.PaasConfig{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: conf.Name}, latestConf)
+			Expect(err).To(Not(HaveOccurred()))
+			latestConf.Spec.GroupExpiry = v1alpha2.ConfigGroupExpiry{MaxHours: 24}
+			err = k8sClient.Update(ctx, latestConf)
+			Expect(err).To(Not(HaveOccurred()))
+
+			soon := metav1.NewTime(time.Now().Add(time.Hour))
+			obj = &v1alpha2.Paas{
+				Spec: v1alpha2.PaasSpec{
+					Groups: map[string]v1alpha2.PaasGroup{
+						"foo": {
+							Users: []string{"bar"},
+							UserExpiries: map[string]metav1.Time{
+								"bar":     soon,
+								"unknown": soon,
+							},
+						},
+					},
+				},
+			}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(
+				`spec.groups[foo].userExpiries[unknown]: Invalid value: "unknown": expiry is not in the group`)))
+			Expect(err).NotTo(MatchError(ContainSubstring("spec.groups[foo].userExpiries[bar]")))
+
+			obj.Spec.Groups["foo"] = v1alpha2.PaasGroup{
+				Users:        []string{"bar"},
+				UserExpiries: map[string]metav1.Time{"bar": metav1.NewTime(time.Now().Add(48 * time.Hour))},
+			}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(SatisfyAll(
+				ContainSubstring("spec.groups[foo].userExpiries[bar]: Invalid value"),
+				ContainSubstring("expiry lies more than 24 hours in the future"),
+			)))
+		})
+		It("Should only allow namespace retention policies which are allowed by the PaasConfig", func() {
+			obj = &v1alpha2.Paas{Spec: v1alpha2.PaasSpec{
+				NamespaceRetention: &v1alpha2.NamespaceRetention{Policy: v1alpha2.NamespaceRetentionDelete},
+			}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(SatisfyAll(
+				ContainSubstring("spec.namespaceRetention.policy: Forbidden"),
+				ContainSubstring("namespace retention policy Delete is not allowed by the PaasConfig"),
+			)))
+
+			latestConf := &v1alpha2.PaasConfig{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: conf.Name}, latestConf)).To(Succeed())
+			latestConf.Spec.AllowedNamespaceRetentionPolicies = []v1alpha2.NamespaceRetentionPolicy{
+				v1alpha2.NamespaceRetentionDelete,
+				v1alpha2.NamespaceRetentionDelayedDelete,
+			}
+			Expect(k8sClient.Update(ctx, latestConf)).To(Succeed())
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.NamespaceRetention = &v1alpha2.NamespaceRetention{Policy: v1alpha2.NamespaceRetentionDelayedDelete}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(SatisfyAll(
+				ContainSubstring("spec.namespaceRetention.delayHours: Invalid value: 0"),
+				ContainSubstring("must be larger than 0 for the DelayedDelete policy"),
+			)))
+		})
+		It("Should only allow service accounts and users which match the allow-list", func() {
+			obj = &v1alpha2.Paas{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-paas"},
+				Spec: v1alpha2.PaasSpec{
+					Groups: map[string]v1alpha2.PaasGroup{
+						"foo": {
+							ServiceAccounts: []v1alpha2.PaasServiceAccount{
+								{Name: "pipeline"},
+								{Name: "deployer", Namespace: "my-paas-cicd"},
+							},
+							UserSubjects: []string{"robot"},
+						},
+					},
+					Namespaces: v1alpha2.PaasNamespaces{"cicd": {}},
+					Quota:      quota.Quota{corev1.ResourceLimitsCPU: resource.MustParse("1")},
+				},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(SatisfyAll(
+				ContainSubstring("spec.groups[foo].serviceAccounts[0].name: Forbidden"),
+				ContainSubstring("spec.groups[foo].userSubjects[0]: Forbidden"),
+			)))
+
+			latestConf := &v1alpha2.PaasConfig{}
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: conf.Name}, latestConf)
+			Expect(err).To(Not(HaveOccurred()))
+			latestConf.Spec.Validations = v1alpha2.PaasConfigValidations{"paas": {
+				"serviceAccountSubject": "^(pipeline|deployer)$",
+				"userSubject":           "^robot$",
+			}}
+			err = k8sClient.Update(ctx, latestConf)
+			Expect(err).To(Not(HaveOccurred()))
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.Groups["foo"] = v1alpha2.PaasGroup{
+				ServiceAccounts: []v1alpha2.PaasServiceAccount{{Name: "builder", Namespace: "other-paas-cicd"}},
+			}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(SatisfyAll(
+				ContainSubstring("service account namespace is not a namespace of this Paas"),
+				ContainSubstring("subject does not match configured validation regex"),
+			)))
+
+			// Namespaces which are merely prefixed with the name of the Paas may belong to another Paas
+			otherPaas := v1alpha2.Paas{ObjectMeta: metav1.ObjectMeta{Name: "my-paas-bar", UID: "my-paas-bar-uid"}}
+			createPaasNamespace(k8sClient, otherPaas, "my-paas-bar-cicd")
+			obj.Spec.Groups["foo"] = v1alpha2.PaasGroup{
+				ServiceAccounts: []v1alpha2.PaasServiceAccount{
+					{Name: "deployer", Namespace: "my-paas-bar-cicd"},
+					{Name: "deployer", Namespace: "my-paas-missing"},
+				},
+			}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(SatisfyAll(
+				ContainSubstring("serviceAccounts[0].namespace: Invalid value: \"my-paas-bar-cicd\""),
+				ContainSubstring("serviceAccounts[1].namespace: Invalid value: \"my-paas-missing\""),
+			)))
+
+			// Namespaces controlled by the Paas (e.g. of a PaasNS) are allowed
+			obj.UID = "my-paas-uid"
+			createPaasNamespace(k8sClient, *obj, "my-paas-from-paasns")
+			obj.Spec.Groups["foo"] = v1alpha2.PaasGroup{
+				ServiceAccounts: []v1alpha2.PaasServiceAccount{{Name: "deployer", Namespace: "my-paas-from-paasns"}},
+			}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
 		Context("quota name validation", func() {
 			var (
 				validResourceKeys = []string{
@@ -10,14 +10,17 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
 
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v5/internal/logging"
+	paasquota "github.com/belastingdienst/opr-paas/v5/pkg/quota"
 	"github.com/belastingdienst/opr-paas/v5/pkg/templating"
 	k8sv1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -180,6 +183,13 @@ func validatePaasConfigSpec(
 	allErrs = append(allErrs, validateConfigCapabilityNames(spec, childPath)...)
 	allErrs = append(allErrs, validateConfigCapabilities(spec.Capabilities, quotaRE, childPath)...)
 	allErrs = append(allErrs, validateTemplatingFields(spec.Templating, childPath)...)
+	allErrs = append(allErrs, validateComponentsDebug(spec.ComponentsDebug, childPath)...)
+	allErrs = append(allErrs, validateBaselineResources(spec.BaselineResources, childPath)...)
+	allErrs = append(allErrs, validateQuotaBackend(spec, childPath)...)
+	allErrs = append(allErrs, validateGroupBackend(spec.GroupBackend, childPath)...)
+	allErrs = append(allErrs, validateGroupSync(spec.GroupSync, childPath)...)
+	allErrs = append(allErrs, validateNamespaceRetention(spec.NamespaceRetention,
+		childPath.Child("namespaceRetention"))...)
 
 	if len(allErrs) > 0 {
 		logger.Error().Strs(
@@ -274,10 +284,27 @@ func validateConfigCapability(name string, capability v1alpha2.ConfigCapability,
 	allErrs = append(allErrs, validateAllowedQuotas(capability.QuotaSettings, quotaRE, childPath)...)
 	allErrs = append(allErrs, validateConfigQuotaSettings(capability.QuotaSettings, childPath)...)
 	allErrs = append(allErrs, validateConfigCustomFields(capability.CustomFields, childPath)...)
+	allErrs = append(allErrs, validateConfigPermissionScopes(capability, childPath)...)
 
 	return allErrs
 }
 
+// validateConfigPermissionScopes ensures that scopes are only set for roles of the permissions of a capability
+func validateConfigPermissionScopes(capability v1alpha2.ConfigCapability, rootPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	roles := append(capability.DefaultPermissions.Roles(), capability.ExtraPermissions.Roles()...)
+	for role := range capability.PermissionScopes {
+		if !slices.Contains(roles, role) {
+			allErrs = append(allErrs, field.Invalid(
+				rootPath.Child("permission_scopes").Key(role),
+				role,
+				"role is not in the default or extra permissions of this capability",
+			))
+		}
+	}
+	return allErrs
+}
+
 func validateConfigQuotaSettings(
 	qs v1alpha2.ConfigQuotaSettings,
 	rootPath *field.Path,
@@ -287,6 +314,7 @@ func validateConfigQuotaSettings(
 
 	allErrs = append(allErrs, validateConfigDefQuota(qs, childPath)...)
 	allErrs = append(allErrs, validateConfigQuotaMinMax(qs, childPath)...)
+	allErrs = append(allErrs, validateConfigQuotaAggregations(qs, childPath)...)
 
 	if qs.External() {
 		return allErrs
@@ -315,6 +343,33 @@ func validateConfigQuotaSettings(
 	return allErrs
 }
 
+// validateConfigQuotaAggregations ensures that all quota aggregations use a registered strategy
+func validateConfigQuotaAggregations(qs v1alpha2.ConfigQuotaSettings, childPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateConfigQuotaAggregation(qs.Aggregation, childPath.Child("aggregation"))...)
+	for resource, aggregation := range qs.ResourceAggregations {
+		allErrs = append(allErrs, validateConfigQuotaAggregation(
+			aggregation,
+			childPath.Child("resource_aggregations").Key(string(resource)),
+		)...)
+	}
+	return allErrs
+}
+
+func validateConfigQuotaAggregation(aggregation v1alpha2.ConfigQuotaAggregation, path *field.Path) field.ErrorList {
+	if aggregation.Strategy == "" {
+		return nil
+	}
+	strategies := paasquota.AggregationStrategies()
+	if !slices.Contains(strategies, aggregation.Strategy) {
+		return field.ErrorList{field.NotSupported(path.Child("strategy"), aggregation.Strategy, strategies)}
+	}
+	if aggregation.Strategy == paasquota.AggregationPercentile && aggregation.Percentile == 0 {
+		return field.ErrorList{field.Required(path.Child("percentile"), "required for the Percentile strategy")}
+	}
+	return nil
+}
+
 func validateAllowedQuotas(
 	qs v1alpha2.ConfigQuotaSettings,
 	quotaRE *regexp.Regexp,
@@ -493,6 +548,10 @@ func validateTemplatingFields(
 		"groupLabels":             templatingConfig.GroupLabels,
 		"namespaceLabels":         templatingConfig.NamespaceLabels,
 		"roleBindingLabels":       templatingConfig.RoleBindingLabels,
+		"clusterQuotaAnnotations": templatingConfig.ClusterQuotaAnnotations,
+		"groupAnnotations":        templatingConfig.GroupAnnotations,
+		"namespaceAnnotations":    templatingConfig.NamespaceAnnotations,
+		"roleBindingAnnotations":  templatingConfig.RoleBindingAnnotations,
 	} {
 		allErrs = append(allErrs, validateTemplatingField(resourceType, childPath.Child(name))...)
 	}
@@ -573,6 +632,90 @@ func validateValidationFields(
 	return allErrs
 }
 
+// validateComponentsDebug ensures that debugging is only configured for known logging components
+func validateComponentsDebug(componentsDebug map[string]bool, rootPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	childPath := rootPath.Child("components_debug")
+	for name := range componentsDebug {
+		if !logging.IsComponentName(name) {
+			allErrs = append(allErrs, field.NotSupported(childPath.Key(name), name, logging.ComponentNames()))
+		}
+	}
+	return allErrs
+}
+
+// validateBaselineResources ensures that baseline resources have a name which can be used as label value, and a
+// template which can be parsed
+func validateBaselineResources(
+	baselineResources map[string]v1alpha2.ConfigBaselineResource,
+	rootPath *field.Path,
+) field.ErrorList {
+	var allErrs field.ErrorList
+	childPath := rootPath.Child("baselineResources")
+	for name, baseline := range baselineResources {
+		for _, msg := range validation.IsValidLabelValue(name) {
+			allErrs = append(allErrs, field.Invalid(childPath.Key(name), name, msg))
+		}
+		err := templating.NewTemplater(v1alpha2.Paas{}, v1alpha2.PaasConfig{}).Verify(name, baseline.Template)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(
+				childPath.Key(name).Child("template"),
+				baseline.Template,
+				err.Error(),
+			))
+		}
+	}
+	return allErrs
+}
+
+// validateQuotaBackend ensures that no capabilities have cluster-wide quotas when the ResourceQuota backend is used,
+// since ResourceQuotas cannot be shared between Paas'es
+func validateQuotaBackend(spec v1alpha2.PaasConfigSpec, rootPath *field.Path) field.ErrorList {
+	if spec.QuotaBackend.GetType() != v1alpha2.ConfigQuotaBackendResourceQuota {
+		return nil
+	}
+	var allErrs field.ErrorList
+	for name, capability := range spec.Capabilities {
+		if capability.QuotaSettings.Clusterwide {
+			allErrs = append(allErrs, field.Invalid(
+				rootPath.Child("capabilities").Key(name).Child("quotasettings").Child("clusterwide"),
+				capability.QuotaSettings.Clusterwide,
+				"cluster-wide quotas are not supported by the ResourceQuota quota backend",
+			))
+		}
+	}
+	return allErrs
+}
+
+// validateGroupBackend ensures that the name template of the group backend can be parsed
+func validateGroupBackend(backend v1alpha2.ConfigGroupBackend, rootPath *field.Path) field.ErrorList {
+	if backend.NameTemplate == "" {
+		return nil
+	}
+	err := templating.NewTemplater(v1alpha2.Paas{}, v1alpha2.PaasConfig{}).Verify("groupName", backend.NameTemplate)
+	if err != nil {
+		return field.ErrorList{field.Invalid(
+			rootPath.Child("groupBackend").Child("nameTemplate"),
+			backend.NameTemplate,
+			err.Error(),
+		)}
+	}
+	return nil
+}
+
+// validateNamespaceRetention ensures that a delay is set for the DelayedDelete namespace retention policy, since
+// without a delay obsolete namespaces would be deleted immediately
+func validateNamespaceRetention(retention v1alpha2.NamespaceRetention, path *field.Path) field.ErrorList {
+	if retention.GetPolicy() != v1alpha2.NamespaceRetentionDelayedDelete || retention.DelayHours > 0 {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(
+		path.Child("delayHours"),
+		retention.DelayHours,
+		"must be larger than 0 for the DelayedDelete policy",
+	)}
+}
+
 // Convert field.ErrorList to a slice of strings for logging purposes
 func formatFieldErrors(allErrs field.ErrorList) []string {
 	var errs []string
@@ -600,3 +743,22 @@ func validateMapKeysAgainstRegex(
 	}
 	return allErrs
 }
+
+// validateGroupSync ensures that the data templates of the group sync configuration have keys which can be used in a
+// ConfigMap or Secret, and templates which can be parsed
+func validateGroupSync(groupSync *v1alpha2.ConfigGroupSync, rootPath *field.Path) field.ErrorList {
+	if groupSync == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	childPath := rootPath.Child("groupSync").Child("data")
+	for name, tpl := range groupSync.Data {
+		for _, msg := range validation.IsConfigMapKey(name) {
+			allErrs = append(allErrs, field.Invalid(childPath.Key(name), name, msg))
+		}
+		if err := templating.NewTemplater(v1alpha2.Paas{}, v1alpha2.PaasConfig{}).Verify(name, tpl); err != nil {
+			allErrs = append(allErrs, field.Invalid(childPath.Key(name), tpl, err.Error()))
+		}
+	}
+	return allErrs
+}
@@ -95,6 +95,133 @@ var _ = Describe("Creating a PaasConfig", Ordered, func() {
 				Expect(err.Error()).To(ContainSubstring(`failed to compile validation regexp for paas.groupName`))
 			})
 		})
+		Context("with debugging for logging components", func() {
+			It("should allow known components", func() {
+				obj.Spec.ComponentsDebug = map[string]bool{"paas_controller": true, "secret_controller": false}
+				warn, err := validator.ValidateCreate(ctx, obj)
+				Expect(warn, err).Error().NotTo(HaveOccurred())
+			})
+			It("should deny unknown components", func() {
+				obj.Spec.ComponentsDebug = map[string]bool{"paas_controller": true, "paas_kontroller": true}
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(err).Error().To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(`spec.components_debug[paas_kontroller]: Unsupported value`))
+				Expect(err.Error()).NotTo(ContainSubstring(`components_debug[paas_controller]`))
+			})
+		})
+		Context("with baseline resources", func() {
+			It("should allow valid baseline resources", func() {
+				obj.Spec.BaselineResources = map[string]v1alpha2.ConfigBaselineResource{
+					"limits": {Template: "kind: LimitRange\nmetadata:\n  name: {{ .Paas.Name }}"},
+				}
+				warn, err := validator.ValidateCreate(ctx, obj)
+				Expect(warn, err).Error().NotTo(HaveOccurred())
+			})
+			It("should deny invalid names and templates", func() {
+				obj.Spec.BaselineResources = map[string]v1alpha2.ConfigBaselineResource{
+					"no/label-value": {Template: "kind: LimitRange"},
+					"broken":         {Template: "{{ .Paas.Name }"},
+				}
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(err).Error().To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(`spec.baselineResources[no/label-value]: Invalid value`))
+				Expect(err.Error()).To(ContainSubstring(`spec.baselineResources[broken].template: Invalid value`))
+			})
+		})
+		Context("with the ResourceQuota quota backend", func() {
+			BeforeEach(func() {
+				obj.Spec.QuotaBackend = v1alpha2.ConfigQuotaBackend{Type: v1alpha2.ConfigQuotaBackendResourceQuota}
+			})
+			It("should allow capabilities without cluster-wide quotas", func() {
+				obj.Spec.Capabilities = v1alpha2.ConfigCapabilities{"argocd": v1alpha2.ConfigCapability{}}
+				warn, err := validator.ValidateCreate(ctx, obj)
+				Expect(warn, err).Error().NotTo(HaveOccurred())
+			})
+			It("should deny capabilities with cluster-wide quotas", func() {
+				obj.Spec.Capabilities = v1alpha2.ConfigCapabilities{"tekton": v1alpha2.ConfigCapability{
+					QuotaSettings: v1alpha2.ConfigQuotaSettings{Clusterwide: true},
+				}}
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(err).Error().To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(
+					`spec.capabilities[tekton].quotasettings.clusterwide: Invalid value`))
+			})
+		})
+		Context("with quota aggregations", func() {
+			It("should allow registered strategies", func() {
+				obj.Spec.Capabilities = v1alpha2.ConfigCapabilities{"tekton": v1alpha2.ConfigCapability{
+					QuotaSettings: v1alpha2.ConfigQuotaSettings{
+						Clusterwide: true,
+						Aggregation: v1alpha2.ConfigQuotaAggregation{Strategy: "LargestN", N: 3},
+						ResourceAggregations: map[corev1.ResourceName]v1alpha2.ConfigQuotaAggregation{
+							"requests.storage": {Strategy: "Percentile", Percentile: 90},
+						},
+					},
+				}}
+				warn, err := validator.ValidateCreate(ctx, obj)
+				Expect(warn, err).Error().NotTo(HaveOccurred())
+			})
+			It("should deny unknown strategies and a Percentile strategy without percentile", func() {
+				obj.Spec.Capabilities = v1alpha2.ConfigCapabilities{"tekton": v1alpha2.ConfigCapability{
+					QuotaSettings: v1alpha2.ConfigQuotaSettings{
+						Clusterwide: true,
+						Aggregation: v1alpha2.ConfigQuotaAggregation{Strategy: "Average"},
+						ResourceAggregations: map[corev1.ResourceName]v1alpha2.ConfigQuotaAggregation{
+							"requests.storage": {Strategy: "Percentile"},
+						},
+					},
+				}}
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(err).Error().To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(
+					`spec.capabilities[tekton].quotasettings.aggregation.strategy: Unsupported value: "Average"`))
+				Expect(err.Error()).To(ContainSubstring(
+					`spec.capabilities[tekton].quotasettings.resource_aggregations[requests.storage].percentile: ` +
+						`Required`))
+			})
+		})
+		Context("with the Virtual group backend", func() {
+			It("should allow a valid name template", func() {
+				obj.Spec.GroupBackend = v1alpha2.ConfigGroupBackend{
+					Type:         v1alpha2.ConfigGroupBackendVirtual,
+					NameTemplate: "oidc:{{ .Group.Key }}",
+				}
+				warn, err := validator.ValidateCreate(ctx, obj)
+				Expect(warn, err).Error().NotTo(HaveOccurred())
+			})
+			It("should deny an invalid name template", func() {
+				obj.Spec.GroupBackend = v1alpha2.ConfigGroupBackend{
+					Type:         v1alpha2.ConfigGroupBackendVirtual,
+					NameTemplate: "oidc:{{ .Group.Key }",
+				}
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(err).Error().To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(`spec.groupBackend.nameTemplate: Invalid value`))
+			})
+		})
+		Context("with group sync", func() {
+			It("should allow valid data templates", func() {
+				obj.Spec.GroupSync = &v1alpha2.ConfigGroupSync{
+					Target: v1alpha2.NamespacedName{Name: "ldap-groups", Namespace: "paas-system"},
+					Data:   map[string]string{"whitelist.txt": "{{ .Groups.AsString }}"},
+				}
+				warn, err := validator.ValidateCreate(ctx, obj)
+				Expect(warn, err).Error().NotTo(HaveOccurred())
+			})
+			It("should deny invalid keys and templates", func() {
+				obj.Spec.GroupSync = &v1alpha2.ConfigGroupSync{
+					Target: v1alpha2.NamespacedName{Name: "ldap-groups", Namespace: "paas-system"},
+					Data: map[string]string{
+						"no/key": "{{ .Groups.AsString }}",
+						"broken": "{{ .Groups.AsString }",
+					},
+				}
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(err).Error().To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(`spec.groupSync.data[no/key]: Invalid value`))
+				Expect(err.Error()).To(ContainSubstring(`spec.groupSync.data[broken]: Invalid value`))
+			})
+		})
 		Context("and a PaasConfig resource already exists", func() {
 			It("should deny creation", func() {
 				existing := &v1alpha2.PaasConfig{}
@@ -146,6 +273,29 @@ var _ = Describe("Creating a PaasConfig", Ordered, func() {
 				Expect(err).Error().To(Not(HaveOccurred()))
 			})
 		})
+		Context("having a capability defined with permission scopes", func() {
+			It("should only allow scopes for roles of the permissions of the capability", func() {
+				obj.Spec.Capabilities = v1alpha2.ConfigCapabilities{
+					"scoped": v1alpha2.ConfigCapability{
+						DefaultPermissions: v1alpha2.ConfigCapPerm{"sa": {"view"}},
+						ExtraPermissions:   v1alpha2.ConfigCapPerm{"sa": {"edit"}},
+						PermissionScopes: map[string]v1alpha2.ConfigPermissionScope{
+							"view": v1alpha2.ConfigPermissionScopeNamespace,
+							"edit": v1alpha2.ConfigPermissionScopePaas,
+						},
+					},
+				}
+				_, err := validator.ValidateCreate(ctx, obj)
+				Expect(err).NotTo(HaveOccurred())
+
+				obj.Spec.Capabilities["scoped"].PermissionScopes["admin"] = v1alpha2.ConfigPermissionScopePaas
+				_, err = validator.ValidateCreate(ctx, obj)
+				Expect(err).To(MatchError(SatisfyAll(
+					ContainSubstring("spec.capabilities[scoped].permission_scopes[admin]"),
+					ContainSubstring("role is not in the default or extra permissions of this capability"),
+				)))
+			})
+		})
 		Context("having a capability defined with a custom_field", func() {
 			It("should verify Validation field to be valid and default to meet validation", func() {
 				tests := []struct {
@@ -265,6 +415,10 @@ var _ = Describe("Creating a PaasConfig", Ordered, func() {
 						NamespaceLabels:         v1alpha2.ConfigTemplatingItem{keyName: test.template},
 						ClusterQuotaLabels:      v1alpha2.ConfigTemplatingItem{keyName: test.template},
 						RoleBindingLabels:       v1alpha2.ConfigTemplatingItem{keyName: test.template},
+						ClusterQuotaAnnotations: v1alpha2.ConfigTemplatingItem{keyName: test.template},
+						GroupAnnotations:        v1alpha2.ConfigTemplatingItem{keyName: test.template},
+						NamespaceAnnotations:    v1alpha2.ConfigTemplatingItem{keyName: test.template},
+						RoleBindingAnnotations:  v1alpha2.ConfigTemplatingItem{keyName: test.template},
 					}
 					_, err := validator.ValidateCreate(ctx, obj)
 					if test.valid {
@@ -277,6 +431,10 @@ var _ = Describe("Creating a PaasConfig", Ordered, func() {
 							"groupLabels",
 							"namespaceLabels",
 							"roleBindingLabels",
+							"clusterQuotaAnnotations",
+							"groupAnnotations",
+							"namespaceAnnotations",
+							"roleBindingAnnotations",
 						} {
 							Expect(err.Error()).To(ContainSubstring(
 								`spec.templating.%s[%s].template: Invalid value: "%s": template: %s`,
@@ -445,6 +603,18 @@ var _ = Describe("Updating a PaasConfig", Ordered, func() {
 				Expect(warn).To(BeEmpty())
 			})
 		})
+		Context("having the DelayedDelete namespace retention policy without a delay", func() {
+			It("should raise an error", func() {
+				obj.Spec.NamespaceRetention = v1alpha2.NamespaceRetention{
+					Policy: v1alpha2.NamespaceRetentionDelayedDelete,
+				}
+				_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+				Expect(err).To(MatchError(SatisfyAll(
+					ContainSubstring("spec.namespaceRetention.delayHours: Invalid value: 0"),
+					ContainSubstring("must be larger than 0 for the DelayedDelete policy"),
+				)))
+			})
+		})
 		Context("having a capability with invalid name", func() {
 			It("Should validate capability names against validation in new config", func() {
 				for _, test := range []struct {
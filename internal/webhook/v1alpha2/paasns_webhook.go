@@ -91,6 +91,7 @@ func (v *PaasNSCustomValidator) ValidateCreate(
 		validatePaasNsGroups,
 		validatePaasNsSecrets,
 		validateQuota,
+		validatePaasNsLimits,
 	} {
 		var fieldErrs []*field.Error
 		fieldErrs, err = validator(ctx, v.client, myConfig, *paas, *paasns)
@@ -280,15 +281,101 @@ func validateQuota(
 	return errs, nil
 }
 
+// validatePaasNsLimits returns an error when creating the PaasNS would exceed the PaasNS limits of the PaasConfig
+func validatePaasNsLimits(
+	ctx context.Context,
+	k8sClient client.Client,
+	conf v1alpha2.PaasConfig,
+	paas v1alpha2.Paas,
+	paasns v1alpha2.PaasNS,
+) ([]*field.Error, error) {
+	limits := conf.Spec.PaasNSLimits
+	if limits == (v1alpha2.ConfigPaasNSLimits{}) {
+		return nil, nil
+	}
+	depths, err := paasNamespaceDepths(ctx, k8sClient, conf, paas)
+	if err != nil {
+		return nil, err
+	}
+	var errs []*field.Error
+	// A PaasNS in a namespace which is not (yet) a namespace of the Paas, is not processed and does not count
+	if depth, exists := depths[paasns.Namespace]; exists && limits.DepthExceeded(depth+1) {
+		errs = append(errs, field.Forbidden(
+			field.NewPath("metadata").Child("namespace"),
+			fmt.Sprintf("paasns would exceed the maximum nesting depth of %d", limits.MaxDepth),
+		))
+	}
+	if _, exists := depths[utils.Join(paas.Name, paasns.Name)]; !exists && limits.NamespacesExceeded(len(depths)+1) {
+		errs = append(errs, field.Forbidden(
+			field.NewPath("metadata").Child("name"),
+			fmt.Sprintf("paas %s would exceed the maximum number of %d namespaces", paas.Name, limits.MaxNamespaces),
+		))
+	}
+	return errs, nil
+}
+
+// paasNamespaceDepths returns all namespaces of a Paas, with the nesting depth of the PaasNS'es in these namespaces.
+// Namespaces for `spec.namespaces` and capabilities have depth 0, namespaces for PaasNS'es in these namespaces have
+// depth 1, etc. Every namespace is visited only once, so that PaasNS'es which point back to a parent namespace cannot
+// cause an endless walk.
+func paasNamespaceDepths(
+	ctx context.Context,
+	k8sClient client.Client,
+	conf v1alpha2.PaasConfig,
+	paas v1alpha2.Paas,
+) (map[string]int, error) {
+	var pnsList v1alpha2.PaasNSList
+	if err := k8sClient.List(ctx, &pnsList); err != nil {
+		return nil, fmt.Errorf("could not list paasns'es: %w", err)
+	}
+	pnsNames := map[string][]string{}
+	for _, pns := range pnsList.Items {
+		pnsNames[pns.Namespace] = append(pnsNames[pns.Namespace], pns.Name)
+	}
+
+	depths := map[string]int{}
+	var parents []string
+	for nsName := range paas.Spec.Namespaces {
+		parents = append(parents, utils.Join(paas.Name, nsName))
+	}
+	for capName := range paas.Spec.Capabilities {
+		if capConfig, exists := conf.Spec.Capabilities[capName]; exists && !capConfig.QuotaSettings.External() {
+			parents = append(parents, utils.Join(paas.Name, capName))
+		}
+	}
+	for _, parent := range parents {
+		depths[parent] = 0
+	}
+	for depth := 1; len(parents) > 0; depth++ {
+		var children []string
+		for _, parent := range parents {
+			for _, pnsName := range pnsNames[parent] {
+				child := utils.Join(paas.Name, pnsName)
+				if _, visited := depths[child]; !visited {
+					depths[child] = depth
+					children = append(children, child)
+				}
+			}
+		}
+		parents = children
+	}
+	return depths, nil
+}
+
 func paasNStoPaas(ctx context.Context, c client.Client, paasns *v1alpha2.PaasNS) (paas *v1alpha2.Paas, err error) {
+	return namespaceToPaas(ctx, c, paasns.GetNamespace())
+}
+
+// namespaceToPaas returns the Paas which owns a namespace, e.g. the namespace of a PaasNS or QuotaRequest
+func namespaceToPaas(ctx context.Context, c client.Client, namespace string) (paas *v1alpha2.Paas, err error) {
 	var ns corev1.Namespace
 	ctx, logger := logging.GetLogComponent(ctx, logging.WebhookPaasNSComponentV2)
 	if err = c.Get(
 		context.Background(),
-		types.NamespacedName{Name: paasns.GetNamespace()},
+		types.NamespacedName{Name: namespace},
 		&ns,
 	); err != nil {
-		logger.Error().Msgf("unable to get namespace where paasns resides: %s", err.Error())
+		logger.Error().Msgf("unable to get namespace %s: %s", namespace, err.Error())
 		return nil, err
 	}
 	var paasNames []string
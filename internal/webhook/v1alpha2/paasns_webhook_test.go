@@ -349,6 +349,53 @@ var _ = Describe("PaasNS Webhook", Ordered, func() {
 		})
 	})
 
+	Context("When creating a PaasNS with PaasNS limits configured", func() {
+		const (
+			limitsPaasName = "limits-paas"
+			limitsNs       = limitsPaasName + "-ns1"
+			nestedNs       = limitsPaasName + "-nested"
+		)
+		setLimits := func(limits v1alpha2.ConfigPaasNSLimits) {
+			conf.Spec.PaasNSLimits = limits
+			Expect(fakeClient.Update(ctx, &conf)).To(Succeed())
+		}
+
+		BeforeAll(func() {
+			limitsPaas := paas.DeepCopy()
+			limitsPaas.ObjectMeta = metav1.ObjectMeta{Name: limitsPaasName, UID: limitsPaasName + "-uid"}
+			limitsPaas.Spec.Namespaces = v1alpha2.PaasNamespaces{"ns1": v1alpha2.PaasNamespace{}}
+			Expect(fakeClient.Create(ctx, limitsPaas)).To(Succeed())
+			createPaasNamespace(fakeClient, *limitsPaas, limitsNs)
+			createPaasNamespace(fakeClient, *limitsPaas, nestedNs)
+			Expect(fakeClient.Create(ctx, &v1alpha2.PaasNS{
+				ObjectMeta: metav1.ObjectMeta{Name: "nested", Namespace: limitsNs},
+			})).To(Succeed())
+		})
+		BeforeEach(func() {
+			obj.Namespace = nestedNs
+			obj.Spec = v1alpha2.PaasNSSpec{}
+		})
+
+		It("should allow creation within limits", func() {
+			setLimits(v1alpha2.ConfigPaasNSLimits{MaxDepth: 2, MaxNamespaces: 3})
+			warn, err := validator.ValidateCreate(ctx, obj)
+			Expect(warn, err).Error().NotTo(HaveOccurred())
+		})
+		It("should deny creation when exceeding the maximum nesting depth", func() {
+			setLimits(v1alpha2.ConfigPaasNSLimits{MaxDepth: 1})
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("paasns would exceed the maximum nesting depth of 1"))
+		})
+		It("should deny creation when exceeding the maximum number of namespaces", func() {
+			setLimits(v1alpha2.ConfigPaasNSLimits{MaxNamespaces: 2})
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(
+				"paas %s would exceed the maximum number of 2 namespaces", limitsPaasName))
+		})
+	})
+
 	Context("When creating a PaasNs but the parent paas doesn't have a quota defined, ", func() {
 		It("should not allow creation of a PaasNS", func() {
 			By("creating a PaasNS for a Paas without a defined Quota block")
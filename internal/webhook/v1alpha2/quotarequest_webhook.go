@@ -0,0 +1,212 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha2
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/internal/config"
+	"github.com/belastingdienst/opr-paas/v5/internal/logging"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupQuotaRequestWebhookWithManager registers the webhook for QuotaRequest in the manager.
+func SetupQuotaRequestWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &v1alpha2.QuotaRequest{}).
+		WithValidator(&QuotaRequestCustomValidator{client: mgr.GetClient()}).
+		Complete()
+}
+
+//revive:disable:line-length-limit
+
+// +kubebuilder:webhook:path=/validate-cpet-belastingdienst-nl-v1alpha2-quotarequest,mutating=false,failurePolicy=fail,sideEffects=None,groups=cpet.belastingdienst.nl,resources=quotarequests,verbs=create;update,versions=v1alpha2,name=vquotarequest-v1alpha2.kb.io,admissionReviewVersions=v1
+
+// QuotaRequestCustomValidator struct is responsible for validating the QuotaRequest resource when it is created, updated, or deleted.
+// +kubebuilder:object:generate=false
+type QuotaRequestCustomValidator struct {
+	client client.Client
+}
+
+//revive:enable:line-length-limit
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type QuotaRequest.
+func (v *QuotaRequestCustomValidator) ValidateCreate(
+	ctx context.Context,
+	quotaRequest *v1alpha2.QuotaRequest,
+) (admission.Warnings, error) {
+	ctx, _ = logging.SetWebhookLogger(ctx, quotaRequest)
+	ctx, logger := logging.GetLogComponent(ctx, logging.WebhookQuotaRequestComponentV2)
+	logger.Info().Msg("starting validation webhook for create")
+	return nil, v.validate(ctx, nil, quotaRequest)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type QuotaRequest.
+func (v *QuotaRequestCustomValidator) ValidateUpdate(
+	ctx context.Context,
+	oldQuotaRequest,
+	newQuotaRequest *v1alpha2.QuotaRequest,
+) (admission.Warnings, error) {
+	ctx, _ = logging.SetWebhookLogger(ctx, newQuotaRequest)
+	ctx, logger := logging.GetLogComponent(ctx, logging.WebhookQuotaRequestComponentV2)
+	if newQuotaRequest.GetDeletionTimestamp() != nil {
+		logger.Info().Msg("quotarequest is being deleted")
+		return nil, nil
+	}
+	logger.Info().Msg("starting validation webhook for update")
+	return nil, v.validate(ctx, oldQuotaRequest, newQuotaRequest)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type QuotaRequest.
+func (*QuotaRequestCustomValidator) ValidateDelete(
+	context.Context,
+	*v1alpha2.QuotaRequest,
+) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate validates a QuotaRequest which is created (oldQuotaRequest is nil) or updated. The spec is validated on
+// creation and on approval, and is immutable. A decision may only be made (once) by a user in the approver group.
+func (v *QuotaRequestCustomValidator) validate(
+	ctx context.Context,
+	oldQuotaRequest *v1alpha2.QuotaRequest,
+	quotaRequest *v1alpha2.QuotaRequest,
+) error {
+	var errs field.ErrorList
+	myConfig, err := config.GetConfig(ctx, v.client)
+	if err != nil {
+		return field.ErrorList{field.InternalError(
+			field.NewPath("paasconfig"),
+			fmt.Errorf("unable to retrieve paasconfig: %s", err),
+		)}.ToAggregate()
+	}
+	if !myConfig.Spec.QuotaRequests.Enabled() {
+		return field.ErrorList{field.Forbidden(
+			field.NewPath("spec"),
+			"quota requests are not enabled, since no approver group is set in the PaasConfig",
+		)}.ToAggregate()
+	}
+
+	decisionChanged := oldQuotaRequest == nil ||
+		oldQuotaRequest.Decision() != quotaRequest.Decision() ||
+		oldQuotaRequest.DecidedBy() != quotaRequest.DecidedBy()
+	if oldQuotaRequest != nil && !equality.Semantic.DeepEqual(oldQuotaRequest.Spec, quotaRequest.Spec) {
+		errs = append(errs, field.Forbidden(field.NewPath("spec"), "spec of a quota request cannot be changed"))
+	}
+	if decisionChanged && quotaRequest.Decision() != "" {
+		errs = append(errs, validateQuotaRequestDecision(ctx, myConfig, oldQuotaRequest, quotaRequest)...)
+	}
+	if oldQuotaRequest == nil || (decisionChanged && quotaRequest.Decision() == v1alpha2.QuotaRequestApproved) {
+		var specErrs field.ErrorList
+		specErrs, err = v.validateQuotaRequestSpec(ctx, myConfig, quotaRequest)
+		if err != nil {
+			return err
+		}
+		errs = append(errs, specErrs...)
+	}
+	return errs.ToAggregate()
+}
+
+// validateQuotaRequestSpec ensures that a QuotaRequest belongs to a Paas (and one of its enabled capabilities), and
+// only requests increases which do not exceed the MaxAllowedSubmittedQuota
+func (v *QuotaRequestCustomValidator) validateQuotaRequestSpec(
+	ctx context.Context,
+	myConfig v1alpha2.PaasConfig,
+	quotaRequest *v1alpha2.QuotaRequest,
+) (field.ErrorList, error) {
+	paas, err := namespaceToPaas(ctx, v.client, quotaRequest.Namespace)
+	if err != nil {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("metadata").Child("namespace"),
+			quotaRequest.Namespace,
+			err.Error(),
+		)}, nil
+	}
+	specPath := field.NewPath("spec")
+	if len(quotaRequest.Spec.Quota) == 0 {
+		return field.ErrorList{field.Required(specPath.Child("quota"), "at least one resource must be requested")}, nil
+	}
+	current := paas.Spec.Quota
+	if capName := quotaRequest.Spec.Capability; capName != "" {
+		capability, exists := paas.Spec.Capabilities[capName]
+		if _, configured := myConfig.Spec.Capabilities[capName]; !exists || !configured {
+			return field.ErrorList{field.Invalid(
+				specPath.Child("capability"),
+				capName,
+				fmt.Sprintf("capability is not enabled in paas %s", paas.Name),
+			)}, nil
+		}
+		current = capability.Quota
+	}
+
+	var errs field.ErrorList
+	for resource, requested := range quotaRequest.Spec.Quota {
+		quotaPath := specPath.Child("quota").Key(string(resource))
+		if value, exists := current[resource]; exists && requested.Cmp(value) <= 0 {
+			errs = append(errs, field.Invalid(
+				quotaPath,
+				requested.String(),
+				fmt.Sprintf("requested quota must be larger than the current quota (%s)", value.String()),
+			))
+		}
+		if maxQuota, exists := myConfig.Spec.MaxAllowedSubmittedQuota.MaxQuota[resource]; exists &&
+			quotaRequest.Spec.Capability == "" && requested.Cmp(maxQuota) > 0 {
+			errs = append(errs, field.Invalid(
+				quotaPath,
+				requested.String(),
+				fmt.Sprintf("quota (%s) cannot be larger than MaxAllowedSubmittedQuota (%s)",
+					resource, maxQuota.String()),
+			))
+		}
+	}
+	return errs, nil
+}
+
+// validateQuotaRequestDecision ensures that a decision on a QuotaRequest is valid, is made only once, and is made by
+// a user in the approver group who signs the decision with their own user name
+func validateQuotaRequestDecision(
+	ctx context.Context,
+	myConfig v1alpha2.PaasConfig,
+	oldQuotaRequest *v1alpha2.QuotaRequest,
+	quotaRequest *v1alpha2.QuotaRequest,
+) field.ErrorList {
+	annotationsPath := field.NewPath("metadata").Child("annotations")
+	decisionPath := annotationsPath.Key(v1alpha2.QuotaRequestDecisionAnnotation)
+	if oldQuotaRequest != nil && oldQuotaRequest.Decision() != "" {
+		return field.ErrorList{field.Forbidden(decisionPath, "the decision on a quota request cannot be changed")}
+	}
+	if !slices.Contains(v1alpha2.QuotaRequestDecisions, quotaRequest.Decision()) {
+		return field.ErrorList{
+			field.NotSupported(decisionPath, quotaRequest.Decision(), v1alpha2.QuotaRequestDecisions),
+		}
+	}
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return field.ErrorList{field.InternalError(decisionPath, err)}
+	}
+	approverGroup := myConfig.Spec.QuotaRequests.ApproverGroup
+	if !slices.Contains(req.UserInfo.Groups, approverGroup) {
+		return field.ErrorList{field.Forbidden(
+			decisionPath,
+			fmt.Sprintf("only users in group %s can approve or reject quota requests", approverGroup),
+		)}
+	}
+	if quotaRequest.DecidedBy() != req.UserInfo.Username {
+		return field.ErrorList{field.Invalid(
+			annotationsPath.Key(v1alpha2.QuotaRequestDecidedByAnnotation),
+			quotaRequest.DecidedBy(),
+			fmt.Sprintf("must be the user name of the approver (%s)", req.UserInfo.Username),
+		)}
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2025, Tax Administration of The Netherlands.
+Licensed under the EUPL 1.2.
+See LICENSE.md for details.
+*/
+
+package v1alpha2
+
+// Excuse Ginkgo use from revive errors
+//revive:disable:dot-imports
+
+import (
+	"context"
+
+	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
+	"github.com/belastingdienst/opr-paas/v5/pkg/quota"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	cl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("QuotaRequest Webhook", Ordered, func() {
+	const (
+		paasName      = "quotarequest-paas"
+		capName       = "argocd"
+		approverGroup = "quota-approvers"
+		approver      = "jane"
+		requestor     = "john"
+	)
+	var (
+		paas       *v1alpha2.Paas
+		obj        *v1alpha2.QuotaRequest
+		oldObj     *v1alpha2.QuotaRequest
+		validator  QuotaRequestCustomValidator
+		conf       v1alpha2.PaasConfig
+		fakeClient cl.Client
+	)
+	userContext := func(username string, groups ...string) context.Context {
+		return admission.NewContextWithRequest(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			UserInfo: authenticationv1.UserInfo{Username: username, Groups: groups},
+		}})
+	}
+	decide := func(qr *v1alpha2.QuotaRequest, decision v1alpha2.QuotaRequestDecision, decidedBy string) {
+		qr.Annotations = map[string]string{
+			v1alpha2.QuotaRequestDecisionAnnotation:  string(decision),
+			v1alpha2.QuotaRequestDecidedByAnnotation: decidedBy,
+		}
+	}
+
+	BeforeAll(func() {
+		paas = &v1alpha2.Paas{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: paasName,
+				UID:  paasName + "-uid",
+			},
+			Spec: v1alpha2.PaasSpec{
+				Requestor: requestor,
+				Capabilities: v1alpha2.PaasCapabilities{
+					capName: v1alpha2.PaasCapability{
+						Quota: quota.Quota{corev1.ResourceLimitsCPU: resource.MustParse("2")},
+					},
+				},
+				Quota: quota.Quota{corev1.ResourceLimitsCPU: resource.MustParse("1")},
+			},
+		}
+
+		scheme := runtime.NewScheme()
+		Expect(v1alpha2.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(paas).
+			Build()
+		validator = QuotaRequestCustomValidator{fakeClient}
+		createPaasNamespace(fakeClient, *paas, paasName)
+	})
+
+	BeforeEach(func() {
+		obj = &v1alpha2.QuotaRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "more-cpu",
+				Namespace: paasName,
+			},
+			Spec: v1alpha2.QuotaRequestSpec{
+				Quota:  quota.Quota{corev1.ResourceLimitsCPU: resource.MustParse("4")},
+				Reason: "we need more cpu",
+			},
+		}
+		oldObj = obj.DeepCopy()
+
+		conf = v1alpha2.PaasConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "paas-config",
+			},
+			Spec: v1alpha2.PaasConfigSpec{
+				Capabilities: v1alpha2.ConfigCapabilities{
+					capName: v1alpha2.ConfigCapability{},
+				},
+				MaxAllowedSubmittedQuota: v1alpha2.ConfigMaxAllowedSubmittedQuota{
+					MaxQuota: quota.Quota{corev1.ResourceLimitsCPU: resource.MustParse("8")},
+				},
+				QuotaRequests: v1alpha2.ConfigQuotaRequests{ApproverGroup: approverGroup},
+			},
+			Status: v1alpha2.PaasConfigStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:    v1alpha2.TypeActivePaasConfig,
+						Status:  metav1.ConditionTrue,
+						Message: "This config is the active config!",
+					},
+				},
+			},
+		}
+		Expect(fakeClient.Create(ctx, &conf)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(fakeClient.Delete(ctx, &conf)).To(Succeed())
+	})
+
+	Context("When creating a QuotaRequest", func() {
+		It("Should allow requesting more quota for the Paas", func() {
+			warn, err := validator.ValidateCreate(ctx, obj)
+			Expect(warn, err).Error().ToNot(HaveOccurred())
+		})
+		It("Should allow requesting more quota for a capability", func() {
+			obj.Spec.Capability = capName
+			obj.Spec.Quota[corev1.ResourceLimitsCPU] = resource.MustParse("16")
+			warn, err := validator.ValidateCreate(ctx, obj)
+			Expect(warn, err).Error().ToNot(HaveOccurred())
+		})
+		It("Should deny when quota requests are not enabled", func() {
+			Expect(fakeClient.Delete(ctx, &conf)).To(Succeed())
+			conf.ResourceVersion = ""
+			conf.Spec.QuotaRequests = v1alpha2.ConfigQuotaRequests{}
+			Expect(fakeClient.Create(ctx, &conf)).To(Succeed())
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("quota requests are not enabled"))
+		})
+		It("Should deny when the namespace does not belong to a Paas", func() {
+			createNamespace(fakeClient, "quotarequest-no-paas")
+			obj.Namespace = "quotarequest-no-paas"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("metadata.namespace"))
+		})
+		It("Should deny an empty quota", func() {
+			obj.Spec.Quota = quota.Quota{}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("at least one resource must be requested"))
+		})
+		It("Should deny a capability which is not enabled", func() {
+			obj.Spec.Capability = "tekton"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("capability is not enabled in paas " + paasName))
+		})
+		It("Should deny a quota which is not an increase", func() {
+			obj.Spec.Quota[corev1.ResourceLimitsCPU] = resource.MustParse("500m")
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("requested quota must be larger than the current quota (1)"))
+		})
+		It("Should deny a quota which exceeds MaxAllowedSubmittedQuota", func() {
+			obj.Spec.Quota[corev1.ResourceLimitsCPU] = resource.MustParse("16")
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cannot be larger than MaxAllowedSubmittedQuota (8)"))
+		})
+		It("Should deny a requestor approving their own QuotaRequest", func() {
+			decide(obj, v1alpha2.QuotaRequestApproved, requestor)
+			_, err := validator.ValidateCreate(userContext(requestor), obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only users in group " + approverGroup))
+		})
+	})
+
+	Context("When updating a QuotaRequest", func() {
+		It("Should deny changing the spec", func() {
+			obj.Spec.Quota[corev1.ResourceLimitsCPU] = resource.MustParse("6")
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec of a quota request cannot be changed"))
+		})
+		It("Should allow an approver to approve", func() {
+			decide(obj, v1alpha2.QuotaRequestApproved, approver)
+			warn, err := validator.ValidateUpdate(userContext(approver, approverGroup), oldObj, obj)
+			Expect(warn, err).Error().ToNot(HaveOccurred())
+		})
+		It("Should allow an approver to reject", func() {
+			decide(obj, v1alpha2.QuotaRequestRejected, approver)
+			warn, err := validator.ValidateUpdate(userContext(approver, approverGroup), oldObj, obj)
+			Expect(warn, err).Error().ToNot(HaveOccurred())
+		})
+		It("Should deny a user outside the approver group", func() {
+			decide(obj, v1alpha2.QuotaRequestApproved, requestor)
+			_, err := validator.ValidateUpdate(userContext(requestor, "developers"), oldObj, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only users in group " + approverGroup))
+		})
+		It("Should deny an approver signing for someone else", func() {
+			decide(obj, v1alpha2.QuotaRequestApproved, "someone-else")
+			_, err := validator.ValidateUpdate(userContext(approver, approverGroup), oldObj, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("must be the user name of the approver (jane)"))
+		})
+		It("Should deny an unsupported decision", func() {
+			decide(obj, "Maybe", approver)
+			_, err := validator.ValidateUpdate(userContext(approver, approverGroup), oldObj, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Unsupported value: \"Maybe\""))
+		})
+		It("Should deny changing a decision", func() {
+			decide(oldObj, v1alpha2.QuotaRequestRejected, approver)
+			decide(obj, v1alpha2.QuotaRequestApproved, approver)
+			_, err := validator.ValidateUpdate(userContext(approver, approverGroup), oldObj, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the decision on a quota request cannot be changed"))
+		})
+		It("Should deny approving a QuotaRequest which is no longer an increase", func() {
+			obj.Spec.Quota[corev1.ResourceLimitsCPU] = resource.MustParse("500m")
+			oldObj = obj.DeepCopy()
+			decide(obj, v1alpha2.QuotaRequestApproved, approver)
+			_, err := validator.ValidateUpdate(userContext(approver, approverGroup), oldObj, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("requested quota must be larger than the current quota"))
+		})
+	})
+})
@@ -0,0 +1,175 @@
+package quota
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+
+	k8sv1 "k8s.io/api/core/v1"
+	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// AggregationOptimal is the largest of the sum of all quantities times the ratio and the sum of the largest two
+	// quantities
+	AggregationOptimal = "Optimal"
+	// AggregationSum is the sum of all quantities
+	AggregationSum = "Sum"
+	// AggregationLargestN is the sum of the largest N quantities
+	AggregationLargestN = "LargestN"
+	// AggregationPercentile is the quantity at a percentile of all quantities (nearest rank)
+	AggregationPercentile = "Percentile"
+	// AggregationMax is the largest quantity
+	AggregationMax = "Max"
+	// AggregationMin is the smallest quantity
+	AggregationMin = "Min"
+)
+
+// AggregationParams holds the parameters of aggregation strategies. Every strategy only uses the parameters it needs.
+type AggregationParams struct {
+	// Ratio is the part of the sum of all quantities which is used by the Optimal strategy
+	Ratio float64
+	// N is the number of largest quantities which are summed by the LargestN strategy
+	N int
+	// Percentile (1-100) is the percentile which is used by the Percentile strategy
+	Percentile int
+}
+
+// AggregationStrategy aggregates the quantities of a resource which were appended for multiple quotas into one
+// quantity. values is never empty, and may not be modified.
+type AggregationStrategy func(values []resourcev1.Quantity, params AggregationParams) resourcev1.Quantity
+
+var (
+	strategiesLock sync.RWMutex
+	strategies     = map[string]AggregationStrategy{
+		AggregationOptimal:    optimal,
+		AggregationSum:        sum,
+		AggregationLargestN:   largestN,
+		AggregationPercentile: percentile,
+		AggregationMax: func(values []resourcev1.Quantity, _ AggregationParams) resourcev1.Quantity {
+			return sortedDesc(values)[0]
+		},
+		AggregationMin: func(values []resourcev1.Quantity, _ AggregationParams) resourcev1.Quantity {
+			return sortedDesc(values)[len(values)-1]
+		},
+	}
+)
+
+// RegisterAggregationStrategy registers an aggregation strategy by name, replacing any strategy with the same name
+func RegisterAggregationStrategy(name string, strategy AggregationStrategy) {
+	strategiesLock.Lock()
+	defer strategiesLock.Unlock()
+	strategies[name] = strategy
+}
+
+// AggregationStrategies returns the names of all registered aggregation strategies, sorted by name
+func AggregationStrategies() []string {
+	strategiesLock.RLock()
+	defer strategiesLock.RUnlock()
+	return slices.Sorted(maps.Keys(strategies))
+}
+
+// Aggregation is an aggregation strategy (by name) along with its parameters
+type Aggregation struct {
+	Strategy string
+	Params   AggregationParams
+}
+
+// Aggregate aggregates values with the strategy of the aggregation. It returns an error when the strategy is not
+// registered.
+func (a Aggregation) Aggregate(values []resourcev1.Quantity) (resourcev1.Quantity, error) {
+	strategiesLock.RLock()
+	strategy, exists := strategies[a.Strategy]
+	strategiesLock.RUnlock()
+	if !exists {
+		return resourcev1.Quantity{}, fmt.Errorf("unknown quota aggregation strategy %s", a.Strategy)
+	}
+	if len(values) == 0 {
+		return resourcev1.MustParse("0"), nil
+	}
+	return strategy(values, a.Params), nil
+}
+
+// Aggregated returns a Quota with for every resource name the quantities which were previously appended, aggregated
+// with the aggregation in overrides for that resource name, or with aggregation when there is none
+func (pcr Quotas) Aggregated(aggregation Aggregation, overrides map[k8sv1.ResourceName]Aggregation) (Quota, error) {
+	quotaResources := make(Quota)
+	for key, values := range pcr.list {
+		resourceAggregation, exists := overrides[key]
+		if !exists {
+			resourceAggregation = aggregation
+		}
+		value, err := resourceAggregation.Aggregate(values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to aggregate %s: %w", key, err)
+		}
+		quotaResources[key] = value
+	}
+	return quotaResources, nil
+}
+
+// AggregatedValues aggregates the quantities which were previously appended like Aggregated, raised to minQuotas and
+// capped by maxQuotas
+func (pcr Quotas) AggregatedValues(
+	aggregation Aggregation,
+	overrides map[k8sv1.ResourceName]Aggregation,
+	minQuotas Quota,
+	maxQuotas Quota,
+) (Quota, error) {
+	aggregated, err := pcr.Aggregated(aggregation, overrides)
+	if err != nil {
+		return nil, err
+	}
+	approaches := NewQuotas()
+	approaches.Append(aggregated)
+	approaches.Append(minQuotas)
+	capped := NewQuotas()
+	capped.Append(approaches.Max())
+	capped.Append(maxQuotas)
+	return capped.Min(), nil
+}
+
+// sortedDesc returns a sorted copy of values, largest first
+func sortedDesc(values []resourcev1.Quantity) []resourcev1.Quantity {
+	sorted := slices.Clone(values)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Value() > sorted[j].Value() })
+	return sorted
+}
+
+func sum(values []resourcev1.Quantity, _ AggregationParams) resourcev1.Quantity {
+	var total resourcev1.Quantity
+	for _, value := range values {
+		total.Add(value)
+	}
+	return total
+}
+
+func largestN(values []resourcev1.Quantity, params AggregationParams) resourcev1.Quantity {
+	n := params.N
+	if n < 1 {
+		n = 2
+	}
+	sorted := sortedDesc(values)
+	return sum(sorted[:min(n, len(sorted))], params)
+}
+
+func percentile(values []resourcev1.Quantity, params AggregationParams) resourcev1.Quantity {
+	p := min(max(params.Percentile, 1), 100)
+	sorted := sortedDesc(values)
+	slices.Reverse(sorted)
+	rank := int(math.Ceil(float64(p) / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+func optimal(values []resourcev1.Quantity, params AggregationParams) resourcev1.Quantity {
+	total := sum(values, params)
+	resized := *(resourcev1.NewQuantity(int64(total.AsApproximateFloat64()*params.Ratio), total.Format))
+	largestTwo := largestN(values, AggregationParams{N: 2})
+	if resized.Value() > largestTwo.Value() {
+		return resized
+	}
+	return largestTwo
+}
@@ -0,0 +1,106 @@
+package quota_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	paasquota "github.com/belastingdienst/opr-paas/v5/pkg/quota"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func newTestQuotas() paasquota.Quotas {
+	quotas := paasquota.NewQuotas()
+	for _, vals := range testQuotas {
+		quotas.Append(vals)
+	}
+	return quotas
+}
+
+func TestPaasQuotas_AggregatedValuesOptimal(t *testing.T) {
+	quotas := newTestQuotas()
+	aggregated, err := quotas.AggregatedValues(
+		paasquota.Aggregation{
+			Strategy: paasquota.AggregationOptimal,
+			Params:   paasquota.AggregationParams{Ratio: ratio},
+		},
+		nil,
+		minQuota,
+		maxQuota,
+	)
+	require.NoError(t, err)
+	optimal := quotas.OptimalValues(ratio, minQuota, maxQuota)
+	assert.Len(t, aggregated, len(optimal))
+	for key, expected := range optimal {
+		actual := aggregated[key]
+		assert.Equal(t, expected.Value(), actual.Value(), "%s should equal OptimalValues", key)
+	}
+}
+
+func TestPaasQuotas_Aggregated(t *testing.T) {
+	quotas := newTestQuotas()
+	aggregated, err := quotas.Aggregated(
+		paasquota.Aggregation{Strategy: paasquota.AggregationSum},
+		map[corev1.ResourceName]paasquota.Aggregation{
+			quotaCPUKey: {
+				Strategy: paasquota.AggregationLargestN,
+				Params:   paasquota.AggregationParams{N: 1},
+			},
+			quotaBlockKey: {
+				Strategy: paasquota.AggregationPercentile,
+				Params:   paasquota.AggregationParams{Percentile: 50},
+			},
+		},
+	)
+	require.NoError(t, err)
+	cpu := aggregated[quotaCPUKey]
+	assert.Equal(t, max_cpu, cpu.MilliValue(), "largest 1 should be the max cpu")
+	mem := aggregated[quotaMemoryKey]
+	assert.Equal(t, resource.BinarySI, mem.Format)
+	assert.Equal(t, sum_memory, mem.Value(), "memory should use the default Sum strategy")
+	block := aggregated[quotaBlockKey]
+	assert.Equal(t, 100*GiB, block.Value())
+}
+
+func TestPaasQuotas_AggregatedStrategies(t *testing.T) {
+	quotas := newTestQuotas()
+	for _, test := range []struct {
+		strategy string
+		params   paasquota.AggregationParams
+		cpu      int64
+	}{
+		{strategy: paasquota.AggregationSum, cpu: sum_cpu},
+		{strategy: paasquota.AggregationLargestN, cpu: largest_two_cpu},
+		{strategy: paasquota.AggregationLargestN, params: paasquota.AggregationParams{N: 5}, cpu: sum_cpu},
+		{strategy: paasquota.AggregationPercentile, params: paasquota.AggregationParams{Percentile: 34}, cpu: 3000},
+		{strategy: paasquota.AggregationPercentile, params: paasquota.AggregationParams{Percentile: 90}, cpu: 6000},
+		{strategy: paasquota.AggregationMax, cpu: max_cpu},
+		{strategy: paasquota.AggregationMin, cpu: min_cpu},
+	} {
+		aggregated, err := quotas.Aggregated(paasquota.Aggregation{Strategy: test.strategy, Params: test.params}, nil)
+		require.NoError(t, err)
+		cpu := aggregated[quotaCPUKey]
+		assert.Equal(t, test.cpu, cpu.MilliValue(), "unexpected cpu for %s %v", test.strategy, test.params)
+	}
+}
+
+func TestPaasQuotas_AggregatedUnknownStrategy(t *testing.T) {
+	_, err := newTestQuotas().Aggregated(paasquota.Aggregation{Strategy: "Average"}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown quota aggregation strategy Average")
+}
+
+func TestRegisterAggregationStrategy(t *testing.T) {
+	const name = "First"
+	paasquota.RegisterAggregationStrategy(name,
+		func(values []resource.Quantity, _ paasquota.AggregationParams) resource.Quantity {
+			return values[0]
+		})
+	assert.Contains(t, paasquota.AggregationStrategies(), name)
+	aggregated, err := newTestQuotas().Aggregated(paasquota.Aggregation{Strategy: name}, nil)
+	require.NoError(t, err)
+	mem := aggregated[quotaMemoryKey]
+	assert.Equal(t, 6*GiB, mem.Value())
+}
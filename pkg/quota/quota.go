@@ -31,6 +31,24 @@ func (pq Quota) Resized(scale float64) (q Quota) {
 	return q
 }
 
+// Divided can be used to divide a quota block into a number of equal parts. Values are rounded down, to millicores for
+// cpu resources and to whole units for all other resources, so that the parts never add up to more than the quota.
+func (pq Quota) Divided(parts int) (q Quota) {
+	if parts < 2 {
+		return pq.DeepCopy()
+	}
+	q = make(Quota)
+	for key, value := range pq {
+		switch key {
+		case corev1.ResourceCPU, corev1.ResourceLimitsCPU, corev1.ResourceRequestsCPU:
+			q[key] = *(resourcev1.NewMilliQuantity(value.MilliValue()/int64(parts), value.Format))
+		default:
+			q[key] = *(resourcev1.NewQuantity(value.Value()/int64(parts), value.Format))
+		}
+	}
+	return q
+}
+
 // DeepCopy is a deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (pq Quota) DeepCopy() Quota {
 	in, out := &pq, &Quota{}
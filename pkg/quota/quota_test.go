@@ -36,3 +36,19 @@ func TestPaasQuotas_QuotaWithDefaults(t *testing.T) {
 	assert.NotEqual(t, defaultedQuotas["requests.cpu"],
 		resourcev1.MustParse("700m"))
 }
+
+func TestPaasQuotas_Divided(t *testing.T) {
+	quota := paasquota.Quota{
+		"limits.cpu":    resourcev1.MustParse("1"),
+		"limits.memory": resourcev1.MustParse("1Gi"),
+		"pods":          resourcev1.MustParse("10"),
+	}
+	divided := quota.Divided(3)
+	cpu, memory, pods := divided["limits.cpu"], divided["limits.memory"], divided["pods"]
+	assert.Equal(t, int64(333), cpu.MilliValue())
+	assert.Equal(t, int64(357913941), memory.Value())
+	assert.Equal(t, int64(3), pods.Value())
+	// Dividing into less than 2 parts returns the quota as is
+	assert.Equal(t, quota, quota.Divided(1))
+	assert.Equal(t, quota, quota.Divided(0))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/belastingdienst/opr-paas/v5/api"
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v5/pkg/fields"
+	"github.com/belastingdienst/opr-paas/v5/pkg/groups"
 )
 
 // PaasUnion is an interface representing either a v1alpha1.Paas or a v1alpha2.Paas
@@ -20,10 +21,31 @@ type PaasUnion interface {
 	v1alpha2.Paas
 }
 
-// Templater is a struct that can hold a Paas and a PaasConfig and can run go-templates using these as input
+// Namespace holds the namespace for which resources are templated
+type Namespace struct {
+	Name       string
+	Kind       v1alpha2.ConfigNamespaceKind
+	Capability string
+}
+
+// Group holds the group of a Paas for which resources are templated
+type Group struct {
+	// Key is the key of the group in the groups of the Paas
+	Key string
+	// Name is the name of the group as it is created by the OpenShift group backend
+	Name  string
+	Query string
+}
+
+// Templater is a struct that can hold a Paas and a PaasConfig and can run go-templates using these as input.
+// When resources are templated for a specific namespace or group, the namespace or group can be used as input too.
+// When resources are templated for all Paas'es, the groups of all Paas'es can be used as input.
 type Templater[P PaasUnion, C api.PaasConfig[S], S any] struct {
-	Paas   P
-	Config C
+	Paas      P
+	Config    C
+	Namespace Namespace
+	Group     Group
+	Groups    groups.Groups
 	// This should not be an exported value
 	extraFuncs template.FuncMap
 }
@@ -45,6 +67,24 @@ func NewTemplater[P PaasUnion, C api.PaasConfig[S], S any](
 	}
 }
 
+// WithNamespace returns a copy of the Templater, which has ns as input for templating namespaced resources
+func (t Templater[P, C, S]) WithNamespace(ns Namespace) Templater[P, C, S] {
+	t.Namespace = ns
+	return t
+}
+
+// WithGroup returns a copy of the Templater, which has group as input for templating resources for a group
+func (t Templater[P, C, S]) WithGroup(group Group) Templater[P, C, S] {
+	t.Group = group
+	return t
+}
+
+// WithGroups returns a copy of the Templater, which has gs as input for templating resources for all Paas'es
+func (t Templater[P, C, S]) WithGroups(gs groups.Groups) Templater[P, C, S] {
+	t.Groups = gs
+	return t
+}
+
 func (t Templater[P, C, S]) getSproutFuncs() (template.FuncMap, error) {
 	var err error
 	handler := sprout.New()
@@ -106,3 +146,17 @@ func (t Templater[P, C, S]) TemplateToMap(name string, templatedText string) (fi
 	}
 	return fields.ElementMap{name: yamlData}, nil
 }
+
+// TemplateItemsToMap runs TemplateToMap for every template in items, where the key is used as name, and returns the
+// merged results as a string map, which can be used as labels or annotations of a resource.
+func (t Templater[P, C, S]) TemplateItemsToMap(items map[string]string) (map[string]string, error) {
+	result := map[string]string{}
+	for name, tpl := range items {
+		templated, err := t.TemplateToMap(name, tpl)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(result, templated.AsLabels())
+	}
+	return result, nil
+}
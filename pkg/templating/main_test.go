@@ -6,6 +6,7 @@ import (
 
 	"github.com/belastingdienst/opr-paas/v5/api/v1alpha2"
 	"github.com/belastingdienst/opr-paas/v5/pkg/fields"
+	"github.com/belastingdienst/opr-paas/v5/pkg/groups"
 	"github.com/belastingdienst/opr-paas/v5/pkg/templating"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -163,3 +164,66 @@ func TestInValidTemplateToMap(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, templated)
 }
+
+func TestTemplateItemsToMap(t *testing.T) {
+	tpl := templating.NewTemplater(paas, paasConfig)
+	templated, err := tpl.TemplateItemsToMap(map[string]string{
+		"openshift.io/display-name": "{{ .Paas.Name }}",
+		"mymap":                     `{"a":"b"}`,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"openshift.io/display-name": paasName,
+		"mymap-a":                   "b",
+	}, templated)
+
+	templated, err = tpl.TemplateItemsToMap(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, templated)
+
+	templated, err = tpl.TemplateItemsToMap(map[string]string{"invalid": "{{ .NotAPaas.Name }}"})
+	assert.Error(t, err)
+	assert.Nil(t, templated)
+}
+
+func TestWithNamespace(t *testing.T) {
+	tpl := templating.NewTemplater(paas, paasConfig)
+	nsTpl := tpl.WithNamespace(templating.Namespace{
+		Name:       "my-ns",
+		Kind:       v1alpha2.ConfigNamespaceKindCapability,
+		Capability: "argocd",
+	})
+	templated, err := nsTpl.TemplateToString("ns",
+		"{{ .Namespace.Name }} {{ .Namespace.Kind }} {{ .Namespace.Capability }}")
+	assert.NoError(t, err)
+	assert.Equal(t, "my-ns Capability argocd", templated)
+
+	templated, err = tpl.TemplateToString("ns", "{{ .Namespace.Name }}")
+	assert.NoError(t, err)
+	assert.Empty(t, templated, "original templater should not be changed")
+}
+
+func TestWithGroup(t *testing.T) {
+	tpl := templating.NewTemplater(paas, paasConfig)
+	groupTpl := tpl.WithGroup(templating.Group{Key: "devs", Name: "my-paas-devs", Query: "CN=devs"})
+	templated, err := groupTpl.TemplateToString("group", "{{ .Group.Key }} {{ .Group.Name }} {{ .Group.Query }}")
+	assert.NoError(t, err)
+	assert.Equal(t, "devs my-paas-devs CN=devs", templated)
+
+	templated, err = tpl.TemplateToString("group", "{{ .Group.Name }}")
+	assert.NoError(t, err)
+	assert.Empty(t, templated, "original templater should not be changed")
+}
+
+func TestWithGroups(t *testing.T) {
+	gs := groups.NewGroups()
+	gs.AddFromStrings([]string{"CN=ops,OU=org", "CN=devs,OU=org"})
+	tpl := templating.NewTemplater(v1alpha2.Paas{}, paasConfig)
+	templated, err := tpl.WithGroups(*gs).TemplateToString("groups", "{{ range .Groups.Keys }}{{ . }};{{ end }}")
+	assert.NoError(t, err)
+	assert.Equal(t, "devs;ops;", templated)
+
+	templated, err = tpl.TemplateToString("groups", "{{ .Groups.AsString }}")
+	assert.NoError(t, err)
+	assert.Empty(t, templated, "original templater should not be changed")
+}